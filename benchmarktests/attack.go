@@ -4,32 +4,231 @@
 package benchmarktests
 
 import (
+	"context"
+	"net/http"
+	"sync"
 	"time"
 
 	"github.com/openbao/openbao/api/v2"
 	vegeta "github.com/tsenart/vegeta/v12/lib"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func Attack(tm *TargetMulti, client *api.Client, duration time.Duration, rps int, workers int) (*Reporter, error) {
-	rate := vegeta.Rate{Freq: rps, Per: time.Second}
+// backpressureThreshold is how much later than its pacer scheduled it a
+// request must be sent to count as held back by busy workers, which allows
+// for the imprecision of sleeping until it is due
+const backpressureThreshold = 10 * time.Millisecond
+
+// attackGroup is a set of targets attacked together by a single attacker
+type attackGroup struct {
+	tm       *TargetMulti
+	duration time.Duration
+	pacer    vegeta.Pacer
+}
+
+// attackGroups splits the targets into the groups that need their own
+// attacker. Targets without overrides share the passed in duration and
+// pacer, while each target with a duration or rps override gets its own.
+func (tm TargetMulti) attackGroups(duration time.Duration, pacer vegeta.Pacer) []attackGroup {
+	shared := &TargetMulti{balancer: tm.balancer, rng: tm.rng, source: tm.source}
+	var groups []attackGroup
+	for _, target := range tm.targets {
+		if !target.hasOverrides() {
+			shared.targets = append(shared.targets, target)
+			continue
+		}
+
+		// The target is the only one in its group so it receives all requests
+		target.Weight = 100
+		group := attackGroup{
+			tm:       &TargetMulti{targets: []BenchmarkTarget{target}, balancer: tm.balancer, rng: tm.rng},
+			duration: duration,
+			pacer:    pacer,
+		}
+		if target.duration != 0 {
+			group.duration = target.duration
+		}
+		if target.RPS != 0 {
+			group.pacer = vegeta.Rate{Freq: target.RPS, Per: time.Second}
+		}
+		groups = append(groups, group)
+	}
+
+	if len(shared.targets) > 0 {
+		groups = append([]attackGroup{{tm: shared, duration: duration, pacer: pacer}}, groups...)
+	}
+	return groups
+}
+
+// backpressurePacer counts the requests an attacker sends late because all
+// of its workers are busy waiting for responses and it can't start more, in
+// which case the attacker, not the server, is limiting the request rate.
+// Attacks at an unthrottled rate are always limited by their workers, so
+// their requests are never counted.
+type backpressurePacer struct {
+	vegeta.Pacer
+
+	// due is when the last request paced was due to be sent
+	due    time.Duration
+	late   uint64
+	maxLag time.Duration
+}
+
+// Pace records how late the previous request was sent, as the attacker paces
+// the next request once it has handed the previous one to a worker
+func (p *backpressurePacer) Pace(elapsed time.Duration, hits uint64) (time.Duration, bool) {
+	if lag := elapsed - p.due; hits > 0 && lag > backpressureThreshold && p.Pacer.Rate(elapsed) > 0 {
+		p.late++
+		p.maxLag = max(p.maxLag, lag)
+	}
+	wait, stop := p.Pacer.Pace(elapsed, hits)
+	p.due = elapsed + wait
+	return wait, stop
+}
+
+// Attack runs the benchmark against the passed in client and returns the
+// collected results. Cancelling ctx stops the attack early; results for the
+// requests completed so far are still reported. When requestTimeout is
+// greater than 0, requests which take longer are cut off, and requests which
+// time out are counted separately in the report. When errorBodies is greater
+// than 0, the most frequent error response bodies of each target are
+// included in the report. Successful requests to targets which implement
+// Verifier are read back, with the checks counted in the report. Every result
+// is also passed to the consumers as it arrives. When pacer is a ClosedLoop,
+// each worker is a virtual user which waits for its response and thinks
+// before sending its next request. Otherwise the attack starts workers and
+// adds more while they are all busy, up to maxWorkers, or workers when
+// maxWorkers is less. Requests which are sent late because every worker is
+// busy are logged once the attack completes.
+func Attack(ctx context.Context, tm *TargetMulti, client *api.Client, duration time.Duration, pacer vegeta.Pacer, workers, maxWorkers int, requestTimeout time.Duration, errorBodies int, consumers ...ResultConsumer) (*Reporter, error) {
+	ctx, span := tracer.Start(ctx, "attack", trace.WithAttributes(
+		attribute.String("attack.duration", duration.String()),
+		attribute.Int("attack.workers", workers),
+		attribute.Int("attack.max_workers", max(workers, maxWorkers)),
+		attribute.String("attack.request_timeout", requestTimeout.String()),
+	))
+	defer span.End()
+
 	opts := []func(*vegeta.Attacker){
 		vegeta.Workers(uint64(workers)),
-		vegeta.MaxWorkers(uint64(workers)),
+		vegeta.MaxWorkers(uint64(max(workers, maxWorkers))),
 	}
+	var httpClient *http.Client
 	if client != nil {
-		opts = append(opts, vegeta.Client(client.CloneConfig().HttpClient))
+		httpClient = client.CloneConfig().HttpClient
+		span.SetAttributes(attribute.String("vault.address", client.Address()))
+	}
+	if requestTimeout > 0 {
+		// The client is copied so that the timeout only applies to the
+		// benchmark requests and not to requests made by the setup client
+		timeoutClient := &http.Client{}
+		if httpClient != nil {
+			*timeoutClient = *httpClient
+		}
+		timeoutClient.Timeout = requestTimeout
+		httpClient = timeoutClient
+	}
+	httpClient = discardBodies(httpClient, tm.targets)
+	if httpClient != nil {
+		opts = append(opts, vegeta.Client(httpClient))
+	}
+
+	groups := tm.attackGroups(duration, pacer)
+	targeters := make([]vegeta.Targeter, len(groups))
+	for i, group := range groups {
+		targeter, err := group.tm.Targeter(client)
+		if err != nil {
+			return nil, err
+		}
+		targeters[i] = targeter
 	}
-	attacker := vegeta.NewAttacker(opts...)
 
-	targeter, err := tm.Targeter(client)
-	if err != nil {
-		return nil, err
+	wg := new(sync.WaitGroup)
+	results := make(chan *vegeta.Result)
+	attackers := make([]interface{ Stop() }, len(groups))
+	var pacers []*backpressurePacer
+	for i, group := range groups {
+		var res <-chan *vegeta.Result
+		if closedLoop, ok := group.pacer.(ClosedLoop); ok {
+			attacker := newClosedLoopAttacker(httpClient, workers)
+			attackers[i] = attacker
+			res = attacker.Attack(targeters[i], closedLoop, group.duration, "Big Bang!")
+		} else {
+			attacker := vegeta.NewAttacker(opts...)
+			attackers[i] = attacker
+			backpressure := &backpressurePacer{Pacer: group.pacer}
+			pacers = append(pacers, backpressure)
+			res = attacker.Attack(targeters[i], backpressure, group.duration, "Big Bang!")
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range res {
+				results <- r
+			}
+		}()
 	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(results)
+		close(done)
+	}()
+
+	// Stop all attackers if the context is cancelled before they finish
+	go func() {
+		select {
+		case <-ctx.Done():
+			for _, attacker := range attackers {
+				attacker.Stop()
+			}
+		case <-done:
+		}
+	}()
+
 	rpt := newReporter(tm, client)
-	for res := range attacker.Attack(targeter, rate, duration, "Big Bang!") {
-		rpt.Add(res)
+	rpt.errorBodies = errorBodies
+	verify := newVerifyRunner(httpClient, workers)
+	for res := range results {
+		restoreBytesIn(res)
+		target := rpt.match(res)
+		target.classify(res)
+		rpt.add(target, res)
+		verify.run(target, res)
+		if len(consumers) == 0 {
+			continue
+		}
+
+		var name string
+		if target != nil {
+			name = target.Name
+		}
+		failed := res.Error != "" || !target.succeeded(res)
+		for _, consumer := range consumers {
+			consumer.Consume(name, res, failed)
+		}
 	}
+	rpt.verifications = verify.wait()
 	rpt.Close()
+	tm.attackFinished()
+
+	var late uint64
+	var maxLag time.Duration
+	for _, backpressure := range pacers {
+		late += backpressure.late
+		maxLag = max(maxLag, backpressure.maxLag)
+	}
+	if late > 0 {
+		targetLogger.Warn("requests were sent late because every worker was busy, increase workers or max_workers so that the benchmark tool doesn't limit the request rate",
+			"late", late, "max_delay", maxLag.String())
+	}
 
+	total := rpt.metrics["total"]
+	span.SetAttributes(
+		attribute.Int64("attack.requests", int64(total.Requests)),
+		attribute.Float64("attack.success_ratio", total.Success),
+		attribute.Int64("attack.late_requests", int64(late)),
+	)
 	return rpt, nil
 }
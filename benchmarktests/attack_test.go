@@ -0,0 +1,31 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"testing"
+	"time"
+
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+func TestBackpressurePacer(t *testing.T) {
+	// Requests are due every 10ms. The second is sent on time, while the
+	// third is held back by busy workers until 50ms after it was due.
+	p := &backpressurePacer{Pacer: vegeta.Rate{Freq: 100, Per: time.Second}}
+	wait, _ := p.Pace(0, 0)
+	wait, _ = p.Pace(wait, 1)
+	p.Pace(10*time.Millisecond+wait+50*time.Millisecond, 2)
+	if p.late != 1 || p.maxLag != 50*time.Millisecond {
+		t.Fatalf("expected 1 request 50ms late, got %d up to %v", p.late, p.maxLag)
+	}
+
+	// Unthrottled attacks are always limited by their workers
+	p = &backpressurePacer{Pacer: vegeta.Rate{Freq: 0, Per: time.Second}}
+	p.Pace(0, 0)
+	p.Pace(time.Second, 1)
+	if p.late != 0 {
+		t.Fatalf("expected no late requests at an unthrottled rate, got %d", p.late)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"fmt"
+
+	"github.com/openbao/openbao/api/v2"
+)
+
+// auditDevicePath is the path the benchmark's audit device is enabled at
+const auditDevicePath = "bench-audit"
+
+// AuditDevice is an audit device enabled for the duration of the attack, so
+// that the cost of auditing can be measured for any test
+type AuditDevice struct {
+	// Type is the type of the audit device, such as file or socket
+	Type string
+
+	// Options are the options of the audit device, such as file_path
+	Options map[string]string
+}
+
+// enableAudit enables the audit device
+func enableAudit(client *api.Client, device *AuditDevice, config *TopLevelTargetConfig) error {
+	targetLogger.Debug("enabling audit device", "type", device.Type, "path", auditDevicePath)
+	err := retrySetup(config, func() error {
+		return client.Sys().EnableAuditWithOptions(auditDevicePath, &api.EnableAuditOptions{
+			Type:    device.Type,
+			Options: device.Options,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("error enabling %v audit device: %v", device.Type, err)
+	}
+	return nil
+}
+
+// disableAudit disables the audit device
+func disableAudit(client *api.Client) error {
+	targetLogger.Debug("disabling audit device", "path", auditDevicePath)
+	if err := client.Sys().DisableAudit(auditDevicePath); err != nil {
+		return fmt.Errorf("error disabling audit device: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"context"
+	"sync"
+)
+
+// background runs the work a test does in the background while it is
+// attacked, such as preparing the requests the attack sends. The work is
+// started by the first request of an attack, stopped once the attack has
+// finished, and started again by the next attack.
+type background struct {
+	mu     sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// running starts fn in a goroutine unless it is already running, and returns
+// the context it runs with, which is cancelled once it is stopped
+func (b *background) running(fn func(ctx context.Context)) context.Context {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cancel == nil {
+		b.ctx, b.cancel = context.WithCancel(context.Background())
+		go fn(b.ctx)
+	}
+	return b.ctx
+}
+
+// stop stops the work if it is running
+func (b *background) stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cancel != nil {
+		b.cancel()
+		b.cancel = nil
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBackground(t *testing.T) {
+	var b background
+	var starts atomic.Int64
+	work := func(ctx context.Context) {
+		starts.Add(1)
+		<-ctx.Done()
+	}
+
+	// The work is only started once while it runs
+	ctx := b.running(work)
+	if b.running(work) != ctx {
+		t.Fatal("expected the running work's context")
+	}
+
+	// Stopping it cancels its context, and it is started again afterwards
+	b.stop()
+	if ctx.Err() == nil {
+		t.Fatal("expected the context to be cancelled")
+	}
+	next := b.running(work)
+	if next == ctx || next.Err() != nil {
+		t.Fatal("expected the work to be started again")
+	}
+	b.stop()
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	LoadBalanceRoundRobin = "round_robin"
+	LoadBalanceRandom     = "random"
+)
+
+// addressBalancer spreads requests across several Vault addresses, as a
+// fleet of clients talking to the nodes of a cluster directly would
+type addressBalancer struct {
+	addrs []string
+	next  atomic.Uint64
+
+	// rng picks the address of each request when balancing at random
+	rng *rand.Rand
+}
+
+// pick returns the address the next request is sent to
+func (b *addressBalancer) pick() string {
+	if b.rng != nil {
+		return b.addrs[b.rng.Intn(len(b.addrs))]
+	}
+	return b.addrs[(b.next.Add(1)-1)%uint64(len(b.addrs))]
+}
+
+// rewrite replaces the from address of url with the next address
+func (b *addressBalancer) rewrite(url, from string) string {
+	if !strings.HasPrefix(url, from) {
+		return url
+	}
+	return b.pick() + url[len(from):]
+}
+
+// trimAddress returns url without the address it was sent to, or false if
+// it wasn't sent to any of the balanced addresses
+func (b *addressBalancer) trimAddress(url string) (string, bool) {
+	for _, addr := range b.addrs {
+		if strings.HasPrefix(url, addr) {
+			return url[len(addr):], true
+		}
+	}
+	return "", false
+}
+
+// LoadBalance spreads the requests of each attack across the passed in
+// addresses rather than sending them all to the attacking client's address.
+// mode is one of LoadBalanceRoundRobin or LoadBalanceRandom.
+func (tm *TargetMulti) LoadBalance(addrs []string, mode string) error {
+	if len(addrs) == 0 {
+		return fmt.Errorf("no addresses to balance requests across")
+	}
+
+	b := &addressBalancer{}
+	switch mode {
+	case LoadBalanceRoundRobin:
+	case LoadBalanceRandom:
+		b.rng = tm.rng
+		if b.rng == nil {
+			b.rng = NewRand(time.Now().UnixNano())
+		}
+	default:
+		return fmt.Errorf("load balancing mode must be one of %v or %v", LoadBalanceRoundRobin, LoadBalanceRandom)
+	}
+	for _, addr := range addrs {
+		b.addrs = append(b.addrs, strings.TrimSuffix(addr, "/"))
+	}
+	tm.balancer = b
+	return nil
+}
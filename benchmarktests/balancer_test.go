@@ -0,0 +1,61 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"testing"
+
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+func TestTargetMulti_LoadBalance(t *testing.T) {
+	config := api.DefaultConfig()
+	config.Address = "http://node1:8200"
+	client, err := api.NewClient(config)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	tm := &TargetMulti{targets: []BenchmarkTarget{{
+		Name:       "status",
+		Method:     "GET",
+		PathPrefix: "/v1/sys/seal-status",
+		Weight:     100,
+		Builder:    &StatusCheck{pathPrefix: "/v1/sys/seal-status"},
+	}}}
+	tm.targets[0].Target = tm.targets[0].Builder.Target
+
+	addrs := []string{"http://node1:8200", "http://node2:8200/", "http://node3:8200"}
+	if err := tm.LoadBalance(addrs, LoadBalanceRoundRobin); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	targeter, err := tm.Targeter(client)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	rpt := newReporter(tm, client)
+	for i := 0; i < 6; i++ {
+		var tgt vegeta.Target
+		if err := targeter(&tgt); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		expected := tm.balancer.addrs[i%3] + "/v1/sys/seal-status"
+		if tgt.URL != expected {
+			t.Fatalf("expected request %d to be sent to %v, got: %v", i, expected, tgt.URL)
+		}
+		rpt.Add(&vegeta.Result{Method: tgt.Method, URL: tgt.URL, Code: 200})
+	}
+	rpt.Close()
+
+	if n := rpt.metrics["status"].Requests; n != 6 {
+		t.Fatalf("expected results from every address to be attributed to the target, got: %d", n)
+	}
+
+	if err := tm.LoadBalance(addrs, "least_conn"); err == nil {
+		t.Fatal("expected error for unknown mode")
+	}
+}
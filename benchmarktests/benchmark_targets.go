@@ -4,26 +4,74 @@
 package benchmarktests
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"math/rand"
-	"os"
+	"net/http"
+	"slices"
 	"sort"
-	"sync"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/openbao/openbao/api/v2"
 	vegeta "github.com/tsenart/vegeta/v12/lib"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Configuration that applies to all individual tests
 type TopLevelTargetConfig struct {
 	Duration     time.Duration
 	RandomMounts bool
+
+	// SetupRetries is the number of times transient errors are retried
+	// while setting up a test
+	SetupRetries int
+
+	// SkipSetup reuses the mounts and data set up by a previous run instead
+	// of setting them up again. Only tests implementing SetupReuser support
+	// it, and mount names must be deterministic.
+	SkipSetup bool
+
+	// Namespaces, if set, are child namespaces of the setup client's
+	// namespace. Every test is set up in each of them and requests are
+	// spread across them at random.
+	Namespaces []string
+
+	// Audit, if set, is an audit device enabled once every test has been set
+	// up and disabled with DisableAudit, so that the attack is audited but
+	// setup and cleanup aren't
+	Audit *AuditDevice
+
+	// Addrs are the addresses of every node of the cluster being
+	// benchmarked, for tests which send requests to particular nodes
+	Addrs []string
+
+	// Workers is the most requests the attack of each address has in flight
+	// at once
+	Workers int
+
+	// Rand is the source of randomness for the requests sent by tests. It
+	// is safe for concurrent use, and runs with the same seed send the same
+	// sequence of requests.
+	Rand *rand.Rand
+
+	// ctx is the context setup was started with. Setup stops making
+	// requests once it is cancelled.
+	ctx context.Context
+}
+
+// interrupted returns true if the context setup was started with has been
+// cancelled
+func (c *TopLevelTargetConfig) interrupted() bool {
+	return c.ctx != nil && c.ctx.Err() != nil
 }
 
 const (
@@ -36,7 +84,10 @@ type BenchmarkBuilder interface {
 
 	// Setup uses the passed in client and configuration to create the necessary test resources
 	// in Vault, and retrieve any necessary information needed to perform the test itself. Setup
-	// returns a test struct type which satisfies this BenchmarkBuilder interface.
+	// returns a test struct type which satisfies this BenchmarkBuilder interface. If a check
+	// finds the server doesn't support the test after resources were created, Setup returns
+	// the test along with an error wrapping errUnsupported, so that they are cleaned up when
+	// the test is skipped.
 	Setup(client *api.Client, mountName string, config *TopLevelTargetConfig) (BenchmarkBuilder, error)
 
 	// Cleanup uses the passed in client to clean up any created resources used as part of the test
@@ -52,6 +103,30 @@ type BenchmarkBuilder interface {
 	Flags(fs *flag.FlagSet)
 }
 
+// SetupReuser is implemented by tests whose Setup can reuse the mount and data
+// set up by a previous run when TopLevelTargetConfig.SkipSetup is set, rather
+// than creating them again
+type SetupReuser interface {
+	// ReusesSetup reports whether Setup honours SkipSetup
+	ReusesSetup() bool
+}
+
+// SetupConsumer is implemented by tests whose requests use up the resources
+// created during setup, such as leases which are revoked, so that repeating
+// the attack without setting the test up again would skew its results
+type SetupConsumer interface {
+	// ConsumesSetup reports whether requests use up what Setup created
+	ConsumesSetup() bool
+}
+
+// AttackFinisher is implemented by tests which need to know when an attack
+// has finished, such as to stop work they do in the background while they
+// are attacked. Runs may attack the same tests several times.
+type AttackFinisher interface {
+	// AttackFinished is called once every request of an attack has completed
+	AttackFinished()
+}
+
 var (
 	TestList     = make(map[string]func() BenchmarkBuilder)
 	targetLogger hclog.Logger
@@ -66,7 +141,55 @@ type BenchmarkTarget struct {
 	MountName  string   `hcl:"mount_name,optional"`
 	Method     string
 	PathPrefix string
-	Weight     int `hcl:"weight,optional"`
+	Weight     int    `hcl:"weight,optional"`
+	Duration   string `hcl:"duration,optional"`
+	RPS        int    `hcl:"rps,optional"`
+
+	// SLO are assertions about the target's metrics, such as "p99 < 50ms",
+	// which fail the run if they don't hold
+	SLO []string `hcl:"slo,optional"`
+
+	// ScopedToken sends the target's requests with a token which is only
+	// allowed to send them, rather than the token used to set it up
+	ScopedToken bool `hcl:"scoped_token,optional"`
+
+	// Headers are sent with every request made to the target, and with the
+	// requests made while setting it up
+	Headers map[string]string `hcl:"headers,optional"`
+
+	// SkipCleanup keeps the resources created by the target when the others
+	// are cleaned up, so that they can be inspected after the run
+	SkipCleanup bool `hcl:"skip_cleanup,optional"`
+
+	// Tags label the target so that a subset of a suite can be run
+	Tags []string `hcl:"tags,optional"`
+
+	// DependsOn names the targets which must be set up before this one,
+	// such as a test creating a policy used by a login test
+	DependsOn []string `hcl:"depends_on,optional"`
+
+	// SuccessStatusCodes are the status codes the target's requests succeed
+	// with, such as 404 for reads of deleted secrets. Requests succeed with
+	// any 2xx or 3xx status code when it is empty.
+	SuccessStatusCodes []int `hcl:"success_status_codes,optional"`
+
+	// QueryParams are added to the URL of every request made to the target,
+	// such as version = "2" to read an older version of a secret
+	QueryParams map[string]string `hcl:"query_params,optional"`
+
+	// SegmentPrefix only matches the target's PathPrefix at the end of a
+	// path segment, so that /v1/sys/health doesn't match /v1/sys/healthz
+	SegmentPrefix bool
+
+	// duration is the parsed per-target Duration override
+	duration time.Duration
+
+	// scopedToken is created during setup when ScopedToken is set
+	scopedToken *scopedToken
+
+	// interrupted is set when setup was interrupted after the target
+	// created resources, which are then cleaned up with the other targets
+	interrupted bool
 }
 
 type TargetInfo struct {
@@ -74,8 +197,34 @@ type TargetInfo struct {
 	pathPrefix string
 }
 
+// matches returns true if a request with the method and path, which may
+// include a query, was made to the target
+func (bt *BenchmarkTarget) matches(method, path string) bool {
+	if method != bt.Method || !strings.HasPrefix(path, bt.PathPrefix) {
+		return false
+	}
+	if !bt.SegmentPrefix || len(path) == len(bt.PathPrefix) {
+		return true
+	}
+	next := path[len(bt.PathPrefix)]
+	return next == '/' || next == '?'
+}
+
+// hasOverrides returns true if the target overrides the global duration or
+// rate, in which case it is attacked on its own rather than sharing the
+// weighted attacker with the other targets.
+func (bt *BenchmarkTarget) hasOverrides() bool {
+	return bt.Duration != "" || bt.RPS != 0
+}
+
 func (bt *BenchmarkTarget) ConfigureTarget(client *api.Client) {
 	bt.Target = bt.Builder.Target
+	if bt.scopedToken != nil {
+		bt.Target = bt.scopedToken.target(bt.Builder.Target)
+	}
+	if len(bt.QueryParams) > 0 {
+		bt.Target = withQueryParams(bt.Target, bt.QueryParams)
+	}
 	tInfo := bt.Builder.GetTargetInfo()
 	bt.PathPrefix = tInfo.pathPrefix
 	bt.Method = tInfo.method
@@ -85,6 +234,22 @@ func (bt *BenchmarkTarget) ConfigureTarget(client *api.Client) {
 // operations randomly following a specified distribution.
 type TargetMulti struct {
 	targets []BenchmarkTarget
+
+	// namespaces created during setup which are removed during cleanup
+	namespaces []string
+
+	// audit is true if the audit device was enabled during setup
+	audit bool
+
+	// balancer spreads requests across several addresses when set
+	balancer *addressBalancer
+
+	// rng chooses which target each request is sent to
+	rng *rand.Rand
+
+	// source generates every request in place of choosing between the
+	// targets when they weren't set up from tests
+	source TargetSource
 }
 
 func (tm TargetMulti) choose(i int) *BenchmarkTarget {
@@ -104,54 +269,150 @@ func (tm TargetMulti) choose(i int) *BenchmarkTarget {
 	return nil
 }
 
-func (tm TargetMulti) Cleanup(client *api.Client) error {
+// DisableAudit disables the audit device enabled during setup, if it is
+// still enabled. Unlike the targets, the device is disabled whether or not
+// they are cleaned up, so that it isn't left auditing the cluster's traffic.
+func (tm *TargetMulti) DisableAudit(client *api.Client) error {
+	if !tm.audit {
+		return nil
+	}
+	if err := disableAudit(client); err != nil {
+		return err
+	}
+	tm.audit = false
+	return nil
+}
+
+// Cleanup runs Cleanup for every target, returning the combined errors of
+// any that failed
+func (tm TargetMulti) Cleanup(ctx context.Context, client *api.Client) (retErr error) {
 	type CleanupMsg struct {
 		err        error
 		targetName string
 	}
 
-	wg := new(sync.WaitGroup)
+	// Nothing is set up for the targets of a source
+	if tm.source != nil {
+		return nil
+	}
+
+	ctx, span := tracer.Start(ctx, "cleanup")
+	defer func() { endSpan(span, retErr) }()
+
 	errch := make(chan CleanupMsg)
-	var errCount int
+	var errs []error
 
-	for _, target := range tm.targets {
-		target := target
-		wg.Add(1)
-		targetLogger.Debug("cleaning up", "target", target.Name)
-		go func() {
-			defer wg.Done()
-			errch <- CleanupMsg{
-				err:        target.Builder.Cleanup(client),
-				targetName: target.Name,
-			}
-		}()
+	// The audit device is disabled first so that cleanup isn't audited
+	if tm.audit {
+		if err := disableAudit(client); err != nil {
+			errs = append(errs, err)
+			targetLogger.Error("error disabling audit device", "error", err.Error())
+		}
 	}
 
-	for i := 0; i < len(tm.targets); i++ {
-		cleanupMsg := <-errch
-		if cleanupMsg.err != nil {
-			errCount++
-			targetLogger.Error("error cleaning up", "target", cleanupMsg.targetName, "error", cleanupMsg.err.Error())
-		} else {
-			targetLogger.Trace("done cleaning up", "target", cleanupMsg.targetName)
+	// Targets are cleaned up concurrently, except that targets which depend
+	// on others are cleaned up first
+	var skipped bool
+	for _, wave := range cleanupWaves(tm.targets) {
+		var pending int
+		for _, i := range wave {
+			target := tm.targets[i]
+			if target.SkipCleanup {
+				targetLogger.Info("skipping cleanup, resources are kept for inspection", "target", target.Name)
+				skipped = true
+				continue
+			}
+			pending++
+			targetLogger.Debug("cleaning up", "target", target.Name)
+			go func() {
+				var err error
+				_, targetSpan := tracer.Start(ctx, "cleanup "+target.Name, trace.WithAttributes(targetAttributes(&target)...))
+				defer func() {
+					if r := recover(); r != nil {
+						err = fmt.Errorf("panic during cleanup: %v", r)
+					}
+					endSpan(targetSpan, err)
+					errch <- CleanupMsg{
+						err:        err,
+						targetName: target.Name,
+					}
+				}()
+				err = target.Builder.Cleanup(client)
+				if target.scopedToken != nil {
+					err = errors.Join(err, target.scopedToken.cleanup(client))
+				}
+			}()
+		}
+
+		for i := 0; i < pending; i++ {
+			cleanupMsg := <-errch
+			if cleanupMsg.err != nil {
+				errs = append(errs, fmt.Errorf("%v: %w", cleanupMsg.targetName, cleanupMsg.err))
+				targetLogger.Error("error cleaning up", "target", cleanupMsg.targetName, "error", cleanupMsg.err.Error())
+			} else {
+				targetLogger.Trace("done cleaning up", "target", cleanupMsg.targetName)
+			}
 		}
 	}
-	return nil
+
+	// Namespaces are removed once the mounts inside them have been cleaned
+	// up, and kept along with the targets which skip cleanup
+	if skipped {
+		targetLogger.Info("skipping cleanup of namespaces", "namespaces", tm.namespaces)
+	} else if err := cleanupNamespaces(client, tm.namespaces); err != nil {
+		errs = append(errs, err)
+		targetLogger.Error("error cleaning up namespaces", "error", err.Error())
+	}
+	return errors.Join(errs...)
 }
 
 func (tm TargetMulti) Targeter(client *api.Client) (vegeta.Targeter, error) {
-	return func(tgt *vegeta.Target) error {
+	rng := tm.rng
+	if rng == nil {
+		rng = NewRand(time.Now().UnixNano())
+	}
+	// vegeta calls the targeter from its own workers, so a panicking Target
+	// is returned as an error, which stops the attack, rather than ending
+	// the process before the targets are cleaned up
+	return func(tgt *vegeta.Target) (err error) {
 		if tgt == nil {
 			return vegeta.ErrNilTarget
 		}
-		rnd := int(rand.Int31n(100))
-		t := tm.choose(rnd)
-		*tgt = t.Target(client)
+		defer func() {
+			if rec := recover(); rec != nil {
+				targetLogger.Error("target panicked", "panic", fmt.Sprintf("%v", rec))
+				err = fmt.Errorf("target panicked: %v", rec)
+			}
+		}()
+		if tm.source != nil {
+			if err := tm.source.Next(client, tgt); err != nil {
+				return err
+			}
+		} else {
+			rnd := int(rng.Int31n(100))
+			t := tm.choose(rnd)
+			*tgt = t.Target(client)
+		}
+		if tm.balancer != nil {
+			tgt.URL = tm.balancer.rewrite(tgt.URL, client.Address())
+		}
 		return nil
 	}, nil
 }
 
-func (tm TargetMulti) DebugInfo(client *api.Client) {
+// attackFinished lets the targets which need to know that an attack has
+// finished
+func (tm TargetMulti) attackFinished() {
+	for i := range tm.targets {
+		if finisher, ok := tm.targets[i].Builder.(AttackFinisher); ok {
+			finisher.AttackFinished()
+		}
+	}
+}
+
+// DebugInfo sends one request of each target and logs the requests and
+// responses, returning an error if any of them fail
+func (tm TargetMulti) DebugInfo(client *api.Client) error {
 	debugInfoHeader := "\n=== Debug Info ===\n"
 	debugInfoHeader += fmt.Sprintf("Client: %s\n", client.Address())
 	debugInfoFooter := "==================\n"
@@ -163,59 +424,138 @@ func (tm TargetMulti) DebugInfo(client *api.Client) {
 		target := benchTarget.Target(client)
 		req, err := target.Request()
 		if err != nil {
-			targetLogger.Error(fmt.Sprintf("Got err building target: %v", err))
-			os.Exit(1)
+			return fmt.Errorf("error building target %v: %w", benchTarget.Name, err)
 		}
 		targetLogger.Debug(targetDebugInfo + fmt.Sprintf("Request: %v\n", req.URL.String()) + debugInfoFooter)
 
 		resp, err := client.CloneConfig().HttpClient.Do(req)
 		if err != nil {
-			targetLogger.Error(fmt.Sprintf("Got err executing target request: %v", err))
-			os.Exit(1)
+			return fmt.Errorf("error executing target %v request: %w", benchTarget.Name, err)
 		}
 		rawBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
 		if err != nil {
-			targetLogger.Debug(fmt.Sprintf("Got err reading response body: %v", err))
-			os.Exit(1)
+			return fmt.Errorf("error reading target %v response body: %w", benchTarget.Name, err)
 		}
 		targetLogger.Debug(targetDebugInfo + fmt.Sprintf("Response: %v\n", resp.Status) +
 			fmt.Sprintf("Response Body: %v", string(rawBody)) + debugInfoFooter)
 		if resp.StatusCode >= 400 {
-			targetLogger.Debug("Got error response from server on testing request; exiting")
-			os.Exit(1)
+			return fmt.Errorf("got error response from server on target %v testing request: %v", benchTarget.Name, resp.Status)
 		}
 	}
+	return nil
 }
 
-func BuildTargets(client *api.Client, tests []*BenchmarkTarget, logger *hclog.Logger, config *TopLevelTargetConfig) (*TargetMulti, error) {
+func BuildTargets(ctx context.Context, client *api.Client, tests []*BenchmarkTarget, logger *hclog.Logger, config *TopLevelTargetConfig) (*TargetMulti, error) {
 	var tm TargetMulti
 	var err error
 	targetLogger = *logger
 
-	// Check to make sure all weights add to 100
-	err = percentageValidate(tests)
+	if config.Rand == nil {
+		config.Rand = NewRand(time.Now().UnixNano())
+	}
+	tm.rng = config.Rand
+
+	ctx, span := tracer.Start(ctx, "setup")
+	defer func() { endSpan(span, err) }()
+	config.ctx = ctx
+
+	err = validateTargets(tests)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build tests
+	if config.SkipSetup {
+		if config.RandomMounts {
+			err = fmt.Errorf("skip_setup can't be used with random_mounts")
+			return nil, err
+		}
+		for _, bvTest := range tests {
+			if reuser, ok := bvTest.Builder.(SetupReuser); !ok || !reuser.ReusesSetup() {
+				err = fmt.Errorf("target %v: %v tests don't support skip_setup", bvTest.Name, bvTest.Type)
+				return nil, err
+			}
+		}
+	}
+
+	if len(config.Namespaces) > 0 {
+		for _, bvTest := range tests {
+			if bvTest.ScopedToken {
+				err = fmt.Errorf("target %v: scoped_token can't be used with namespaces", bvTest.Name)
+				return nil, err
+			}
+		}
+
+		targetLogger.Debug("setting up namespaces", "count", len(config.Namespaces))
+		tm.namespaces, err = setupNamespaces(client, config.Namespaces)
+		if err != nil {
+			return &tm, err
+		}
+	}
+
+	// Build tests in order, so that the targets each depends on are set up
+	// first. On failure the targets which were already set up are still
+	// returned so that the caller can clean them up.
+	var skipped int
+	unsupported := make(map[string]bool)
 	for _, bvTest := range tests {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("setup interrupted: %w", ctx.Err())
+			return &tm, err
+		}
+		if dep := slices.IndexFunc(bvTest.DependsOn, func(dep string) bool { return unsupported[dep] }); dep >= 0 {
+			targetLogger.Warn("skipping target which depends on a skipped target", "target", bvTest.Name, "depends_on", bvTest.DependsOn[dep])
+			unsupported[bvTest.Name] = true
+			skipped++
+			continue
+		}
+
 		targetLogger.Debug("setting up target", "target", hclog.Fmt("%v", bvTest.Name))
 		mountName := bvTest.Name
 		if bvTest.MountName != "" {
 			mountName = bvTest.MountName
 		}
-		bvTest.Builder, err = bvTest.Builder.Setup(client, mountName, config)
+		err = bvTest.setup(ctx, client, mountName, config)
+		if err != nil && isUnsupported(err) {
+			// Let suites run against servers which don't include every
+			// feature, rather than failing the whole run
+			targetLogger.Warn("skipping target unsupported by the server", "target", bvTest.Name, "error", err.Error())
+			unsupported[bvTest.Name] = true
+			skipped++
+			err = nil
+			continue
+		}
 		if err != nil {
-			// TODO:
-			// We should look to implement some mechanism to clean up the mount if we
-			// fail to configure some aspect of it (config, role, etc.)
-			return nil, err
+			if bvTest.interrupted {
+				tm.targets = append(tm.targets, *bvTest)
+			}
+			err = fmt.Errorf("error setting up target %v: %w", bvTest.Name, err)
+			return &tm, err
 		}
 		bvTest.ConfigureTarget(client)
 		tm.targets = append(tm.targets, *bvTest)
 	}
 
+	if skipped > 0 {
+		if len(tm.targets) == 0 {
+			err = fmt.Errorf("all targets were skipped as unsupported by the server")
+			return &tm, err
+		}
+		targets := make([]*BenchmarkTarget, len(tm.targets))
+		for i := range tm.targets {
+			targets[i] = &tm.targets[i]
+		}
+		RebalanceWeights(targets)
+	}
+
+	if config.Audit != nil {
+		err = enableAudit(client, config.Audit, config)
+		if err != nil {
+			return &tm, err
+		}
+		tm.audit = true
+	}
+
 	// Put the biggest fractions first as an optimization
 	sort.Slice(tm.targets, func(i, j int) bool {
 		return tm.targets[j].Weight < tm.targets[i].Weight
@@ -224,12 +564,200 @@ func BuildTargets(client *api.Client, tests []*BenchmarkTarget, logger *hclog.Lo
 	return &tm, nil
 }
 
+// validateTargets checks the target definitions before any resources are
+// created, parsing any per-target duration overrides
+func validateTargets(tests []*BenchmarkTarget) error {
+	// Check to make sure all weights add to 100
+	err := percentageValidate(tests)
+	if err != nil {
+		return err
+	}
+
+	if _, err := OrderTargets(tests); err != nil {
+		return err
+	}
+
+	for _, bvTest := range tests {
+		for name := range bvTest.Headers {
+			switch http.CanonicalHeaderKey(name) {
+			case "X-Vault-Token", "X-Vault-Namespace":
+				return fmt.Errorf("headers for target %v can't set %v", bvTest.Name, name)
+			}
+		}
+
+		for name := range bvTest.QueryParams {
+			if name == "" {
+				return fmt.Errorf("query_params for target %v has an empty parameter name", bvTest.Name)
+			}
+		}
+
+		for _, code := range bvTest.SuccessStatusCodes {
+			if code < 100 || code > 599 {
+				return fmt.Errorf("success_status_codes for target %v has invalid status code %v", bvTest.Name, code)
+			}
+		}
+
+		if bvTest.Duration == "" {
+			continue
+		}
+		bvTest.duration, err = time.ParseDuration(bvTest.Duration)
+		if err != nil {
+			return fmt.Errorf("error parsing duration for target %v: %v", bvTest.Name, err)
+		}
+	}
+	return nil
+}
+
+// DescribeTargets validates the passed in tests without contacting Vault and
+// writes a summary of the targets that would be attacked to w. Paths which are
+// only known once a target has been set up, such as random mount paths, are
+// reported as such.
+func DescribeTargets(w io.Writer, tests []*BenchmarkTarget) error {
+	if err := validateTargets(tests); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "name\ttype\tweight\tmethod\tpath\n")
+	for _, bvTest := range tests {
+		tInfo := bvTest.Builder.GetTargetInfo()
+		path := tInfo.pathPrefix
+		if !strings.HasPrefix(path, "/v1/") {
+			path = "(determined during setup)"
+		}
+		weight := strconv.Itoa(bvTest.Weight)
+		if bvTest.hasOverrides() {
+			weight = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", bvTest.Name, bvTest.Type, weight, tInfo.method, path)
+	}
+	return tw.Flush()
+}
+
+// cleanupSkipped removes what a test created before finding out the server
+// doesn't support it
+func cleanupSkipped(client *api.Client, name string, builder BenchmarkBuilder) {
+	targetLogger.Debug("cleaning up skipped target", "target", name)
+	if err := builder.Cleanup(client); err != nil {
+		targetLogger.Warn("error cleaning up skipped target", "target", name, "error", err.Error())
+	}
+}
+
+// setup runs the builder's Setup, converting any panic into an error
+func (bt *BenchmarkTarget) setup(ctx context.Context, client *api.Client, mountName string, config *TopLevelTargetConfig) (err error) {
+	_, span := tracer.Start(ctx, "setup "+bt.Name, trace.WithAttributes(targetAttributes(bt)...))
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic during setup: %v", r)
+		}
+		endSpan(span, err)
+	}()
+
+	// Tests send the headers of the client they are set up with
+	if len(bt.Headers) > 0 {
+		client, err = clientWithHeaders(client, bt.Headers)
+		if err != nil {
+			return fmt.Errorf("error creating client with headers: %v", err)
+		}
+	}
+
+	var builder BenchmarkBuilder
+	if len(config.Namespaces) > 0 {
+		builder, err = bt.setupNamespaced(client, mountName, config)
+	} else {
+		builder, err = bt.Builder.Setup(client, mountName, config)
+	}
+	if err != nil {
+		switch {
+		case builder != nil && isUnsupported(err):
+			cleanupSkipped(client, bt.Name, builder)
+		case builder != nil && config.interrupted():
+			// Tests interrupted while seeding return what they created
+			// so far, which is cleaned up like any other target
+			bt.Builder = builder
+			bt.interrupted = true
+		}
+		return err
+	}
+	bt.Builder = builder
+
+	if bt.ScopedToken {
+		if err := bt.setupScopedToken(client, config); err != nil {
+			// The target isn't returned for cleanup, so clean it up here
+			_ = builder.Cleanup(client)
+			return err
+		}
+	}
+	return nil
+}
+
+// clientWithHeaders returns a copy of client which sends the passed in headers
+// along with the client's own
+func clientWithHeaders(client *api.Client, headers map[string]string) (*api.Client, error) {
+	c, err := client.CloneWithHeaders()
+	if err != nil {
+		return nil, err
+	}
+	c.SetToken(client.Token())
+
+	header := c.Headers()
+	if header == nil {
+		header = make(http.Header)
+	}
+	for name, value := range headers {
+		header.Set(name, value)
+	}
+	c.SetHeaders(header)
+	return c, nil
+}
+
+// RebalanceWeights scales the weights of the targets sharing the global
+// attacker back up to 100 after others were removed, keeping their
+// proportions. Any remainder from rounding goes to the heaviest target.
+func RebalanceWeights(targets []*BenchmarkTarget) {
+	var shared []*BenchmarkTarget
+	total := 0
+	for _, target := range targets {
+		if target.hasOverrides() {
+			continue
+		}
+		shared = append(shared, target)
+		total += target.Weight
+	}
+	if len(shared) == 0 || total == 100 {
+		return
+	}
+
+	heaviest := shared[0]
+	sum := 0
+	for _, target := range shared {
+		if total == 0 {
+			target.Weight = 100 / len(shared)
+		} else {
+			target.Weight = target.Weight * 100 / total
+		}
+		sum += target.Weight
+		if target.Weight > heaviest.Weight {
+			heaviest = target
+		}
+	}
+	heaviest.Weight += 100 - sum
+}
+
+// percentageValidate checks that the weights of the targets sharing the
+// global attacker add up to 100. Targets with a duration or rps override are
+// attacked on their own and their weight is ignored.
 func percentageValidate(tests []*BenchmarkTarget) error {
 	total := 0
+	shared := 0
 	for _, bvTest := range tests {
+		if bvTest.hasOverrides() {
+			continue
+		}
+		shared++
 		total += bvTest.Weight
 	}
-	if total != 100 {
+	if total != 100 && (shared > 0 || len(tests) == 0) {
 		return fmt.Errorf("test percentage total comes to %d, should be 100", total)
 	}
 	return nil
@@ -0,0 +1,295 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+// fakeBuilder is a BenchmarkBuilder whose Setup and Cleanup behaviour can be
+// controlled by tests
+type fakeBuilder struct {
+	setupErr    error
+	setupPanic  bool
+	targetPanic bool
+	cleanedUp   bool
+
+	// partial returns the builder along with setupErr, as tests do when
+	// they find out they're unsupported after creating resources
+	partial bool
+
+	// cancel, if set, is called during Setup to interrupt it
+	cancel context.CancelFunc
+}
+
+func (f *fakeBuilder) Target(client *api.Client) vegeta.Target {
+	if f.targetPanic {
+		panic("target failed")
+	}
+	return vegeta.Target{}
+}
+
+func (f *fakeBuilder) Setup(client *api.Client, mountName string, config *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	if f.setupPanic {
+		panic("setup failed")
+	}
+	if f.cancel != nil {
+		f.cancel()
+	}
+	if f.setupErr != nil && f.partial {
+		return f, f.setupErr
+	}
+	if f.setupErr != nil {
+		return nil, f.setupErr
+	}
+	return f, nil
+}
+
+func (f *fakeBuilder) Cleanup(client *api.Client) error {
+	f.cleanedUp = true
+	return nil
+}
+
+func (f *fakeBuilder) ParseConfig(body hcl.Body) error { return nil }
+
+func (f *fakeBuilder) GetTargetInfo() TargetInfo { return TargetInfo{} }
+
+func (f *fakeBuilder) Flags(fs *flag.FlagSet) {}
+
+func TestBenchmarkTarget_Matches(t *testing.T) {
+	target := &BenchmarkTarget{Method: "GET", PathPrefix: "/v1/sys/health", SegmentPrefix: true}
+	for path, expected := range map[string]bool{
+		"/v1/sys/health":           true,
+		"/v1/sys/health?standbyok": true,
+		"/v1/sys/health/extra":     true,
+		"/v1/sys/healthz":          false,
+	} {
+		if target.matches("GET", path) != expected {
+			t.Fatalf("expected %v to match: %v", path, expected)
+		}
+	}
+	if target.matches("POST", "/v1/sys/health") {
+		t.Fatal("expected POST not to match")
+	}
+}
+
+func TestPercentageValidate_Overrides(t *testing.T) {
+	tests := []*BenchmarkTarget{
+		{Name: "shared", Weight: 100},
+		{Name: "override", RPS: 10},
+	}
+	if err := percentageValidate(tests); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	tests[0].Weight = 50
+	if err := percentageValidate(tests); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestTargetMulti_AttackGroups(t *testing.T) {
+	tm := TargetMulti{targets: []BenchmarkTarget{
+		{Name: "shared1", Weight: 50},
+		{Name: "shared2", Weight: 50},
+		{Name: "override", RPS: 10, Duration: "1m", duration: time.Minute},
+	}}
+	pacer := vegeta.Rate{Freq: 100, Per: time.Second}
+
+	groups := tm.attackGroups(10*time.Second, pacer)
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got: %d", len(groups))
+	}
+	if len(groups[0].tm.targets) != 2 || groups[0].duration != 10*time.Second || groups[0].pacer != pacer {
+		t.Fatalf("unexpected shared group: %+v", groups[0])
+	}
+
+	override := groups[1]
+	if override.duration != time.Minute {
+		t.Fatalf("expected override duration of 1m, got: %v", override.duration)
+	}
+	if rate := override.pacer.Rate(0); rate != 10 {
+		t.Fatalf("expected override rate of 10, got: %f", rate)
+	}
+	if override.tm.targets[0].Weight != 100 {
+		t.Fatalf("expected override target weight of 100, got: %d", override.tm.targets[0].Weight)
+	}
+}
+
+func TestBuildTargets_SetupFailure(t *testing.T) {
+	logger := hclog.NewNullLogger()
+	for name, failing := range map[string]*fakeBuilder{
+		"error": {setupErr: errors.New("setup failed")},
+		"panic": {setupPanic: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ok := &fakeBuilder{}
+			tests := []*BenchmarkTarget{
+				{Name: "ok", Weight: 50, Builder: ok},
+				{Name: "failing", Weight: 50, Builder: failing},
+			}
+
+			tm, err := BuildTargets(context.Background(), nil, tests, &logger, &TopLevelTargetConfig{})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if tm == nil || len(tm.targets) != 1 {
+				t.Fatalf("expected the successfully set up target to be returned, got: %v", tm)
+			}
+
+			if err := tm.Cleanup(context.Background(), nil); err != nil {
+				t.Fatalf("err: %v", err)
+			}
+			if !ok.cleanedUp {
+				t.Fatal("expected successfully set up target to be cleaned up")
+			}
+		})
+	}
+}
+
+func TestBuildTargets_SkipSetupUnsupported(t *testing.T) {
+	logger := hclog.NewNullLogger()
+	builder := &fakeBuilder{}
+	tests := []*BenchmarkTarget{
+		{Name: "fake", Type: "fake", Weight: 100, Builder: builder},
+	}
+
+	_, err := BuildTargets(context.Background(), nil, tests, &logger, &TopLevelTargetConfig{SkipSetup: true})
+	if err == nil {
+		t.Fatal("expected error for test which doesn't support skip_setup")
+	}
+}
+
+func TestTargetMulti_CleanupSkipped(t *testing.T) {
+	previous := targetLogger
+	t.Cleanup(func() { targetLogger = previous })
+	targetLogger = hclog.NewNullLogger()
+	kept := &fakeBuilder{}
+	removed := &fakeBuilder{}
+	tm := TargetMulti{targets: []BenchmarkTarget{
+		{Name: "kept", Builder: kept, SkipCleanup: true},
+		{Name: "removed", Builder: removed},
+	}}
+
+	if err := tm.Cleanup(context.Background(), nil); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if kept.cleanedUp {
+		t.Fatal("expected target which skips cleanup to be kept")
+	}
+	if !removed.cleanedUp {
+		t.Fatal("expected target to be cleaned up")
+	}
+}
+
+func TestBuildTargets_Interrupted(t *testing.T) {
+	logger := hclog.NewNullLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := &fakeBuilder{}
+	interrupted := &fakeBuilder{cancel: cancel, setupErr: context.Canceled, partial: true}
+	never := &fakeBuilder{}
+	tests := []*BenchmarkTarget{
+		{Name: "done", Weight: 40, Builder: done},
+		{Name: "interrupted", Weight: 30, Builder: interrupted},
+		{Name: "never", Weight: 30, Builder: never},
+	}
+
+	// Targets which were set up, along with what the interrupted target
+	// created so far, are returned to be cleaned up
+	tm, err := BuildTargets(ctx, nil, tests, &logger, &TopLevelTargetConfig{})
+	if err == nil {
+		t.Fatal("expected error when setup is interrupted")
+	}
+	if tm == nil || len(tm.targets) != 2 || tm.targets[0].Name != "done" || tm.targets[1].Name != "interrupted" {
+		t.Fatalf("expected the set up and interrupted targets to be returned, got: %v", tm)
+	}
+	if err := tm.Cleanup(context.Background(), nil); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !done.cleanedUp || !interrupted.cleanedUp || never.cleanedUp {
+		t.Fatal("expected only the targets which were set up to be cleaned up")
+	}
+}
+
+func TestTargetMulti_TargeterPanic(t *testing.T) {
+	logger := targetLogger
+	t.Cleanup(func() { targetLogger = logger })
+	targetLogger = hclog.NewNullLogger()
+	tm := TargetMulti{targets: []BenchmarkTarget{
+		{Name: "panic", Weight: 100, Builder: &fakeBuilder{targetPanic: true}},
+	}}
+
+	targeter, err := tm.Targeter(nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := targeter(&vegeta.Target{}); err == nil {
+		t.Fatal("expected error from a panicking target")
+	}
+}
+
+func TestBuildTargets_SkipUnsupported(t *testing.T) {
+	logger := hclog.NewNullLogger()
+	tests := []*BenchmarkTarget{
+		{Name: "a", Weight: 50, Builder: &fakeBuilder{}},
+		{Name: "b", Weight: 25, Builder: &fakeBuilder{}},
+		{Name: "unsupported", Weight: 25, Builder: &fakeBuilder{setupErr: fmt.Errorf("%w: control groups", errUnsupported)}},
+	}
+
+	tm, err := BuildTargets(context.Background(), nil, tests, &logger, &TopLevelTargetConfig{})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(tm.targets) != 2 {
+		t.Fatalf("expected the unsupported target to be skipped, got: %v", tm.targets)
+	}
+	if tm.targets[0].Weight != 67 || tm.targets[1].Weight != 33 {
+		t.Fatalf("expected weights to be rebalanced to 67 and 33, got: %d and %d", tm.targets[0].Weight, tm.targets[1].Weight)
+	}
+
+	tests = []*BenchmarkTarget{
+		{Name: "unsupported", Weight: 100, Builder: &fakeBuilder{setupErr: fmt.Errorf("%w: control groups", errUnsupported)}},
+	}
+	if _, err := BuildTargets(context.Background(), nil, tests, &logger, &TopLevelTargetConfig{}); err == nil {
+		t.Fatal("expected error when every target is skipped")
+	}
+
+	// Server errors outside of setup checks, such as those of mistyped
+	// paths, fail the run
+	tests = []*BenchmarkTarget{
+		{Name: "a", Weight: 50, Builder: &fakeBuilder{}},
+		{Name: "typo", Weight: 50, Builder: &fakeBuilder{setupErr: errors.New("* unsupported path")}},
+	}
+	if _, err := BuildTargets(context.Background(), nil, tests, &logger, &TopLevelTargetConfig{}); err == nil {
+		t.Fatal("expected error for a server error outside of a setup check")
+	}
+}
+
+func TestBuildTargets_CleanupUnsupported(t *testing.T) {
+	logger := hclog.NewNullLogger()
+	partial := &fakeBuilder{setupErr: fmt.Errorf("%w: cmac", errUnsupported), partial: true}
+	tests := []*BenchmarkTarget{
+		{Name: "a", Weight: 50, Builder: &fakeBuilder{}},
+		{Name: "partial", Weight: 50, Builder: partial},
+	}
+
+	// What a skipped target created before finding out it is unsupported
+	// is cleaned up
+	if _, err := BuildTargets(context.Background(), nil, tests, &logger, &TopLevelTargetConfig{}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !partial.cleanedUp {
+		t.Fatal("expected skipped target to be cleaned up")
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"os"
+	"text/template"
+
+	"github.com/hashicorp/go-uuid"
+)
+
+const bodyTemplateRandomChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// bodyTemplate is a request body template loaded from a file. Templates are
+// parsed once during Setup and executed for each request.
+type bodyTemplate struct {
+	tmpl *template.Template
+	rng  *rand.Rand
+}
+
+// bodyTemplateData is passed to body templates. Random and UUID are methods
+// so that they are only generated when a template uses them.
+type bodyTemplateData struct {
+	Index int
+
+	rng *rand.Rand
+}
+
+// Random returns a random 16 character alphanumeric string
+func (d bodyTemplateData) Random() string {
+	b := make([]byte, 16)
+	for i := range b {
+		b[i] = bodyTemplateRandomChars[d.rng.Intn(len(bodyTemplateRandomChars))]
+	}
+	return string(b)
+}
+
+// UUID returns a random UUID
+func (bodyTemplateData) UUID() (string, error) {
+	return uuid.GenerateUUID()
+}
+
+// newBodyTemplate parses the body template in the passed in file, executing
+// it once so that invalid placeholders are caught during Setup
+func newBodyTemplate(path string, rng *rand.Rand) (*bodyTemplate, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading body template: %v", err)
+	}
+
+	tmpl, err := template.New(path).Option("missingkey=error").Parse(string(contents))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing body template: %v", err)
+	}
+
+	b := &bodyTemplate{tmpl: tmpl, rng: rng}
+	if _, err := b.render(1); err != nil {
+		return nil, fmt.Errorf("error executing body template: %v", err)
+	}
+	return b, nil
+}
+
+// render executes the template for the request with the passed in index
+func (b *bodyTemplate) render(index int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := b.tmpl.Execute(&buf, bodyTemplateData{Index: index, rng: b.rng}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBodyTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "body.json")
+	contents := `{"data": {"index": {{ .Index }}, "random": "{{ .Random }}", "uuid": "{{ .UUID }}"}}`
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	tmpl, err := newBodyTemplate(path, NewRand(1))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	body, err := tmpl.render(42)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	var parsed struct {
+		Data struct {
+			Index  int    `json:"index"`
+			Random string `json:"random"`
+			UUID   string `json:"uuid"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		t.Fatalf("expected rendered body to be valid JSON, got: %s", body)
+	}
+	if parsed.Data.Index != 42 {
+		t.Fatalf("expected index 42, got: %d", parsed.Data.Index)
+	}
+	if len(parsed.Data.Random) != 16 {
+		t.Fatalf("expected 16 character random string, got: %q", parsed.Data.Random)
+	}
+	if len(parsed.Data.UUID) != 36 {
+		t.Fatalf("expected UUID, got: %q", parsed.Data.UUID)
+	}
+
+	// Unknown placeholders are rejected when the template is loaded
+	if err := os.WriteFile(path, []byte(`{{ .Unknown }}`), 0o600); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if _, err := newBodyTemplate(path, NewRand(1)); err == nil {
+		t.Fatal("expected error for unknown placeholder")
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+// ClosedLoop paces an attack as a fixed number of virtual users rather than
+// a rate of arriving requests. Each worker sends its next request ThinkTime
+// after its previous request completes, so the request rate falls as
+// OpenBao slows down. The wrapped Pacer only decides when the attack stops.
+type ClosedLoop struct {
+	vegeta.Pacer
+	ThinkTime time.Duration
+}
+
+// closedLoopAttacker attacks with a fixed number of virtual users, each
+// sending a request, waiting for its response and then thinking before the
+// next one
+type closedLoopAttacker struct {
+	client  *http.Client
+	workers int
+
+	hits     atomic.Uint64
+	stopch   chan struct{}
+	stopOnce sync.Once
+}
+
+func newClosedLoopAttacker(client *http.Client, workers int) *closedLoopAttacker {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &closedLoopAttacker{
+		client:  client,
+		workers: workers,
+		stopch:  make(chan struct{}),
+	}
+}
+
+// Attack starts the virtual users, returning the results of their requests.
+// The channel is closed once every virtual user has stopped.
+func (a *closedLoopAttacker) Attack(tr vegeta.Targeter, p ClosedLoop, du time.Duration, name string) <-chan *vegeta.Result {
+	results := make(chan *vegeta.Result)
+	began := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < a.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			think := time.NewTimer(0)
+			defer think.Stop()
+
+			for {
+				select {
+				case <-a.stopch:
+					return
+				case <-think.C:
+				}
+
+				elapsed := time.Since(began)
+				if du > 0 && elapsed > du {
+					return
+				}
+				seq := a.hits.Add(1) - 1
+				if _, stop := p.Pace(elapsed, seq); stop {
+					return
+				}
+
+				results <- a.hit(tr, name, seq)
+				think.Reset(p.ThinkTime)
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// Stop stops the virtual users once their requests in flight complete
+func (a *closedLoopAttacker) Stop() {
+	a.stopOnce.Do(func() {
+		close(a.stopch)
+	})
+}
+
+// hit sends a single request, recording its result the same way vegeta does
+func (a *closedLoopAttacker) hit(tr vegeta.Targeter, name string, seq uint64) *vegeta.Result {
+	res := vegeta.Result{
+		Attack:    name,
+		Seq:       seq,
+		Timestamp: time.Now(),
+	}
+	var err error
+	defer func() {
+		res.Latency = time.Since(res.Timestamp)
+		if err != nil {
+			res.Error = err.Error()
+		}
+	}()
+
+	var tgt vegeta.Target
+	if err = tr(&tgt); err != nil {
+		a.Stop()
+		return &res
+	}
+	res.Method = tgt.Method
+	res.URL = tgt.URL
+
+	req, err := tgt.Request()
+	if err != nil {
+		return &res
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return &res
+	}
+	defer resp.Body.Close()
+
+	if res.Body, err = io.ReadAll(resp.Body); err != nil {
+		return &res
+	}
+	res.BytesIn = uint64(len(res.Body))
+	if req.ContentLength != -1 {
+		res.BytesOut = uint64(req.ContentLength)
+	}
+	if res.Code = uint16(resp.StatusCode); res.Code < 200 || res.Code >= 400 {
+		res.Error = resp.Status
+	}
+	res.Headers = resp.Header
+	return &res
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+// stopAfter stops an attack once limit requests have been sent
+type stopAfter uint64
+
+func (s stopAfter) Pace(elapsed time.Duration, hits uint64) (time.Duration, bool) {
+	return 0, hits >= uint64(s)
+}
+
+func (s stopAfter) Rate(elapsed time.Duration) float64 {
+	return 0
+}
+
+func TestClosedLoopAttacker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	targeter := vegeta.NewStaticTargeter(vegeta.Target{Method: "GET", URL: server.URL})
+	attacker := newClosedLoopAttacker(server.Client(), 2)
+
+	// Each of the 2 virtual users sends 3 requests, thinking twice in between
+	const thinkTime = 20 * time.Millisecond
+	start := time.Now()
+	var count int
+	for res := range attacker.Attack(targeter, ClosedLoop{Pacer: stopAfter(6), ThinkTime: thinkTime}, 0, "test") {
+		if res.Code != http.StatusOK || string(res.Body) != "ok" {
+			t.Fatalf("unexpected result: %+v", res)
+		}
+		count++
+	}
+
+	if count != 6 {
+		t.Fatalf("expected 6 requests, got: %d", count)
+	}
+	if elapsed := time.Since(start); elapsed < 2*thinkTime {
+		t.Fatalf("expected virtual users to think between requests, finished after %v", elapsed)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// ConfigField describes a single configurable field of a test's config block
+type ConfigField struct {
+	Name    string
+	Type    string
+	Default string
+}
+
+// ConfigFields returns the fields which can be set in the config block of the
+// passed in test type along with their defaults. Fields are derived from the
+// hcl tags of the test's config struct after parsing an empty configuration,
+// so any defaults set by the test's ParseConfig are included.
+func ConfigFields(testType string) ([]ConfigField, error) {
+	newBuilder, ok := TestList[testType]
+	if !ok {
+		return nil, fmt.Errorf("invalid test type: %v", testType)
+	}
+	builder := newBuilder()
+
+	// Errors are expected here for tests with required fields; we only care
+	// about the defaults that were populated
+	_ = builder.ParseConfig(hcl.EmptyBody())
+
+	return builderConfigFields(builder), nil
+}
+
+// builderConfigFields returns the current values of the fields of a parsed
+// builder's config block
+func builderConfigFields(builder BenchmarkBuilder) []ConfigField {
+	v := reflect.ValueOf(builder)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	config := v.FieldByName("config")
+	if !config.IsValid() {
+		return nil
+	}
+
+	var fields []ConfigField
+	collectConfigFields(config, "config", &fields)
+	return fields
+}
+
+// collectConfigFields walks the hcl tagged fields of v, appending each
+// attribute to fields. Nested blocks are walked with their name added to
+// the prefix.
+func collectConfigFields(v reflect.Value, prefix string, fields *[]ConfigField) {
+	t := v.Type()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		if !v.IsNil() {
+			v = v.Elem()
+		} else {
+			v = reflect.Zero(t)
+		}
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := t.Field(i).Tag.Lookup("hcl")
+		if !ok {
+			continue
+		}
+		name, kind, _ := strings.Cut(tag, ",")
+		if name == "" || kind == "remain" {
+			continue
+		}
+
+		field := v.Field(i)
+		if kind == "block" {
+			collectConfigFields(field, prefix+"."+name, fields)
+			continue
+		}
+
+		*fields = append(*fields, ConfigField{
+			Name:    prefix + "." + name,
+			Type:    strings.TrimPrefix(t.Field(i).Type.String(), "*"),
+			Default: configFieldDefault(name, field),
+		})
+	}
+}
+
+// configFieldDefault formats the default value of a field, hiding values
+// that may have been populated from credentials in the environment
+func configFieldDefault(name string, v reflect.Value) string {
+	if v.IsZero() {
+		return ""
+	}
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	for _, sensitive := range []string{"password", "secret", "token", "private_key"} {
+		if strings.Contains(name, sensitive) {
+			return "(redacted)"
+		}
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"testing"
+)
+
+func TestConfigFields(t *testing.T) {
+	fields, err := ConfigFields(KVV2ReadTestType)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	defaults := make(map[string]string)
+	for _, field := range fields {
+		defaults[field.Name] = field.Default
+	}
+	if defaults["config.numkvs"] != "1000" {
+		t.Fatalf("expected numkvs default of 1000, got: %v", fields)
+	}
+	if _, ok := defaults["config.kvsize"]; !ok {
+		t.Fatalf("expected kvsize field, got: %v", fields)
+	}
+
+	if _, err := ConfigFields("nope"); err == nil {
+		t.Fatal("expected error for invalid test type")
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OrderTargets returns the targets ordered so that every target comes after
+// the targets it depends on, and otherwise in the order they were declared
+// in. It fails if a target depends on one which isn't being run or if the
+// dependencies form a cycle.
+func OrderTargets(tests []*BenchmarkTarget) ([]*BenchmarkTarget, error) {
+	byName := make(map[string]*BenchmarkTarget, len(tests))
+	for _, bvTest := range tests {
+		byName[bvTest.Name] = bvTest
+	}
+	for _, bvTest := range tests {
+		for _, dep := range bvTest.DependsOn {
+			if _, ok := byName[dep]; !ok {
+				return nil, fmt.Errorf("target %v depends on %v, which isn't being run", bvTest.Name, dep)
+			}
+		}
+	}
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int, len(tests))
+	ordered := make([]*BenchmarkTarget, 0, len(tests))
+	var visit func(bvTest *BenchmarkTarget, path []string) error
+	visit = func(bvTest *BenchmarkTarget, path []string) error {
+		path = append(path, bvTest.Name)
+		switch state[bvTest.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("targets depend on each other: %v", strings.Join(path, " -> "))
+		}
+
+		state[bvTest.Name] = visiting
+		for _, dep := range bvTest.DependsOn {
+			if err := visit(byName[dep], path); err != nil {
+				return err
+			}
+		}
+		state[bvTest.Name] = visited
+		ordered = append(ordered, bvTest)
+		return nil
+	}
+	for _, bvTest := range tests {
+		if err := visit(bvTest, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// cleanupWaves groups the indexes of the targets into waves which are cleaned
+// up one after another, so that every target is cleaned up before the
+// targets it depends on. Dependencies on targets which aren't in targets,
+// such as those skipped as unsupported, are ignored.
+func cleanupWaves(targets []BenchmarkTarget) [][]int {
+	byName := make(map[string]int, len(targets))
+	for i, target := range targets {
+		byName[target.Name] = i
+	}
+
+	// The depth of a target is the length of the longest chain of
+	// dependencies below it. The targets were ordered when they were
+	// validated, so the dependencies don't form a cycle.
+	depths := make(map[int]int, len(targets))
+	var depth func(i int) int
+	depth = func(i int) int {
+		if d, ok := depths[i]; ok {
+			return d
+		}
+		d := 0
+		for _, dep := range targets[i].DependsOn {
+			if j, ok := byName[dep]; ok {
+				d = max(d, depth(j)+1)
+			}
+		}
+		depths[i] = d
+		return d
+	}
+
+	var deepest int
+	for i := range targets {
+		deepest = max(deepest, depth(i))
+	}
+	waves := make([][]int, deepest+1)
+	for i := range targets {
+		wave := deepest - depths[i]
+		waves[wave] = append(waves[wave], i)
+	}
+	return waves
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrderTargets(t *testing.T) {
+	tests := []*BenchmarkTarget{
+		{Name: "login", DependsOn: []string{"policy", "auth"}},
+		{Name: "read"},
+		{Name: "auth"},
+		{Name: "policy", DependsOn: []string{"auth"}},
+	}
+	ordered, err := OrderTargets(tests)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	var names []string
+	targets := make([]BenchmarkTarget, len(ordered))
+	for i, bvTest := range ordered {
+		names = append(names, bvTest.Name)
+		targets[i] = *bvTest
+	}
+	if expected := []string{"auth", "policy", "login", "read"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+
+	// Dependents are cleaned up before the targets they depend on
+	if expected := [][]int{{2}, {1}, {0, 3}}; !reflect.DeepEqual(cleanupWaves(targets), expected) {
+		t.Fatalf("expected cleanup waves %v, got %v", expected, cleanupWaves(targets))
+	}
+
+	tests[2].DependsOn = []string{"login"}
+	if _, err := OrderTargets(tests); err == nil {
+		t.Fatal("expected an error for a dependency cycle")
+	}
+
+	if _, err := OrderTargets(tests[:2]); err == nil {
+		t.Fatal("expected an error for a dependency which isn't being run")
+	}
+}
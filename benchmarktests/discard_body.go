@@ -0,0 +1,104 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+// discardedBytesHeader is set on responses whose bodies were discarded to
+// the number of bytes read, so that the size of the body can still be
+// reported
+const discardedBytesHeader = "X-Benchmark-Discarded-Bytes"
+
+// BodyDiscarder is implemented by tests whose successful responses are too
+// large to keep with their results, such as snapshots of the whole storage.
+// Their bodies are still read in full, so that the request's latency
+// includes the download, but are discarded as they are read.
+type BodyDiscarder interface {
+	// DiscardsBody reports whether successful response bodies are discarded
+	DiscardsBody() bool
+}
+
+// discardBodyTransport discards the successful response bodies of requests
+// made to targets which discard them
+type discardBodyTransport struct {
+	http.RoundTripper
+	targets []TargetInfo
+}
+
+// discardBodies returns a copy of the client which discards the successful
+// response bodies of the targets implementing BodyDiscarder, or the client
+// itself if there are none
+func discardBodies(client *http.Client, targets []BenchmarkTarget) *http.Client {
+	var discarded []TargetInfo
+	for i := range targets {
+		if discarder, ok := targets[i].Builder.(BodyDiscarder); ok && discarder.DiscardsBody() {
+			discarded = append(discarded, targets[i].Builder.GetTargetInfo())
+		}
+	}
+	if len(discarded) == 0 {
+		return client
+	}
+
+	discardClient := &http.Client{}
+	if client != nil {
+		*discardClient = *client
+	}
+	transport := discardClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	discardClient.Transport = &discardBodyTransport{RoundTripper: transport, targets: discarded}
+	return discardClient
+}
+
+func (t *discardBodyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.RoundTripper.RoundTrip(req)
+	if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp, err
+	}
+	for _, target := range t.targets {
+		if req.Method == target.method && strings.HasPrefix(req.URL.Path, target.pathPrefix) {
+			resp.Body = discardedBody{ReadCloser: resp.Body, header: resp.Header}
+			break
+		}
+	}
+	return resp, nil
+}
+
+// discardedBody reads the whole of the response body on the first read,
+// discarding it, and reports it as empty. The number of bytes read is set on
+// the response's header.
+type discardedBody struct {
+	io.ReadCloser
+	header http.Header
+}
+
+func (d discardedBody) Read(p []byte) (int, error) {
+	n, err := io.Copy(io.Discard, d.ReadCloser)
+	if err != nil {
+		return 0, err
+	}
+	if n > 0 {
+		d.header.Set(discardedBytesHeader, strconv.FormatInt(n, 10))
+	}
+	return 0, io.EOF
+}
+
+// restoreBytesIn sets the bytes read of a result whose response body was
+// discarded
+func restoreBytesIn(result *vegeta.Result) {
+	n := result.Headers.Get(discardedBytesHeader)
+	if n == "" {
+		return
+	}
+	result.BytesIn, _ = strconv.ParseUint(n, 10, 64)
+	result.Headers.Del(discardedBytesHeader)
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+func TestDiscardBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/sys/storage/raft/snapshot/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(strings.Repeat("a", 1024)))
+	}))
+	defer server.Close()
+
+	client := discardBodies(server.Client(), []BenchmarkTarget{
+		{Name: "snapshot", Builder: &RaftSnapshotTest{pathPrefix: "/v1/sys/storage/raft/snapshot"}},
+		{Name: "read", Builder: &fakeBuilder{}},
+	})
+
+	for _, tc := range []struct {
+		path     string
+		expected int
+	}{
+		// Successful snapshots are discarded, while error responses and
+		// other requests are kept
+		{"/v1/sys/storage/raft/snapshot", 0},
+		{"/v1/sys/storage/raft/snapshot/missing", 1024},
+		{"/v1/secret/data", 1024},
+	} {
+		resp, err := client.Get(server.URL + tc.path)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if len(body) != tc.expected {
+			t.Fatalf("%v: expected %d bytes, got: %d", tc.path, tc.expected, len(body))
+		}
+
+		// The size of discarded bodies is still reported
+		result := &vegeta.Result{BytesIn: uint64(len(body)), Headers: resp.Header}
+		restoreBytesIn(result)
+		if result.BytesIn != 1024 {
+			t.Fatalf("%v: expected 1024 bytes in, got: %d", tc.path, result.BytesIn)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// envFunc returns the value of an environment variable, failing if it isn't
+// set rather than silently using an empty value
+var envFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "name", Type: cty.String},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		name := args[0].AsString()
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return cty.NilVal, fmt.Errorf("environment variable %q is not set", name)
+		}
+		return cty.StringVal(value), nil
+	},
+})
+
+// EvalContext returns the context every HCL config is decoded with. It
+// exposes the environment both as the env function, e.g. env("VAULT_ADDR"),
+// and as the env object, e.g. env.VAULT_ADDR.
+func EvalContext() *hcl.EvalContext {
+	vars := make(map[string]cty.Value)
+	for _, kv := range os.Environ() {
+		if name, value, ok := strings.Cut(kv, "="); ok && name != "" {
+			vars[name] = cty.StringVal(value)
+		}
+	}
+
+	return &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"env": cty.ObjectVal(vars),
+		},
+		Functions: map[string]function.Function{
+			"env": envFunc,
+		},
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+)
+
+const (
+	AccessDistributionUniform = "uniform"
+	AccessDistributionZipfian = "zipfian"
+)
+
+// keyDistribution selects which of n keys each request accesses. With a
+// zipfian distribution a few keys are accessed far more often than the rest,
+// modelling hot keys.
+type keyDistribution struct {
+	n   int
+	rng *rand.Rand
+
+	// zipf is nil for a uniform distribution
+	zipf *rand.Zipf
+}
+
+// newKeyDistribution returns a distribution over keys 1 through n drawn from
+// rng, which may be nil when only validating the config. s is the skew of the
+// zipfian distribution and must be greater than 1; larger values concentrate
+// more requests on the first few keys.
+func newKeyDistribution(rng *rand.Rand, n int, distribution string, s float64) (*keyDistribution, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("numkvs must be at least 1")
+	}
+
+	d := &keyDistribution{n: n, rng: rng}
+	switch distribution {
+	case "", AccessDistributionUniform:
+	case AccessDistributionZipfian:
+		if s <= 1 {
+			return nil, fmt.Errorf("zipf_s must be greater than 1")
+		}
+		if n > 1 {
+			d.zipf = rand.NewZipf(rng, s, 1, uint64(n-1))
+		}
+	default:
+		return nil, fmt.Errorf("access_distribution must be one of %v or %v", AccessDistributionUniform, AccessDistributionZipfian)
+	}
+	return d, nil
+}
+
+// next returns the number of the key the next request accesses
+func (d *keyDistribution) next() int {
+	if d.zipf == nil {
+		return int(1 + d.rng.Int63n(int64(d.n)))
+	}
+	return int(1 + d.zipf.Uint64())
+}
+
+const (
+	KeyModeRandomExisting = "random_existing"
+	KeyModeSequentialNew  = "sequential_new"
+	KeyModeFixed          = "fixed"
+)
+
+// writeKeys selects which key each write request writes to. Writes either
+// overwrite one of the n existing keys at random, append new keys after
+// them, or always overwrite the same key to test contention on a hot key.
+type writeKeys struct {
+	mode string
+	n    int
+	rng  *rand.Rand
+
+	// written counts the new keys written, and is shared by every worker so
+	// that appended keys don't collide
+	written atomic.Int64
+}
+
+// newWriteKeys returns the write keys for n existing keys drawn from rng,
+// which may be nil when only validating the config
+func newWriteKeys(rng *rand.Rand, n int, mode string) (*writeKeys, error) {
+	switch mode {
+	case "", KeyModeRandomExisting, KeyModeSequentialNew, KeyModeFixed:
+	default:
+		return nil, fmt.Errorf("key_mode must be one of %v, %v or %v", KeyModeRandomExisting, KeyModeSequentialNew, KeyModeFixed)
+	}
+	return &writeKeys{mode: mode, n: n, rng: rng}, nil
+}
+
+// next returns the number of the key the next write request writes to
+func (w *writeKeys) next() int {
+	switch w.mode {
+	case KeyModeSequentialNew:
+		return w.n + int(w.written.Add(1))
+	case KeyModeFixed:
+		return 1
+	default:
+		return int(1 + w.rng.Int63n(int64(w.n)))
+	}
+}
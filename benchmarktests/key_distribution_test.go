@@ -0,0 +1,106 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestKeyDistribution(t *testing.T) {
+	for _, distribution := range []string{AccessDistributionUniform, AccessDistributionZipfian} {
+		d, err := newKeyDistribution(NewRand(1), 100, distribution, 1.5)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		counts := make(map[int]int)
+		for i := 0; i < 10000; i++ {
+			key := d.next()
+			if key < 1 || key > 100 {
+				t.Fatalf("expected %v key within range, got: %d", distribution, key)
+			}
+			counts[key]++
+		}
+
+		// The first key is the hottest under a zipfian distribution
+		if distribution == AccessDistributionZipfian && counts[1] < 10*counts[50] {
+			t.Fatalf("expected key 1 to be read far more often than key 50, got: %d and %d", counts[1], counts[50])
+		}
+	}
+
+	// A single key is always selected
+	d, err := newKeyDistribution(NewRand(1), 1, AccessDistributionZipfian, 1.5)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if key := d.next(); key != 1 {
+		t.Fatalf("expected key 1, got: %d", key)
+	}
+
+	if _, err := newKeyDistribution(NewRand(1), 100, AccessDistributionZipfian, 1); err == nil {
+		t.Fatal("expected error when zipf_s is not greater than 1")
+	}
+	if _, err := newKeyDistribution(NewRand(1), 100, "normal", 1.5); err == nil {
+		t.Fatal("expected error for unknown distribution")
+	}
+	if _, err := newKeyDistribution(NewRand(1), 0, AccessDistributionUniform, 1.5); err == nil {
+		t.Fatal("expected error when there are no keys")
+	}
+}
+
+func TestKeyDistributionLargeNumKVs(t *testing.T) {
+	// Larger than fits in an int32
+	const numKVs = 1 << 40
+	for _, distribution := range []string{AccessDistributionUniform, AccessDistributionZipfian} {
+		d, err := newKeyDistribution(NewRand(1), numKVs, distribution, 1.1)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		for i := 0; i < 1000; i++ {
+			if key := d.next(); key < 1 || key > numKVs {
+				t.Fatalf("expected %v key within range, got: %d", distribution, key)
+			}
+		}
+	}
+}
+
+func TestWriteKeys(t *testing.T) {
+	fixed, err := newWriteKeys(NewRand(1), 100, KeyModeFixed)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if key := fixed.next(); key != 1 || fixed.next() != key {
+		t.Fatalf("expected fixed mode to always write key 1, got: %d", key)
+	}
+
+	// Concurrent workers append distinct keys after the existing ones
+	seq, err := newWriteKeys(NewRand(1), 100, KeyModeSequentialNew)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	keys := make(chan int, 1000)
+	var wg sync.WaitGroup
+	for w := 0; w < 10; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				keys <- seq.next()
+			}
+		}()
+	}
+	wg.Wait()
+	close(keys)
+	seen := make(map[int]bool)
+	for key := range keys {
+		if key <= 100 || key > 1100 || seen[key] {
+			t.Fatalf("expected a new distinct key after 100, got: %d", key)
+		}
+		seen[key] = true
+	}
+
+	if _, err := newWriteKeys(nil, 100, "hot"); err == nil {
+		t.Fatal("expected error for unknown key_mode")
+	}
+}
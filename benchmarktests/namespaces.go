@@ -0,0 +1,210 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+// namespacedBuilder wraps a test which has been set up in several namespaces.
+// Each request is sent to one of the namespaces at random.
+type namespacedBuilder struct {
+	builders   []BenchmarkBuilder
+	namespaces []string
+	rng        *rand.Rand
+}
+
+var (
+	_ BenchmarkBuilder = (*namespacedBuilder)(nil)
+	_ RateLimited      = (*rateLimitedNamespacedBuilder)(nil)
+	_ LeaseLimited     = (*leaseLimitedNamespacedBuilder)(nil)
+)
+
+// namespacePath returns the full path of the child namespace of the
+// client's current namespace
+func namespacePath(client *api.Client, namespace string) string {
+	parent := strings.Trim(client.Namespace(), "/")
+	if parent == "" || parent == "root" {
+		return namespace
+	}
+	return parent + "/" + namespace
+}
+
+// setupNamespaces creates any of the passed in child namespaces of the
+// client's namespace which don't exist yet. The namespaces which were created
+// are returned so that they can be removed during cleanup.
+func setupNamespaces(client *api.Client, namespaces []string) ([]string, error) {
+	var created []string
+	for _, ns := range namespaces {
+		resp, err := client.Logical().Read("sys/namespaces/" + ns)
+		if err != nil {
+			return created, fmt.Errorf("error reading namespace %v: %v", ns, err)
+		}
+		if resp != nil {
+			continue
+		}
+
+		targetLogger.Trace("creating namespace", "namespace", ns)
+		if _, err := client.Logical().Write("sys/namespaces/"+ns, nil); err != nil {
+			return created, fmt.Errorf("error creating namespace %v: %v", ns, err)
+		}
+		created = append(created, ns)
+	}
+	return created, nil
+}
+
+// cleanupNamespaces deletes the passed in child namespaces of the client's
+// namespace
+func cleanupNamespaces(client *api.Client, namespaces []string) error {
+	var errs []error
+	for _, ns := range namespaces {
+		targetLogger.Trace("deleting namespace", "namespace", ns)
+		if _, err := client.Logical().Delete("sys/namespaces/" + ns); err != nil {
+			errs = append(errs, fmt.Errorf("error deleting namespace %v: %w", ns, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// setupNamespaced runs the target's Setup once in each namespace. The same
+// mount name is used in every namespace so that results can be attributed
+// to the target regardless of the namespace a request was sent to.
+func (bt *BenchmarkTarget) setupNamespaced(client *api.Client, mountName string, config *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	nsConfig := *config
+	if config.RandomMounts {
+		var err error
+		mountName, err = uuid.GenerateUUID()
+		if err != nil {
+			return nil, fmt.Errorf("can't create UUID: %v", err)
+		}
+		nsConfig.RandomMounts = false
+	}
+
+	nb := &namespacedBuilder{rng: config.Rand}
+	for _, ns := range config.Namespaces {
+		nsClient := client.WithNamespace(namespacePath(client, ns))
+		builder, err := bt.Builder.Setup(nsClient, mountName, &nsConfig)
+		if err != nil {
+			if builder != nil && isUnsupported(err) {
+				cleanupSkipped(nsClient, bt.Name, builder)
+			}
+			// An interrupted setup returns the namespaces set up so far to
+			// be cleaned up along with the other targets
+			if config.interrupted() {
+				if builder != nil {
+					nb.builders = append(nb.builders, builder)
+					nb.namespaces = append(nb.namespaces, ns)
+				}
+				if len(nb.builders) > 0 {
+					return nb, fmt.Errorf("error setting up namespace %v: %w", ns, err)
+				}
+				return nil, fmt.Errorf("error setting up namespace %v: %w", ns, err)
+			}
+			// Clean up the namespaces which were already set up
+			_ = nb.Cleanup(client)
+			return nil, fmt.Errorf("error setting up namespace %v: %w", ns, err)
+		}
+		nb.builders = append(nb.builders, builder)
+		nb.namespaces = append(nb.namespaces, ns)
+
+		// Results don't record the namespace their request was sent to, so
+		// requests can't be read back in the right namespace
+		if _, ok := builder.(Verifier); ok {
+			_ = nb.Cleanup(client)
+			return nil, fmt.Errorf("%v tests which verify their requests can't be used with namespaces", bt.Type)
+		}
+	}
+
+	// Keep reporting quota enforcement, with the quotas of every namespace
+	// added up as requests are spread across them
+	switch nb.builders[0].(type) {
+	case RateLimited:
+		return &rateLimitedNamespacedBuilder{nb}, nil
+	case LeaseLimited:
+		return &leaseLimitedNamespacedBuilder{nb}, nil
+	}
+	return nb, nil
+}
+
+func (n *namespacedBuilder) Target(client *api.Client) vegeta.Target {
+	return n.builders[n.rng.Intn(len(n.builders))].Target(client)
+}
+
+func (n *namespacedBuilder) Setup(client *api.Client, mountName string, config *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	return nil, fmt.Errorf("namespaced tests are set up by BuildTargets")
+}
+
+func (n *namespacedBuilder) Cleanup(client *api.Client) error {
+	var errs []error
+	for i, builder := range n.builders {
+		nsClient := client.WithNamespace(namespacePath(client, n.namespaces[i]))
+		if err := builder.Cleanup(nsClient); err != nil {
+			errs = append(errs, fmt.Errorf("namespace %v: %w", n.namespaces[i], err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *namespacedBuilder) ParseConfig(body hcl.Body) error {
+	return nil
+}
+
+func (n *namespacedBuilder) GetTargetInfo() TargetInfo {
+	return n.builders[0].GetTargetInfo()
+}
+
+// AttackFinished lets the wrapped tests which need to know that an attack
+// has finished
+func (n *namespacedBuilder) AttackFinished() {
+	for _, builder := range n.builders {
+		if finisher, ok := builder.(AttackFinisher); ok {
+			finisher.AttackFinished()
+		}
+	}
+}
+
+// DiscardsBody reports whether the wrapped test discards its response bodies
+func (n *namespacedBuilder) DiscardsBody() bool {
+	discarder, ok := n.builders[0].(BodyDiscarder)
+	return ok && discarder.DiscardsBody()
+}
+
+func (n *namespacedBuilder) Flags(fs *flag.FlagSet) {}
+
+// rateLimitedNamespacedBuilder wraps a rate limited test set up in several
+// namespaces, each with its own quota
+type rateLimitedNamespacedBuilder struct {
+	*namespacedBuilder
+}
+
+func (r *rateLimitedNamespacedBuilder) QuotaRate() float64 {
+	var rate float64
+	for _, builder := range r.builders {
+		rate += builder.(RateLimited).QuotaRate()
+	}
+	return rate
+}
+
+// leaseLimitedNamespacedBuilder wraps a lease limited test set up in several
+// namespaces, each with its own quota
+type leaseLimitedNamespacedBuilder struct {
+	*namespacedBuilder
+}
+
+func (l *leaseLimitedNamespacedBuilder) QuotaMaxLeases() int {
+	var leases int
+	for _, builder := range l.builders {
+		leases += builder.(LeaseLimited).QuotaMaxLeases()
+	}
+	return leases
+}
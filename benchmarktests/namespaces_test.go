@@ -0,0 +1,31 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"testing"
+)
+
+func TestNamespacedBuilder_Quotas(t *testing.T) {
+	nb := &namespacedBuilder{
+		builders: []BenchmarkBuilder{
+			&RateLimitQuotaTest{rate: 10},
+			&RateLimitQuotaTest{rate: 15},
+		},
+		namespaces: []string{"ns-0", "ns-1"},
+	}
+
+	// Quotas are reported for the wrapped tests, added up across namespaces
+	var builder BenchmarkBuilder = &rateLimitedNamespacedBuilder{nb}
+	limited, ok := builder.(RateLimited)
+	if !ok {
+		t.Fatal("expected rate limited builder")
+	}
+	if rate := limited.QuotaRate(); rate != 25 {
+		t.Fatalf("expected rate 25, got: %v", rate)
+	}
+	if _, ok := builder.(LeaseLimited); ok {
+		t.Fatal("unexpected lease limited builder")
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+const (
+	PayloadSizeUniform = "uniform"
+	PayloadSizeNormal  = "normal"
+)
+
+// payloadSize draws the size of request payloads from the range [min, max]
+type payloadSize struct {
+	min          int
+	max          int
+	distribution string
+}
+
+// newPayloadSize returns the payload size for the passed in config values.
+// When neither sizeMin nor sizeMax are set, every payload has the fixed size.
+func newPayloadSize(fixed, sizeMin, sizeMax int, distribution string) (payloadSize, error) {
+	if sizeMin == 0 && sizeMax == 0 {
+		return payloadSize{min: fixed, max: fixed}, nil
+	}
+
+	switch {
+	case sizeMin < 0 || sizeMax < 0:
+		return payloadSize{}, fmt.Errorf("kvsize_min and kvsize_max must not be negative")
+	case sizeMax < sizeMin:
+		return payloadSize{}, fmt.Errorf("kvsize_max must be greater than or equal to kvsize_min")
+	}
+
+	switch distribution {
+	case "", PayloadSizeUniform:
+		distribution = PayloadSizeUniform
+	case PayloadSizeNormal:
+	default:
+		return payloadSize{}, fmt.Errorf("kvsize_distribution must be one of %v or %v", PayloadSizeUniform, PayloadSizeNormal)
+	}
+
+	return payloadSize{min: sizeMin, max: sizeMax, distribution: distribution}, nil
+}
+
+// next returns the size of the next payload drawn from rng
+func (p payloadSize) next(rng *rand.Rand) int {
+	if p.max <= p.min {
+		return p.min
+	}
+
+	switch p.distribution {
+	case PayloadSizeNormal:
+		// Center on the middle of the range so that nearly all sizes fall
+		// within three standard deviations, clamping the few that don't
+		mean := float64(p.min+p.max) / 2
+		stddev := float64(p.max-p.min) / 6
+		size := int(rng.NormFloat64()*stddev + mean + 0.5)
+		return min(max(size, p.min), p.max)
+	default:
+		return p.min + rng.Intn(p.max-p.min+1)
+	}
+}
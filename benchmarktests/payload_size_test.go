@@ -0,0 +1,47 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import "testing"
+
+func TestPayloadSize(t *testing.T) {
+	rng := NewRand(1)
+
+	// Fixed size when no range is set
+	size, err := newPayloadSize(10, 0, 0, "")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if n := size.next(rng); n != 10 {
+		t.Fatalf("expected fixed size of 10, got: %d", n)
+	}
+
+	// A range where min and max are equal behaves like a fixed size
+	size, err = newPayloadSize(1, 64, 64, PayloadSizeNormal)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if n := size.next(rng); n != 64 {
+		t.Fatalf("expected size of 64, got: %d", n)
+	}
+
+	for _, distribution := range []string{PayloadSizeUniform, PayloadSizeNormal} {
+		size, err = newPayloadSize(1, 10, 20, distribution)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		for i := 0; i < 1000; i++ {
+			if n := size.next(rng); n < 10 || n > 20 {
+				t.Fatalf("expected %v size within range, got: %d", distribution, n)
+			}
+		}
+	}
+
+	if _, err := newPayloadSize(1, 20, 10, ""); err == nil {
+		t.Fatal("expected error when max is less than min")
+	}
+	if _, err := newPayloadSize(1, 10, 20, "exponential"); err == nil {
+		t.Fatal("expected error for unknown distribution")
+	}
+}
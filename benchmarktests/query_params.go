@@ -0,0 +1,47 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+// withQueryParams wraps the target function of a test so that the passed in
+// query parameters are added to the URL of every request. They replace any
+// parameters of the same name set by the test.
+func withQueryParams(target func(*api.Client) vegeta.Target, params map[string]string) func(*api.Client) vegeta.Target {
+	values := make(url.Values, len(params))
+	for name, value := range params {
+		values.Set(name, value)
+	}
+	encoded := values.Encode()
+
+	return func(client *api.Client) vegeta.Target {
+		t := target(client)
+
+		// Most tests don't set a query of their own, in which case the
+		// encoded parameters are appended without parsing the URL
+		if !strings.Contains(t.URL, "?") {
+			t.URL += "?" + encoded
+			return t
+		}
+
+		u, err := url.Parse(t.URL)
+		if err != nil {
+			targetLogger.Error("error parsing target URL", "url", t.URL, "error", err)
+			return t
+		}
+		query := u.Query()
+		for name, value := range params {
+			query.Set(name, value)
+		}
+		u.RawQuery = query.Encode()
+		t.URL = u.String()
+		return t
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"testing"
+
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+func TestWithQueryParams(t *testing.T) {
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for _, tc := range []struct {
+		url      string
+		expected string
+	}{
+		{"/v1/secret/data/secret-1", "/v1/secret/data/secret-1?version=2"},
+		{"/v1/secret/metadata?list=true&version=1", "/v1/secret/metadata?list=true&version=2"},
+	} {
+		target := withQueryParams(func(client *api.Client) vegeta.Target {
+			return vegeta.Target{Method: "GET", URL: client.Address() + tc.url}
+		}, map[string]string{"version": "2"})(client)
+		if target.URL != client.Address()+tc.expected {
+			t.Fatalf("expected %v, got %v", client.Address()+tc.expected, target.URL)
+		}
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"sort"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 
@@ -30,15 +32,113 @@ func init() {
 	prometheus.MustRegister(attackErrors)
 }
 
+const (
+	// maxErrorBodyLen is the length error response bodies are truncated to
+	maxErrorBodyLen = 512
+
+	// maxDistinctErrorBodies bounds the number of distinct error bodies
+	// tracked per target, in case bodies contain unique values such as
+	// request IDs
+	maxDistinctErrorBodies = 100
+)
+
 type Reporter struct {
 	tm         *TargetMulti
 	clientAddr string
 	metrics    map[string]*vegeta.Metrics
+
+	// server is the build of the server the requests were sent to, if it
+	// could be read
+	server *ServerInfo
+
+	// errorBodies is the number of distinct error response bodies reported
+	// per target. Error bodies aren't captured when it is 0.
+	errorBodies    int
+	errorBodyCount map[string]map[string]int
+	errorSummaries map[string][]ErrorBody
+
+	// verifications counts the read-back checks of each target which
+	// verifies its requests
+	verifications map[string]*Verification
+
+	// timeouts counts the requests to each target which timed out
+	timeouts map[string]int
+
+	// clientErrors counts the requests to each target which failed on the
+	// benchmark's side before reaching Vault
+	clientErrors map[string]*ClientErrors
+
+	// quotas compares the rate allowed by each rate limited target's quota
+	// with the rate its requests succeeded at
+	quotas map[string]*QuotaEnforcement
+
+	// leaseQuotas compares the number of leases allowed by each lease
+	// limited target's quota with the number of its requests which succeeded
+	leaseQuotas map[string]*LeaseQuotaEnforcement
+
+	// successes counts the requests which succeeded, per target and in
+	// total, when any target sets its own success status codes. vegeta only
+	// counts 2xx and 3xx responses as successes, so its success ratio and
+	// throughput are recomputed from these on Close.
+	successes map[string]uint64
+}
+
+// QuotaEnforcement is the rate a target's rate limit quota allows along with
+// the observed throughput of successful requests and the number of requests
+// rejected by the quota
+type QuotaEnforcement struct {
+	Configured float64 `json:"configured"`
+	Enforced   float64 `json:"enforced"`
+	Rejected   int     `json:"rejected"`
+}
+
+// LeaseQuotaEnforcement is the number of leases a target's lease count quota
+// allows along with the number of requests which succeeded, and so created
+// leases, and the number rejected by the quota
+type LeaseQuotaEnforcement struct {
+	Configured int `json:"configured"`
+	Accepted   int `json:"accepted"`
+	Rejected   int `json:"rejected"`
+}
+
+// ClientErrors counts the requests to a target which failed to connect to
+// Vault, split by the local resources which ran out. These failures are
+// caused by the machine running the benchmark rather than by Vault.
+type ClientErrors struct {
+	// OpenFiles counts requests which failed as the open file limit was
+	// reached
+	OpenFiles int `json:"open_files"`
+
+	// EphemeralPorts counts requests which failed as no local port was
+	// free to connect from
+	EphemeralPorts int `json:"ephemeral_ports"`
+
+	// Dial counts requests which failed to connect for any other reason
+	Dial int `json:"dial"`
+}
+
+// ErrorBody is a distinct error response returned by a target along with the
+// number of times it was seen
+type ErrorBody struct {
+	Code  uint16 `json:"code"`
+	Body  string `json:"body"`
+	Count int    `json:"count"`
 }
 
 type JSONReport struct {
-	TargetAddr string                     `json:"target_addr"`
-	Metrics    map[string]*vegeta.Metrics `json:"metrics"`
+	// Type is empty for reports. Other objects written alongside reports,
+	// such as summaries of several runs, set it so that FromReader can skip
+	// them.
+	Type          string                            `json:"type,omitempty"`
+	TargetAddr    string                            `json:"target_addr"`
+	Server        *ServerInfo                       `json:"server,omitempty"`
+	Metrics       map[string]*vegeta.Metrics        `json:"metrics"`
+	ErrorBodies   map[string][]ErrorBody            `json:"error_bodies,omitempty"`
+	Verifications map[string]*Verification          `json:"verifications,omitempty"`
+	Timeouts      map[string]int                    `json:"timeouts,omitempty"`
+	ClientErrors  map[string]*ClientErrors          `json:"client_errors,omitempty"`
+	Quotas        map[string]*QuotaEnforcement      `json:"quotas,omitempty"`
+	LeaseQuotas   map[string]*LeaseQuotaEnforcement `json:"lease_quotas,omitempty"`
 }
 
 func FromReader(r io.Reader) ([]*Reporter, error) {
@@ -49,9 +149,19 @@ func FromReader(r io.Reader) ([]*Reporter, error) {
 		if err := d.Decode(&unmarshaled); err != nil {
 			return nil, fmt.Errorf("could not decode report JSON (index %d): %w", len(reporters), err)
 		}
+		if unmarshaled.Type != "" {
+			continue
+		}
 		rpt := newReporter(&TargetMulti{}, nil)
 		rpt.clientAddr = unmarshaled.TargetAddr
+		rpt.server = unmarshaled.Server
 		rpt.metrics = unmarshaled.Metrics
+		rpt.errorSummaries = unmarshaled.ErrorBodies
+		rpt.verifications = unmarshaled.Verifications
+		rpt.timeouts = unmarshaled.Timeouts
+		rpt.clientErrors = unmarshaled.ClientErrors
+		rpt.quotas = unmarshaled.Quotas
+		rpt.leaseQuotas = unmarshaled.LeaseQuotas
 		reporters = append(reporters, rpt)
 	}
 	return reporters, nil
@@ -59,7 +169,10 @@ func FromReader(r io.Reader) ([]*Reporter, error) {
 
 func newReporter(tm *TargetMulti, client *api.Client) *Reporter {
 	clientAddress := "N/A"
-	if client != nil {
+	switch {
+	case tm.balancer != nil:
+		clientAddress = strings.Join(tm.balancer.addrs, ", ")
+	case client != nil:
 		clientAddress = client.Address()
 	}
 	r := &Reporter{tm: tm, clientAddr: clientAddress}
@@ -67,40 +180,389 @@ func newReporter(tm *TargetMulti, client *api.Client) *Reporter {
 	r.metrics["total"] = &vegeta.Metrics{}
 	for _, t := range tm.targets {
 		r.metrics[t.Name] = &vegeta.Metrics{}
+		if len(t.SuccessStatusCodes) > 0 {
+			r.successes = make(map[string]uint64, len(tm.targets)+1)
+		}
 	}
 	return r
 }
 
+// ResultConsumer receives the result of every request made during an attack
+// as it arrives, along with the name of the target the request was made to.
+// Results which can't be matched to a target have an empty target name.
+// Whether the request failed takes the target's success status codes into
+// account.
+type ResultConsumer interface {
+	Consume(target string, result *vegeta.Result, failed bool)
+}
+
+// match returns the target the result's request was made to, or nil if the
+// request doesn't match any target
+func (r *Reporter) match(result *vegeta.Result) *BenchmarkTarget {
+	var path string
+	if r.tm.balancer != nil {
+		var ok bool
+		if path, ok = r.tm.balancer.trimAddress(result.URL); !ok {
+			return nil
+		}
+	} else {
+		if !strings.HasPrefix(result.URL, r.clientAddr) {
+			return nil
+		}
+		path = result.URL[len(r.clientAddr):]
+	}
+
+	for i, target := range r.tm.targets {
+		if target.matches(result.Method, path) {
+			return &r.tm.targets[i]
+		}
+	}
+	return nil
+}
+
+// Metrics returns the metrics of the named target, or of every target when
+// name is "total"
+func (r *Reporter) Metrics(name string) (*vegeta.Metrics, bool) {
+	m, ok := r.metrics[name]
+	return m, ok
+}
+
+// MetricNames returns the names of the targets with metrics, sorted, along
+// with "total"
+func (r *Reporter) MetricNames() []string {
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (r *Reporter) Add(result *vegeta.Result) {
+	r.add(r.match(result), result)
+}
+
+func (r *Reporter) add(target *BenchmarkTarget, result *vegeta.Result) {
 	r.metrics["total"].Add(result)
+	succeeded := r.successes != nil && target.succeeded(result)
+	if succeeded {
+		r.successes["total"]++
+	}
+	// TODO what if we didn't find any match?
+	if target == nil {
+		return
+	}
+
+	r.metrics[target.Name].Add(result)
+	if succeeded {
+		r.successes[target.Name]++
+	}
+	attackResult.WithLabelValues(target.Name).Observe(result.Latency.Seconds())
+	if result.Error != "" {
+		attackErrors.WithLabelValues(target.Name, result.Error).Inc()
+	}
+	if isTimeout(result) {
+		if r.timeouts == nil {
+			r.timeouts = make(map[string]int)
+		}
+		r.timeouts[target.Name]++
+	}
+	r.addClientError(target.Name, result)
+	// Responses with one of the target's success status codes aren't errors
+	if len(target.SuccessStatusCodes) == 0 || result.Error != "" {
+		r.addErrorBody(target.Name, result)
+	}
+}
+
+// isTimeout returns true if the result's request was cut off by the HTTP
+// client's timeout
+func isTimeout(result *vegeta.Result) bool {
+	return strings.Contains(result.Error, "Client.Timeout")
+}
+
+// addClientError records the result if its request failed to connect
+func (r *Reporter) addClientError(name string, result *vegeta.Result) {
+	if result.Code != 0 || result.Error == "" {
+		return
+	}
+
+	var count func(*ClientErrors)
+	switch {
+	case strings.Contains(result.Error, "too many open files"):
+		count = func(c *ClientErrors) { c.OpenFiles++ }
+	case strings.Contains(result.Error, "cannot assign requested address"):
+		count = func(c *ClientErrors) { c.EphemeralPorts++ }
+	case strings.Contains(result.Error, "dial "):
+		count = func(c *ClientErrors) { c.Dial++ }
+	default:
+		return
+	}
+
+	if r.clientErrors == nil {
+		r.clientErrors = make(map[string]*ClientErrors)
+	}
+	if r.clientErrors[name] == nil {
+		r.clientErrors[name] = &ClientErrors{}
+	}
+	count(r.clientErrors[name])
+}
+
+// addErrorBody records the body of result if it is an error response
+func (r *Reporter) addErrorBody(name string, result *vegeta.Result) {
+	if r.errorBodies <= 0 || result.Code == 0 || (result.Code >= 200 && result.Code < 400) {
+		return
+	}
+
+	if r.errorBodyCount == nil {
+		r.errorBodyCount = make(map[string]map[string]int)
+	}
+	counts, ok := r.errorBodyCount[name]
+	if !ok {
+		counts = make(map[string]int)
+		r.errorBodyCount[name] = counts
+	}
+
+	body := strings.TrimSpace(string(result.Body))
+	if len(body) > maxErrorBodyLen {
+		body = body[:maxErrorBodyLen] + "..."
+	}
+	key := fmt.Sprintf("%d %s", result.Code, body)
+	if _, ok := counts[key]; !ok && len(counts) >= maxDistinctErrorBodies {
+		key = fmt.Sprintf("%d (other)", result.Code)
+	}
+	counts[key]++
+}
+
+// summarizeErrorBodies keeps the most frequent error bodies of each target
+func (r *Reporter) summarizeErrorBodies() {
+	if len(r.errorBodyCount) == 0 {
+		return
+	}
+
+	r.errorSummaries = make(map[string][]ErrorBody, len(r.errorBodyCount))
+	for name, counts := range r.errorBodyCount {
+		summary := make([]ErrorBody, 0, len(counts))
+		for key, count := range counts {
+			code, body, _ := strings.Cut(key, " ")
+			parsedCode, _ := strconv.ParseUint(code, 10, 16)
+			summary = append(summary, ErrorBody{Code: uint16(parsedCode), Body: body, Count: count})
+		}
+		sort.Slice(summary, func(i, j int) bool {
+			if summary[i].Count != summary[j].Count {
+				return summary[i].Count > summary[j].Count
+			}
+			return summary[i].Body < summary[j].Body
+		})
+		if len(summary) > r.errorBodies {
+			summary = summary[:r.errorBodies]
+		}
+		r.errorSummaries[name] = summary
+	}
+	r.errorBodyCount = nil
+}
+
+// reportErrorBodies writes the summarized error bodies of each target
+func (r *Reporter) reportErrorBodies(w io.Writer) {
+	if len(r.errorSummaries) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(r.errorSummaries))
+	for name := range r.errorSummaries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Error responses:")
+	for _, name := range names {
+		fmt.Fprintf(w, "%s\n", name)
+		for _, e := range r.errorSummaries[name] {
+			fmt.Fprintf(w, "  %d x %d: %s\n", e.Count, e.Code, e.Body)
+		}
+	}
+}
+
+// reportTimeouts writes the number of requests to each target which timed
+// out
+func (r *Reporter) reportTimeouts(w io.Writer) {
+	if len(r.timeouts) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(r.timeouts))
+	for name := range r.timeouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Timeouts:")
+	for _, name := range names {
+		fmt.Fprintf(w, "%s: %d\n", name, r.timeouts[name])
+	}
+}
+
+// reportClientErrors writes the number of requests to each target which
+// failed to connect, so that they aren't mistaken for errors returned by Vault
+func (r *Reporter) reportClientErrors(w io.Writer) {
+	if len(r.clientErrors) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(r.clientErrors))
+	for name := range r.clientErrors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Client-side errors:")
+	for _, name := range names {
+		c := r.clientErrors[name]
+		fmt.Fprintf(w, "%s: %d open file limit, %d ephemeral ports exhausted, %d other connection failures\n", name, c.OpenFiles, c.EphemeralPorts, c.Dial)
+	}
+}
+
+// reportVerifications writes the read-back check counts of each target
+func (r *Reporter) reportVerifications(w io.Writer) {
+	if len(r.verifications) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(r.verifications))
+	for name := range r.verifications {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Verification:")
+	for _, name := range names {
+		v := r.verifications[name]
+		fmt.Fprintf(w, "%s: %d checked, %d mismatched, %d errors\n", name, v.Checked, v.Mismatched, v.Errors)
+		if v.Skipped > 0 {
+			fmt.Fprintf(w, "  skipped: %d, the backlog of checks was full\n", v.Skipped)
+		}
+		if v.Lag != nil {
+			fmt.Fprintf(w, "  lag: mean %s, 95th %s, 99th %s, max %s\n", v.Lag.Mean, v.Lag.P95, v.Lag.P99, v.Lag.Max)
+		}
+	}
+}
+
+// summarizeQuotas compares the closed metrics of each rate or lease limited
+// target with what its quota allows
+func (r *Reporter) summarizeQuotas() {
+	rejected := strconv.Itoa(http.StatusTooManyRequests)
 	for _, target := range r.tm.targets {
-		if result.Method == target.Method && strings.HasPrefix(result.URL, r.clientAddr+target.PathPrefix) {
-			r.metrics[target.Name].Add(result)
-			attackResult.WithLabelValues(target.Name).Observe(result.Latency.Seconds())
-			if result.Error != "" {
-				attackErrors.WithLabelValues(target.Name, result.Error).Inc()
+		m, ok := r.metrics[target.Name]
+		if !ok {
+			continue
+		}
+		if limited, ok := target.Builder.(RateLimited); ok {
+			if r.quotas == nil {
+				r.quotas = make(map[string]*QuotaEnforcement)
+			}
+			r.quotas[target.Name] = &QuotaEnforcement{
+				Configured: limited.QuotaRate(),
+				Enforced:   m.Throughput,
+				Rejected:   m.StatusCodes[rejected],
+			}
+		}
+		if limited, ok := target.Builder.(LeaseLimited); ok {
+			var accepted int
+			for code, count := range m.StatusCodes {
+				if strings.HasPrefix(code, "2") {
+					accepted += count
+				}
+			}
+			if r.leaseQuotas == nil {
+				r.leaseQuotas = make(map[string]*LeaseQuotaEnforcement)
+			}
+			r.leaseQuotas[target.Name] = &LeaseQuotaEnforcement{
+				Configured: limited.QuotaMaxLeases(),
+				Accepted:   accepted,
+				Rejected:   m.StatusCodes[rejected],
 			}
-			break
 		}
 	}
-	// TODO what if we didn't find any match?
+}
+
+// reportQuotas writes what the quota of each rate or lease limited target
+// allows along with what was enforced
+func (r *Reporter) reportQuotas(w io.Writer) {
+	if len(r.quotas) > 0 {
+		names := make([]string, 0, len(r.quotas))
+		for name := range r.quotas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Rate limit quotas:")
+		for _, name := range names {
+			q := r.quotas[name]
+			fmt.Fprintf(w, "%s: configured %.2f/s, enforced %.2f/s, %d rejected\n", name, q.Configured, q.Enforced, q.Rejected)
+		}
+	}
+
+	if len(r.leaseQuotas) > 0 {
+		names := make([]string, 0, len(r.leaseQuotas))
+		for name := range r.leaseQuotas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Lease count quotas:")
+		for _, name := range names {
+			q := r.leaseQuotas[name]
+			fmt.Fprintf(w, "%s: configured %d leases, %d accepted, %d rejected\n", name, q.Configured, q.Accepted, q.Rejected)
+		}
+	}
+}
+
+// SetServerInfo records the build of the server the requests were sent to
+func (r *Reporter) SetServerInfo(server *ServerInfo) {
+	r.server = server
 }
 
 func (r *Reporter) Close() {
-	for name := range r.metrics {
-		r.metrics[name].Close()
+	for name, m := range r.metrics {
+		m.Close()
+		if r.successes == nil || m.Requests == 0 {
+			continue
+		}
+		successes := float64(r.successes[name])
+		m.Success = successes / float64(m.Requests)
+		m.Throughput = successes
+		if m.Duration > 0 {
+			m.Throughput /= (m.Duration + m.Wait).Seconds()
+		}
 	}
+	r.summarizeErrorBodies()
+	r.summarizeQuotas()
 }
 
 func (r *Reporter) ReportJSON(w io.Writer) error {
 	j := json.NewEncoder(w)
 	return j.Encode(&JSONReport{
-		TargetAddr: r.clientAddr,
-		Metrics:    r.metrics,
+		TargetAddr:    r.clientAddr,
+		Server:        r.server,
+		Metrics:       r.metrics,
+		ErrorBodies:   r.errorSummaries,
+		Verifications: r.verifications,
+		Timeouts:      r.timeouts,
+		ClientErrors:  r.clientErrors,
+		Quotas:        r.quotas,
+		LeaseQuotas:   r.leaseQuotas,
 	})
 }
 
 func (r *Reporter) ReportVerbose(w io.Writer) error {
+	if r.server != nil {
+		fmt.Fprintf(w, "Server: %v\n", r.server)
+	}
 	sections := make([]string, 0, len(r.metrics))
 	for name := range r.metrics {
 		sections = append(sections, name)
@@ -118,12 +580,20 @@ func (r *Reporter) ReportVerbose(w io.Writer) error {
 			return fmt.Errorf("report error: %v", err)
 		}
 	}
+	r.reportTimeouts(w)
+	r.reportClientErrors(w)
+	r.reportErrorBodies(w)
+	r.reportVerifications(w)
+	r.reportQuotas(w)
 	return nil
 }
 
 func (r *Reporter) ReportTerse(w io.Writer) error {
 	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.StripEscape)
 	fmt.Fprintf(tw, "Target: %v\n", r.clientAddr)
+	if r.server != nil {
+		fmt.Fprintf(tw, "Server: %v\n", r.server)
+	}
 	fmt.Fprintf(tw, "op\tcount\trate\tthroughput\tmean\t95th%%\t99th%%\tsuccessRatio\n")
 	const fmtstr = "%s\t%d\t%f\t%f\t%s\t%s\t%s\t%.2f%%\n"
 
@@ -143,5 +613,10 @@ func (r *Reporter) ReportTerse(w io.Writer) error {
 		}
 	}
 	tw.Flush()
+	r.reportTimeouts(w)
+	r.reportClientErrors(w)
+	r.reportErrorBodies(w)
+	r.reportVerifications(w)
+	r.reportQuotas(w)
 	return nil
 }
@@ -8,6 +8,8 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+
+	vegeta "github.com/tsenart/vegeta/v12/lib"
 )
 
 func TestReportJSONRoundTrip(t *testing.T) {
@@ -52,3 +54,182 @@ func TestReportJSONRoundTrip(t *testing.T) {
 		t.Fatalf("expected reports to be unchanged after round trip: %v", reports2)
 	}
 }
+
+func TestReporter_ErrorBodies(t *testing.T) {
+	tm := &TargetMulti{targets: []BenchmarkTarget{
+		{Name: "kvv2_read_test", Method: "GET", PathPrefix: "/v1/secret"},
+	}}
+	rpt := newReporter(tm, nil)
+	rpt.errorBodies = 1
+
+	add := func(code uint16, body string) {
+		rpt.Add(&vegeta.Result{
+			Method: "GET",
+			URL:    "N/A/v1/secret/data/secret-1",
+			Code:   code,
+			Body:   []byte(body),
+		})
+	}
+	add(200, `{"data":{}}`)
+	add(403, `{"errors":["permission denied"]}`)
+	add(429, `{"errors":["request path \"secret/\": rate limit quota exceeded"]}`)
+	add(429, `{"errors":["request path \"secret/\": rate limit quota exceeded"]}`)
+	rpt.Close()
+
+	summary := rpt.errorSummaries["kvv2_read_test"]
+	if len(summary) != 1 {
+		t.Fatalf("expected 1 error body, got: %v", summary)
+	}
+	if summary[0].Code != 429 || summary[0].Count != 2 {
+		t.Fatalf("expected rate limit error to be reported, got: %v", summary[0])
+	}
+
+	var buf bytes.Buffer
+	if err := rpt.ReportTerse(&buf); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "2 x 429: ") {
+		t.Fatalf("expected error bodies in report, got: %s", buf.String())
+	}
+}
+
+func TestReporter_Timeouts(t *testing.T) {
+	tm := &TargetMulti{targets: []BenchmarkTarget{
+		{Name: "kvv2_read_test", Method: "GET", PathPrefix: "/v1/secret"},
+	}}
+	rpt := newReporter(tm, nil)
+
+	add := func(err string) {
+		rpt.Add(&vegeta.Result{
+			Method: "GET",
+			URL:    "N/A/v1/secret/data/secret-1",
+			Error:  err,
+		})
+	}
+	add(`Get "N/A/v1/secret/data/secret-1": context deadline exceeded (Client.Timeout exceeded while awaiting headers)`)
+	add(`Get "N/A/v1/secret/data/secret-1": dial tcp: connection refused`)
+	rpt.Close()
+
+	if rpt.timeouts["kvv2_read_test"] != 1 {
+		t.Fatalf("expected 1 timeout, got: %v", rpt.timeouts)
+	}
+
+	var buf bytes.Buffer
+	if err := rpt.ReportTerse(&buf); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Timeouts:\nkvv2_read_test: 1\n") {
+		t.Fatalf("expected timeouts in report, got: %s", buf.String())
+	}
+}
+
+func TestReporter_ClientErrors(t *testing.T) {
+	tm := &TargetMulti{targets: []BenchmarkTarget{
+		{Name: "kvv2_read_test", Method: "GET", PathPrefix: "/v1/secret"},
+	}}
+	rpt := newReporter(tm, nil)
+
+	add := func(code uint16, err string) {
+		rpt.Add(&vegeta.Result{
+			Method: "GET",
+			URL:    "N/A/v1/secret/data/secret-1",
+			Code:   code,
+			Error:  err,
+		})
+	}
+	add(0, `Get "N/A/v1/secret/data/secret-1": dial tcp 127.0.0.1:8200: socket: too many open files`)
+	add(0, `Get "N/A/v1/secret/data/secret-1": dial tcp 127.0.0.1:8200: connect: cannot assign requested address`)
+	add(0, `Get "N/A/v1/secret/data/secret-1": dial tcp 127.0.0.1:8200: connect: connection refused`)
+	add(500, "500 Internal Server Error")
+	rpt.Close()
+
+	expected := ClientErrors{OpenFiles: 1, EphemeralPorts: 1, Dial: 1}
+	if c := rpt.clientErrors["kvv2_read_test"]; c == nil || *c != expected {
+		t.Fatalf("expected %+v, got: %+v", expected, c)
+	}
+
+	var buf bytes.Buffer
+	if err := rpt.ReportTerse(&buf); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Client-side errors:\nkvv2_read_test: 1 open file limit, 1 ephemeral ports exhausted, 1 other connection failures\n") {
+		t.Fatalf("expected client-side errors in report, got: %s", buf.String())
+	}
+}
+
+func TestReporter_Quotas(t *testing.T) {
+	tm := &TargetMulti{targets: []BenchmarkTarget{
+		{Name: "quota_test", Method: "GET", PathPrefix: "/v1/limited", Builder: &RateLimitQuotaTest{rate: 10}},
+		{Name: "lease_test", Method: "POST", PathPrefix: "/v1/auth/limited", Builder: &LeaseCountQuotaTest{config: &LeaseCountQuotaTestConfig{MaxLeases: 2}}},
+		{Name: "kvv2_read_test", Method: "GET", PathPrefix: "/v1/secret", Builder: &KVV2Test{}},
+	}}
+	rpt := newReporter(tm, nil)
+
+	for _, code := range []uint16{200, 429, 429} {
+		rpt.Add(&vegeta.Result{
+			Method: "GET",
+			URL:    "N/A/v1/limited/secret",
+			Code:   code,
+		})
+	}
+	for _, code := range []uint16{200, 200, 429} {
+		rpt.Add(&vegeta.Result{
+			Method: "POST",
+			URL:    "N/A/v1/auth/limited/login/benchmark-user",
+			Code:   code,
+		})
+	}
+	rpt.Close()
+
+	q, ok := rpt.quotas["quota_test"]
+	if !ok || len(rpt.quotas) != 1 {
+		t.Fatalf("expected only quota_test to be rate limited, got: %v", rpt.quotas)
+	}
+	if q.Configured != 10 || q.Rejected != 2 {
+		t.Fatalf("expected 10/s configured and 2 rejected, got: %+v", q)
+	}
+
+	lq, ok := rpt.leaseQuotas["lease_test"]
+	if !ok || len(rpt.leaseQuotas) != 1 {
+		t.Fatalf("expected only lease_test to be lease limited, got: %v", rpt.leaseQuotas)
+	}
+	if expected := (LeaseQuotaEnforcement{Configured: 2, Accepted: 2, Rejected: 1}); *lq != expected {
+		t.Fatalf("expected %+v, got: %+v", expected, *lq)
+	}
+
+	var buf bytes.Buffer
+	if err := rpt.ReportTerse(&buf); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Rate limit quotas:\nquota_test: configured 10.00/s") {
+		t.Fatalf("expected quotas in report, got: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "Lease count quotas:\nlease_test: configured 2 leases, 2 accepted, 1 rejected\n") {
+		t.Fatalf("expected lease quotas in report, got: %s", buf.String())
+	}
+}
+
+func TestReporter_ServerInfo(t *testing.T) {
+	rpt := newReporter(&TargetMulti{}, nil)
+	rpt.SetServerInfo(&ServerInfo{Version: "2.1.0", BuildDate: "2024-11-29T14:31:54Z"})
+
+	var buf bytes.Buffer
+	if err := rpt.ReportJSON(&buf); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	reports, err := FromReader(&buf)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if server := reports[0].server; server == nil || server.Version != "2.1.0" || server.BuildDate != "2024-11-29T14:31:54Z" {
+		t.Fatalf("expected the server build to be kept, got: %+v", server)
+	}
+
+	buf.Reset()
+	if err := reports[0].ReportTerse(&buf); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Server: 2.1.0 (built 2024-11-29T14:31:54Z)") {
+		t.Fatalf("expected the server build in the report, got: %v", buf.String())
+	}
+}
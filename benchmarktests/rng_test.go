@@ -0,0 +1,32 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import "testing"
+
+func TestNewRand(t *testing.T) {
+	sequence := func(seed int64) []int {
+		d, err := newKeyDistribution(NewRand(seed), 1000, AccessDistributionZipfian, 1.1)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		keys := make([]int, 100)
+		for i := range keys {
+			keys[i] = d.next()
+		}
+		return keys
+	}
+
+	first, second, other := sequence(42), sequence(42), sequence(7)
+	same := true
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("expected the same keys for the same seed, got %v and %v", first, second)
+		}
+		same = same && first[i] == other[i]
+	}
+	if same {
+		t.Fatal("expected different keys for a different seed")
+	}
+}
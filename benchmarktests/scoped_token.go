@@ -0,0 +1,126 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+// methodCapabilities are the policy capabilities needed to send a request
+// with each method
+var methodCapabilities = map[string][]string{
+	http.MethodGet:    {"read"},
+	http.MethodHead:   {"read"},
+	http.MethodPost:   {"create", "update"},
+	http.MethodPut:    {"create", "update"},
+	http.MethodPatch:  {"patch"},
+	http.MethodDelete: {"delete"},
+	"LIST":            {"list"},
+}
+
+// scopedToken is a token created for a single test which is only allowed to
+// send the test's requests
+type scopedToken struct {
+	policy   string
+	token    string
+	accessor string
+
+	// setupToken is the token used to set the test up, which is the only
+	// one replaced in its requests
+	setupToken string
+}
+
+// scopedPolicy returns a policy granting exactly the capabilities needed to
+// send requests with the target's method under its path prefix
+func scopedPolicy(info TargetInfo) (string, error) {
+	capabilities, ok := methodCapabilities[strings.ToUpper(info.method)]
+	if !ok {
+		return "", fmt.Errorf("no capabilities known for method %q", info.method)
+	}
+	path := strings.TrimPrefix(info.pathPrefix, "/v1/")
+	if path == info.pathPrefix || path == "" {
+		return "", fmt.Errorf("can't scope a token to path %q", info.pathPrefix)
+	}
+	return fmt.Sprintf("path %q {\n  capabilities = [\"%s\"]\n}\n", path+"*", strings.Join(capabilities, `", "`)), nil
+}
+
+// setupScopedToken writes a policy for the set up target and creates a token
+// with only that policy
+func (bt *BenchmarkTarget) setupScopedToken(client *api.Client, config *TopLevelTargetConfig) error {
+	policy, err := scopedPolicy(bt.Builder.GetTargetInfo())
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return fmt.Errorf("can't create UUID: %v", err)
+	}
+	st := &scopedToken{
+		policy:     "benchmark-" + bt.Name + "-" + id[:8],
+		setupToken: client.Token(),
+	}
+
+	targetLogger.Trace("writing scoped policy", "target", bt.Name, "policy", st.policy)
+	err = retrySetup(config, func() error {
+		return client.Sys().PutPolicy(st.policy, policy)
+	})
+	if err != nil {
+		return fmt.Errorf("error writing scoped policy: %v", err)
+	}
+
+	var secret *api.Secret
+	err = retrySetup(config, func() error {
+		var err error
+		secret, err = client.Auth().Token().Create(&api.TokenCreateRequest{
+			Policies:        []string{st.policy},
+			NoDefaultPolicy: true,
+			DisplayName:     "benchmark-" + bt.Name,
+		})
+		return err
+	})
+	if err != nil {
+		_ = client.Sys().DeletePolicy(st.policy)
+		return fmt.Errorf("error creating scoped token: %v", err)
+	}
+	st.token = secret.Auth.ClientToken
+	st.accessor = secret.Auth.Accessor
+
+	bt.scopedToken = st
+	return nil
+}
+
+// target sends the wrapped target's request with the scoped token in place of
+// the token used to set the test up. Requests sent with a token of the test's
+// own, or without one, are left as they are.
+func (st *scopedToken) target(target func(*api.Client) vegeta.Target) func(*api.Client) vegeta.Target {
+	return func(client *api.Client) vegeta.Target {
+		t := target(client)
+		if t.Header.Get("X-Vault-Token") != st.setupToken {
+			return t
+		}
+		t.Header = t.Header.Clone()
+		t.Header.Set("X-Vault-Token", st.token)
+		return t
+	}
+}
+
+// cleanup revokes the scoped token and deletes its policy
+func (st *scopedToken) cleanup(client *api.Client) error {
+	var errs []error
+	if err := client.Auth().Token().RevokeAccessor(st.accessor); err != nil {
+		errs = append(errs, fmt.Errorf("error revoking scoped token: %v", err))
+	}
+	if err := client.Sys().DeletePolicy(st.policy); err != nil {
+		errs = append(errs, fmt.Errorf("error deleting scoped policy: %v", err))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+func TestScopedPolicy(t *testing.T) {
+	policy, err := scopedPolicy(TargetInfo{method: "POST", pathPrefix: "/v1/kvv2/data"})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	expected := "path \"kvv2/data*\" {\n  capabilities = [\"create\", \"update\"]\n}\n"
+	if policy != expected {
+		t.Fatalf("expected policy:\n%s\ngot:\n%s", expected, policy)
+	}
+
+	if _, err := scopedPolicy(TargetInfo{method: "CONNECT", pathPrefix: "/v1/kvv2/data"}); err == nil {
+		t.Fatal("expected error for unknown method")
+	}
+	if _, err := scopedPolicy(TargetInfo{method: "GET", pathPrefix: "kvv2"}); err == nil {
+		t.Fatal("expected error for a path which isn't known yet")
+	}
+}
+
+func TestScopedToken_Target(t *testing.T) {
+	st := &scopedToken{token: "scoped", setupToken: "root"}
+	header := http.Header{"X-Vault-Token": []string{"root"}}
+	target := st.target(func(*api.Client) vegeta.Target {
+		return vegeta.Target{Method: "GET", URL: "/v1/kvv2/data/0", Header: header}
+	})
+
+	if got := target(nil).Header.Get("X-Vault-Token"); got != "scoped" {
+		t.Fatalf("expected scoped token, got: %q", got)
+	}
+	// The test's header is shared by its requests and must not be changed
+	if got := header.Get("X-Vault-Token"); got != "root" {
+		t.Fatalf("expected test header to be unchanged, got: %q", got)
+	}
+
+	// Requests sent with the test's own token keep it
+	header.Set("X-Vault-Token", "own")
+	if got := target(nil).Header.Get("X-Vault-Token"); got != "own" {
+		t.Fatalf("expected test's own token, got: %q", got)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"fmt"
+
+	"github.com/openbao/openbao/api/v2"
+)
+
+// ServerInfo identifies the build of the server a report's requests were sent
+// to, so that reports are self-describing when comparing results across
+// upgrades
+type ServerInfo struct {
+	Version   string `json:"version"`
+	BuildDate string `json:"build_date,omitempty"`
+}
+
+// ReadServerInfo reads the version and build date of the client's server.
+// Unlike sys/health, sys/seal-status reports the build date as well as the
+// version, and neither requires a token.
+func ReadServerInfo(client *api.Client) (*ServerInfo, error) {
+	// sys/seal-status is only served from the root namespace
+	status, err := client.WithNamespace("").Sys().SealStatus()
+	if err != nil {
+		return nil, fmt.Errorf("error reading seal status: %v", err)
+	}
+	if status.Version == "" {
+		return nil, fmt.Errorf("server didn't report its version")
+	}
+	return &ServerInfo{
+		Version:   status.Version,
+		BuildDate: status.BuildDate,
+	}, nil
+}
+
+func (s *ServerInfo) String() string {
+	if s.BuildDate == "" {
+		return s.Version
+	}
+	return s.Version + " (built " + s.BuildDate + ")"
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"fmt"
+	"slices"
+
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+// classify applies the target's success status codes to the result of a
+// request made to it, before the result is reported. Responses with one of
+// the codes have their error cleared, and any other response is given one,
+// so that reports and result consumers agree that a request failed when its
+// result has an error. Results of targets without success status codes, or
+// which don't match a target, are left as they are.
+func (t *BenchmarkTarget) classify(result *vegeta.Result) {
+	if t == nil || len(t.SuccessStatusCodes) == 0 || result.Code == 0 {
+		return
+	}
+	if slices.Contains(t.SuccessStatusCodes, int(result.Code)) {
+		result.Error = ""
+		return
+	}
+	if result.Error == "" {
+		result.Error = fmt.Sprintf("unexpected status code %d", result.Code)
+	}
+}
+
+// succeeded returns true if the request of a result which has been
+// classified succeeded. Results of targets without success status codes are
+// counted as vegeta counts them.
+func (t *BenchmarkTarget) succeeded(result *vegeta.Result) bool {
+	if t == nil || len(t.SuccessStatusCodes) == 0 {
+		return result.Code >= 200 && result.Code < 400
+	}
+	return result.Error == ""
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"testing"
+	"time"
+
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+func TestReporter_SuccessStatusCodes(t *testing.T) {
+	tm := &TargetMulti{targets: []BenchmarkTarget{
+		{Name: "read_deleted", Method: "GET", PathPrefix: "/v1/kv", SuccessStatusCodes: []int{404}},
+	}}
+	rpt := newReporter(tm, nil)
+	rpt.clientAddr = "http://127.0.0.1:8200"
+
+	// A deleted secret is expected to be missing, while finding it isn't
+	start := time.Now()
+	for i, code := range []uint16{404, 404, 404, 200} {
+		result := &vegeta.Result{Method: "GET", URL: "http://127.0.0.1:8200/v1/kv/secret-1", Code: code, Timestamp: start.Add(time.Duration(i) * time.Second)}
+		if code == 404 {
+			result.Error = "404 Not Found"
+		}
+		target := rpt.match(result)
+		target.classify(result)
+		rpt.add(target, result)
+	}
+	rpt.Close()
+
+	m, _ := rpt.Metrics("read_deleted")
+	if m.Success != 0.75 {
+		t.Fatalf("expected a success ratio of 0.75, got %v", m.Success)
+	}
+	if len(m.Errors) != 1 || m.Errors[0] != "unexpected status code 200" {
+		t.Fatalf("expected only the 200 to be an error, got %v", m.Errors)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	AgentCacheTestType   = "agent_cache_read"
+	AgentCacheTestMethod = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[AgentCacheTestType] = func() BenchmarkBuilder { return &AgentCacheTest{} }
+}
+
+// AgentCacheTest repeatedly makes the same few requests for leased secrets,
+// certificates issued by a PKI role which generates leases. An OpenBao Agent
+// or Proxy with caching enabled returns the cached lease for every request
+// after the first of each, so that its cache hits can be compared with
+// requests sent directly to Vault, which issue a new lease every time.
+type AgentCacheTest struct {
+	pathPrefix string
+	mountPath  string
+	header     http.Header
+	config     *AgentCacheTestConfig
+	rng        *rand.Rand
+	logger     hclog.Logger
+}
+
+type AgentCacheTestConfig struct {
+	NumLeases int    `hcl:"num_leases,optional"`
+	TTL       string `hcl:"ttl,optional"`
+}
+
+func (a *AgentCacheTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *AgentCacheTestConfig `hcl:"config,block"`
+	}{
+		Config: &AgentCacheTestConfig{
+			NumLeases: 10,
+			TTL:       "1h",
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	a.config = testConfig.Config
+
+	if a.config.NumLeases < 1 {
+		return fmt.Errorf("num_leases must be at least 1")
+	}
+	return nil
+}
+
+func (a *AgentCacheTest) Target(client *api.Client) vegeta.Target {
+	// The cache is keyed by the request, so each distinct common name is a
+	// distinct lease
+	body, err := json.Marshal(map[string]interface{}{
+		"common_name": fmt.Sprintf("cache-%d.example.com", a.rng.Intn(a.config.NumLeases)),
+	})
+	if err != nil {
+		a.logger.Error("error marshaling agent cache request", "error", err)
+	}
+
+	return vegeta.Target{
+		Method: AgentCacheTestMethod,
+		URL:    client.Address() + a.pathPrefix,
+		Body:   body,
+		Header: a.header,
+	}
+}
+
+func (a *AgentCacheTest) Cleanup(client *api.Client) error {
+	// Unmounting the PKI mount revokes the leases issued during the test
+	a.logger.Trace(cleanupLogMessage(a.mountPath))
+	_, err := client.Logical().Delete("/sys/mounts/" + a.mountPath)
+	if err != nil {
+		return fmt.Errorf("error cleaning up mount: %v", err)
+	}
+	return nil
+}
+
+func (a *AgentCacheTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     AgentCacheTestMethod,
+		pathPrefix: a.pathPrefix,
+	}
+}
+
+func (a *AgentCacheTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	mountPath := mountName
+	a.logger = targetLogger.Named(AgentCacheTestType)
+
+	if topLevelConfig.RandomMounts {
+		mountPath, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	leasePath, err := setupLeasePKI(client, mountPath, a.config.TTL, a.logger, topLevelConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AgentCacheTest{
+		pathPrefix: "/v1/" + leasePath,
+		mountPath:  mountPath,
+		header:     generateHeader(client),
+		config:     a.config,
+		rng:        topLevelConfig.Rand,
+		logger:     a.logger,
+	}, nil
+}
+
+func (a *AgentCacheTest) Flags(fs *flag.FlagSet) {}
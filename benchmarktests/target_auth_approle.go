@@ -91,7 +91,7 @@ func (a *ApproleAuth) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -138,8 +138,10 @@ func (a *ApproleAuth) Setup(client *api.Client, mountName string, topLevelConfig
 
 	// Create AppRole Auth Mount
 	a.logger.Trace(mountLogMessage("auth", "approle", authPath))
-	err = client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
-		Type: "approle",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
+			Type: "approle",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error enabling approle auth: %v", err)
@@ -103,7 +103,7 @@ func (a *AWSAuth) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -161,8 +161,10 @@ func (a *AWSAuth) Setup(client *api.Client, mountName string, topLevelConfig *To
 
 	// Create AWS Auth mount
 	a.logger.Trace(mountLogMessage("auth", "aws", authPath))
-	err = client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
-		Type: "aws",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
+			Type: "aws",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error enabling aws: %v", err)
@@ -102,7 +102,7 @@ func (a *AzureAuth) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -156,8 +156,10 @@ func (a *AzureAuth) Setup(client *api.Client, mountName string, topLevelConfig *
 
 	// Create Azure Auth mount
 	a.logger.Trace(mountLogMessage("auth", "azure", authPath))
-	err = client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
-		Type: "azure",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
+			Type: "azure",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error enabling azure: %v", err)
@@ -20,6 +20,7 @@ import (
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/openbao/openbao/api/v2"
 	vegeta "github.com/tsenart/vegeta/v12/lib"
+	"golang.org/x/net/http2"
 )
 
 // Constants for test
@@ -80,7 +81,7 @@ func (c *CertAuth) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -140,7 +141,9 @@ func (c *CertAuth) Setup(client *api.Client, mountName string, topLevelConfig *T
 		// Create new client with newly generated cert
 		c.logger.Trace("creating new client with generated cert")
 		tClientConfig := client.CloneConfig()
-		tClientConfig.HttpClient.Transport.(*http.Transport).TLSClientConfig.Certificates = []tls.Certificate{keyPair}
+		if err := setClientCertificate(tClientConfig, keyPair); err != nil {
+			return nil, fmt.Errorf("failed to configure vault client with client cert: %v", err)
+		}
 
 		nClient, err := api.NewClient(tClientConfig)
 		if err != nil {
@@ -158,8 +161,10 @@ func (c *CertAuth) Setup(client *api.Client, mountName string, topLevelConfig *T
 
 	// Create Cert Auth mount
 	c.logger.Trace(mountLogMessage("auth", "cert", authPath))
-	err = client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
-		Type: "cert",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
+			Type: "cert",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error enabling cert auth: %v", err)
@@ -198,4 +203,23 @@ func (c *CertAuth) Cleanup(client *api.Client) error {
 	return nil
 }
 
+// setClientCertificate sets the certificate presented by the client's
+// transport, which force_http2 replaces with an HTTP/2 transport
+func setClientCertificate(config *api.Config, cert tls.Certificate) error {
+	var tlsConfig *tls.Config
+	switch transport := config.HttpClient.Transport.(type) {
+	case *http.Transport:
+		tlsConfig = transport.TLSClientConfig
+	case *http2.Transport:
+		tlsConfig = transport.TLSClientConfig
+	default:
+		return fmt.Errorf("unsupported transport %T", transport)
+	}
+	if tlsConfig == nil {
+		return fmt.Errorf("client does not use TLS")
+	}
+	tlsConfig.Certificates = []tls.Certificate{cert}
+	return nil
+}
+
 func (c *CertAuth) Flags(fs *flag.FlagSet) {}
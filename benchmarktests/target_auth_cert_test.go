@@ -0,0 +1,33 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+
+	"github.com/openbao/openbao/api/v2"
+	"golang.org/x/net/http2"
+)
+
+func TestSetClientCertificate(t *testing.T) {
+	cert := tls.Certificate{Certificate: [][]byte{[]byte("cert")}}
+
+	// force_http2 replaces the client's transport with an HTTP/2 one
+	h2 := &http2.Transport{TLSClientConfig: &tls.Config{}}
+	config := api.DefaultConfig()
+	config.HttpClient = &http.Client{Transport: h2}
+	if err := setClientCertificate(config, cert); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(h2.TLSClientConfig.Certificates) != 1 {
+		t.Fatal("expected the certificate to be set on the HTTP/2 transport")
+	}
+
+	config.HttpClient = &http.Client{Transport: &http2.Transport{AllowHTTP: true}}
+	if err := setClientCertificate(config, cert); err == nil {
+		t.Fatal("expected error without TLS")
+	}
+}
@@ -99,7 +99,7 @@ func (g *GCPAuth) ParseConfig(body hcl.Body) error {
 	},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -146,8 +146,10 @@ func (g *GCPAuth) Setup(client *api.Client, mountName string, topLevelConfig *To
 	}
 
 	g.logger.Trace(mountLogMessage("auth", "gcp", authPath))
-	err = client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
-		Type: "gcp",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
+			Type: "gcp",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error enabling gcp: %v", err)
@@ -202,7 +204,7 @@ func (g *GCPAuth) Setup(client *api.Client, mountName string, topLevelConfig *To
 		return nil, fmt.Errorf("error writing gcp role: %v", err)
 	}
 
-	jwt, err := getSignedJwt(g.config)
+	jwt, err := getSignedJwt(g.config, topLevelConfig.Rand)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching JWT: %v", err)
 	}
@@ -220,7 +222,7 @@ func (g *GCPAuth) Setup(client *api.Client, mountName string, topLevelConfig *To
 // Func Flags accepts a flag set to assign additional flags defined in the function
 func (g *GCPAuth) Flags(fs *flag.FlagSet) {}
 
-func getSignedJwt(config *GCPAuthTestConfig) (string, error) {
+func getSignedJwt(config *GCPAuthTestConfig, rng *rand.Rand) (string, error) {
 	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, cleanhttp.DefaultClient())
 
 	credentials, tokenSource, err := gcputil.FindCredentials(config.GCPAuthConfig.Credentials, ctx, iamcredentials.CloudPlatformScope)
@@ -232,9 +234,8 @@ func getSignedJwt(config *GCPAuthTestConfig) (string, error) {
 
 	var serviceAccount string
 	// Select one of the configured service accounts if more than 1
-	rand.Seed(time.Now().Unix())
 	if len(config.GCPTestRoleConfig.BoundServiceAccounts) > 0 {
-		n := rand.Int() % len(config.GCPTestRoleConfig.BoundServiceAccounts)
+		n := rng.Intn(len(config.GCPTestRoleConfig.BoundServiceAccounts))
 		serviceAccount = config.GCPTestRoleConfig.BoundServiceAccounts[n]
 	}
 
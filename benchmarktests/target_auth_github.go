@@ -77,7 +77,7 @@ func (g *GitHubAuth) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -130,8 +130,10 @@ func (g *GitHubAuth) Setup(client *api.Client, mountName string, topLevelConfig
 
 	// Create GitHub Auth mount
 	g.logger.Trace(mountLogMessage("auth", "github", authPath))
-	err = client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
-		Type: "github",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
+			Type: "github",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error enabling github: %v", err)
@@ -121,7 +121,7 @@ func (j *JWTAuth) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -168,8 +168,10 @@ func (j *JWTAuth) Setup(client *api.Client, mountName string, topLevelConfig *To
 
 	// Create JWT Auth mount
 	j.logger.Trace(mountLogMessage("auth", "jwt", authPath))
-	err = client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
-		Type: "jwt",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
+			Type: "jwt",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error enabling jwt: %v", err)
@@ -86,7 +86,7 @@ func (k *KubeAuth) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -140,8 +140,10 @@ func (k *KubeAuth) Setup(client *api.Client, mountName string, topLevelConfig *T
 	}
 
 	k.logger.Trace(mountLogMessage("auth", "kubernetes", authPath))
-	err = client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
-		Type: "kubernetes",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
+			Type: "kubernetes",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error enabling kubernetes: %v", err)
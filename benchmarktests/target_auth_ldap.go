@@ -104,7 +104,7 @@ func (l *LDAPAuth) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -165,8 +165,10 @@ func (l *LDAPAuth) Setup(client *api.Client, mountName string, topLevelConfig *T
 
 	// Create LDAP Auth mount
 	l.logger.Trace(mountLogMessage("auth", "ldap", authPath))
-	err = client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
-		Type: "ldap",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
+			Type: "ldap",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error enabling ldap: %v", err)
@@ -0,0 +1,383 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"context"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	"github.com/sethvargo/go-password/password"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	MFAValidateTestType   = "mfa_validate"
+	MFAValidateTestMethod = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[MFAValidateTestType] = func() BenchmarkBuilder { return &MFAValidateTest{} }
+}
+
+// MFAValidateTest validates logins to a userpass mount which requires TOTP
+// login MFA. Logging in is the first step of the flow and is done in the
+// background ahead of the attack's requests, so that only the second step,
+// validating the login with sys/mfa/validate, is measured.
+type MFAValidateTest struct {
+	pathPrefix string
+	header     http.Header
+	config     *MFAValidateTestConfig
+	logger     hclog.Logger
+
+	authPath string
+	password string
+	methodID string
+	users    []mfaUser
+
+	// pending receives logins waiting to be validated from the background
+	// goroutine logging in, which runs while the test is attacked
+	logins  background
+	pending chan mfaLogin
+}
+
+var _ AttackFinisher = (*MFAValidateTest)(nil)
+
+type MFAValidateTestConfig struct {
+	NumUsers  int    `hcl:"num_users,optional"`
+	Period    int    `hcl:"period,optional"`
+	Digits    int    `hcl:"digits,optional"`
+	Algorithm string `hcl:"algorithm,optional"`
+	Skew      int    `hcl:"skew,optional"`
+}
+
+// mfaUser is a userpass user along with the entity it logs in as and the
+// TOTP secret generated for the entity
+type mfaUser struct {
+	name     string
+	entityID string
+	secret   []byte
+}
+
+// mfaLogin is a login waiting for MFA validation. The request ID is empty if
+// logging in failed.
+type mfaLogin struct {
+	user      int
+	requestID string
+}
+
+func (m *MFAValidateTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *MFAValidateTestConfig `hcl:"config,block"`
+	}{
+		Config: &MFAValidateTestConfig{
+			NumUsers:  100,
+			Period:    30,
+			Digits:    6,
+			Algorithm: "SHA1",
+			Skew:      1,
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	m.config = testConfig.Config
+
+	switch {
+	case m.config.NumUsers < 1:
+		return fmt.Errorf("num_users must be at least 1")
+	case m.config.Period < 1:
+		return fmt.Errorf("period must be at least 1")
+	case m.config.Digits != 6 && m.config.Digits != 8:
+		return fmt.Errorf("digits must be either 6 or 8")
+	case m.config.Skew != 0 && m.config.Skew != 1:
+		return fmt.Errorf("skew must be either 0 or 1")
+	}
+	if totpHash(m.config.Algorithm) == nil {
+		return fmt.Errorf("algorithm must be one of SHA1, SHA256 or SHA512")
+	}
+	return nil
+}
+
+func (m *MFAValidateTest) Target(client *api.Client) vegeta.Target {
+	// Logins are stopped once an attack finishes, in which case another
+	// attack which is still running starts them again
+	var login mfaLogin
+	for waiting := true; waiting; {
+		ctx := m.logins.running(func(ctx context.Context) { m.login(ctx, client) })
+		select {
+		case login = <-m.pending:
+			waiting = false
+		case <-ctx.Done():
+		}
+	}
+	user := m.users[login.user]
+	step := time.Now().Unix() / int64(m.config.Period)
+	code := totpCode(m.config.Algorithm, user.secret, step, m.config.Digits)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"mfa_request_id": login.requestID,
+		"mfa_payload": map[string][]string{
+			m.methodID: {fmt.Sprintf("%0*d", m.config.Digits, code)},
+		},
+	})
+	if err != nil {
+		m.logger.Error("error marshaling mfa validate data", "error", err)
+	}
+
+	return vegeta.Target{
+		Method: MFAValidateTestMethod,
+		URL:    client.Address() + m.pathPrefix,
+		Body:   body,
+		Header: m.header,
+	}
+}
+
+// login logs in as each user in turn until the context is cancelled, queuing
+// the logins for validation
+func (m *MFAValidateTest) login(ctx context.Context, client *api.Client) {
+	for i := 0; ; i = (i + 1) % len(m.users) {
+		login := mfaLogin{user: i}
+		secret, err := client.Logical().WriteWithContext(ctx, filepath.Join("auth", m.authPath, "login", m.users[i].name), map[string]interface{}{
+			"password": m.password,
+		})
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			m.logger.Warn("error logging in", "user", m.users[i].name, "error", err)
+		case secret == nil || secret.Auth == nil || secret.Auth.MFARequirement == nil:
+			m.logger.Warn("login did not require mfa", "user", m.users[i].name)
+		default:
+			login.requestID = secret.Auth.MFARequirement.MFARequestID
+		}
+
+		select {
+		case m.pending <- login:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// AttackFinished stops logging in once the attack has finished
+func (m *MFAValidateTest) AttackFinished() {
+	m.logins.stop()
+}
+
+func (m *MFAValidateTest) Cleanup(client *api.Client) error {
+	m.logins.stop()
+
+	m.logger.Trace("cleaning up mfa method " + m.methodID)
+	var errs []error
+	paths := []string{
+		"identity/mfa/login-enforcement/" + m.authPath,
+		"identity/mfa/method/totp/" + m.methodID,
+	}
+	for _, user := range m.users {
+		paths = append(paths, "identity/entity/id/"+user.entityID)
+	}
+	for _, path := range paths {
+		if _, err := client.Logical().Delete(path); err != nil {
+			errs = append(errs, fmt.Errorf("error deleting %v: %v", path, err))
+		}
+	}
+
+	// Removing the mount revokes the tokens created by validated logins
+	m.logger.Trace(cleanupLogMessage("/v1/auth/" + m.authPath))
+	if err := client.Sys().DisableAuth(m.authPath); err != nil {
+		errs = append(errs, fmt.Errorf("error cleaning up mount: %v", err))
+	}
+	return errors.Join(errs...)
+}
+
+func (m *MFAValidateTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     MFAValidateTestMethod,
+		pathPrefix: m.pathPrefix,
+	}
+}
+
+func (m *MFAValidateTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	authPath := mountName
+	m.logger = targetLogger.Named(MFAValidateTestType)
+
+	if topLevelConfig.RandomMounts {
+		authPath, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	m.logger.Trace(mountLogMessage("auth", "userpass", authPath))
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
+			Type: "userpass",
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error enabling userpass auth: %v", err)
+	}
+
+	setupLogger := m.logger.Named(authPath)
+
+	auths, err := client.Sys().ListAuth()
+	if err != nil {
+		return nil, fmt.Errorf("error listing auth methods: %v", err)
+	}
+	mount, ok := auths[authPath+"/"]
+	if !ok {
+		return nil, fmt.Errorf("userpass auth not found at %q", authPath)
+	}
+
+	setupLogger.Trace("creating totp mfa method")
+	var methodID string
+	err = retrySetup(topLevelConfig, func() error {
+		resp, err := client.Logical().Write("identity/mfa/method/totp", map[string]interface{}{
+			"issuer":    "openbao-benchmark",
+			"period":    m.config.Period,
+			"digits":    m.config.Digits,
+			"algorithm": m.config.Algorithm,
+			"skew":      m.config.Skew,
+		})
+		if err != nil {
+			return err
+		}
+		if resp == nil || resp.Data["method_id"] == nil {
+			return fmt.Errorf("no method_id returned")
+		}
+		methodID = resp.Data["method_id"].(string)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating totp mfa method: %v", err)
+	}
+
+	setupLogger.Trace("enforcing mfa on userpass auth")
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write("identity/mfa/login-enforcement/"+authPath, map[string]interface{}{
+			"mfa_method_ids":        []string{methodID},
+			"auth_method_accessors": []string{mount.Accessor},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating mfa login enforcement: %v", err)
+	}
+
+	// Every user has its own entity, as MFA secrets are generated per
+	// entity and each code can only be used once
+	setupLogger.Trace("creating mfa users", "count", m.config.NumUsers)
+	pass := password.MustGenerate(64, 10, 0, false, true)
+	users := make([]mfaUser, m.config.NumUsers)
+	for i := range users {
+		users[i].name = "user-" + strconv.Itoa(i)
+		if err := m.createUser(client, topLevelConfig, authPath, mount.Accessor, methodID, pass, &users[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return &MFAValidateTest{
+		pathPrefix: "/v1/sys/mfa/validate",
+		header:     generateHeader(client),
+		config:     m.config,
+		logger:     m.logger,
+		authPath:   authPath,
+		password:   pass,
+		methodID:   methodID,
+		users:      users,
+		pending:    make(chan mfaLogin, len(users)),
+	}, nil
+}
+
+// createUser creates the userpass user, its entity and the entity's TOTP
+// secret
+func (m *MFAValidateTest) createUser(client *api.Client, topLevelConfig *TopLevelTargetConfig, authPath, accessor, methodID, pass string, user *mfaUser) error {
+	err := retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write(filepath.Join("auth", authPath, "users", user.name), map[string]interface{}{
+			"password": pass,
+		})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error creating userpass user %q: %v", user.name, err)
+	}
+
+	// The alias is created along with the entity, so that the user logs in
+	// as the entity
+	err = retrySetup(topLevelConfig, func() error {
+		resp, err := client.Logical().Write("identity/entity/name/"+authPath+"-"+user.name, nil)
+		if err != nil {
+			return err
+		}
+		if resp != nil && resp.Data["id"] != nil {
+			user.entityID = resp.Data["id"].(string)
+		}
+		if user.entityID == "" {
+			return fmt.Errorf("no entity id returned")
+		}
+		_, err = client.Logical().Write("identity/entity-alias", map[string]interface{}{
+			"name":           user.name,
+			"canonical_id":   user.entityID,
+			"mount_accessor": accessor,
+		})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error creating entity for user %q: %v", user.name, err)
+	}
+
+	err = retrySetup(topLevelConfig, func() error {
+		resp, err := client.Logical().Write("identity/mfa/method/totp/admin-generate", map[string]interface{}{
+			"method_id": methodID,
+			"entity_id": user.entityID,
+		})
+		if err != nil {
+			return err
+		}
+		if resp == nil || resp.Data["url"] == nil {
+			return fmt.Errorf("no totp url returned")
+		}
+		user.secret, err = totpSecretFromURL(resp.Data["url"].(string))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error generating totp secret for user %q: %v", user.name, err)
+	}
+	return nil
+}
+
+func (m *MFAValidateTest) Flags(fs *flag.FlagSet) {}
+
+// totpSecretFromURL returns the secret of an otpauth:// key URL
+func totpSecretFromURL(keyURL string) ([]byte, error) {
+	u, err := url.Parse(keyURL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing totp url: %v", err)
+	}
+	secret := strings.ToUpper(u.Query().Get("secret"))
+	if secret == "" {
+		return nil, fmt.Errorf("no secret in totp url")
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.TrimRight(secret, "="))
+}
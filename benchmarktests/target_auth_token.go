@@ -0,0 +1,178 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	TokenCreateTestType   = "token_create"
+	TokenCreateTestMethod = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[TokenCreateTestType] = func() BenchmarkBuilder { return &TokenCreateTest{} }
+}
+
+// TokenCreateTest creates tokens as children of a parent token created during
+// setup, so that every token created by the test is revoked along with the
+// parent during cleanup
+type TokenCreateTest struct {
+	pathPrefix string
+	body       []byte
+	header     http.Header
+	config     *TokenCreateTestConfig
+	logger     hclog.Logger
+
+	// policy is the parent token's policy, and accessor its accessor
+	policy   string
+	accessor string
+}
+
+type TokenCreateTestConfig struct {
+	TokenType string   `hcl:"token_type,optional"`
+	Policies  []string `hcl:"policies,optional"`
+	TTL       string   `hcl:"ttl,optional"`
+}
+
+func (t *TokenCreateTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *TokenCreateTestConfig `hcl:"config,block"`
+	}{
+		Config: &TokenCreateTestConfig{
+			TokenType: "service",
+			Policies:  []string{"default"},
+			TTL:       "1h",
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	t.config = testConfig.Config
+
+	switch t.config.TokenType {
+	case "service", "batch":
+	default:
+		return fmt.Errorf("token_type must be one of service or batch")
+	}
+	if len(t.config.Policies) == 0 {
+		return fmt.Errorf("policies must not be empty")
+	}
+	if _, err := time.ParseDuration(t.config.TTL); err != nil {
+		return fmt.Errorf("error parsing ttl: %v", err)
+	}
+	return nil
+}
+
+func (t *TokenCreateTest) Target(client *api.Client) vegeta.Target {
+	return vegeta.Target{
+		Method: TokenCreateTestMethod,
+		URL:    client.Address() + t.pathPrefix,
+		Body:   t.body,
+		Header: t.header,
+	}
+}
+
+// Cleanup revokes the parent token, which revokes every token created by the
+// test
+func (t *TokenCreateTest) Cleanup(client *api.Client) error {
+	t.logger.Trace("revoking parent token")
+	var errs []error
+	if err := client.Auth().Token().RevokeAccessor(t.accessor); err != nil {
+		errs = append(errs, fmt.Errorf("error revoking parent token: %v", err))
+	}
+	if err := client.Sys().DeletePolicy(t.policy); err != nil {
+		errs = append(errs, fmt.Errorf("error deleting parent token policy: %v", err))
+	}
+	return errors.Join(errs...)
+}
+
+func (t *TokenCreateTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     TokenCreateTestMethod,
+		pathPrefix: t.pathPrefix,
+	}
+}
+
+func (t *TokenCreateTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	policyName := mountName
+	t.logger = targetLogger.Named(TokenCreateTestType)
+
+	if topLevelConfig.RandomMounts {
+		policyName, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	// Tokens can only be created with a subset of their parent's policies,
+	// so the parent has the configured policies along with one allowing it
+	// to create tokens
+	t.logger.Trace("writing parent token policy " + policyName)
+	policy := fmt.Sprintf("path %q {\n  capabilities = [\"update\"]\n}\n", "auth/token/create")
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().PutPolicy(policyName, policy)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error writing parent token policy: %v", err)
+	}
+
+	t.logger.Trace("creating parent token")
+	var secret *api.Secret
+	err = retrySetup(topLevelConfig, func() error {
+		var err error
+		secret, err = client.Auth().Token().Create(&api.TokenCreateRequest{
+			Policies:        append([]string{policyName}, t.config.Policies...),
+			NoDefaultPolicy: true,
+			DisplayName:     "benchmark-token-create",
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating parent token: %v", err)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"type":              t.config.TokenType,
+		"policies":          t.config.Policies,
+		"no_default_policy": true,
+		"ttl":               t.config.TTL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling token create data: %v", err)
+	}
+
+	header := generateHeader(client)
+	header.Set("X-Vault-Token", secret.Auth.ClientToken)
+
+	return &TokenCreateTest{
+		pathPrefix: "/v1/auth/token/create",
+		body:       body,
+		header:     header,
+		config:     t.config,
+		logger:     t.logger,
+		policy:     policyName,
+		accessor:   secret.Auth.Accessor,
+	}, nil
+}
+
+func (t *TokenCreateTest) Flags(fs *flag.FlagSet) {}
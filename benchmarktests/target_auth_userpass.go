@@ -66,7 +66,7 @@ func (u *UserpassAuth) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -113,8 +113,10 @@ func (u *UserpassAuth) Setup(client *api.Client, mountName string, topLevelConfi
 
 	// Create Userpass Auth Mount
 	u.logger.Trace(mountLogMessage("auth", "userpass", authPath))
-	err = client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
-		Type: "userpass",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
+			Type: "userpass",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error enabling userpass auth: %v", err)
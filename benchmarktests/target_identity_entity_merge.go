@@ -0,0 +1,244 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	IdentityEntityMergeTestType   = "identity_entity_merge"
+	IdentityEntityMergeTestMethod = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[IdentityEntityMergeTestType] = func() BenchmarkBuilder { return &IdentityEntityMergeTest{} }
+}
+
+// IdentityEntityMergeTest merges pairs of entities created during setup,
+// each with an alias. Aliases of the two entities of a pair are on different
+// auth mounts, as entities with aliases on the same mount can't be merged
+// without choosing which alias to keep.
+type IdentityEntityMergeTest struct {
+	pathPrefix string
+	header     http.Header
+	config     *IdentityEntityMergeTestConfig
+	logger     hclog.Logger
+
+	// name prefixes the entities and auth mounts created during setup
+	name string
+
+	// pairs is the pool of entities to merge. Merging deletes the entity
+	// merged from, so each request merges the next pair in the pool,
+	// tracked by next.
+	pairs       []entityPair
+	next        atomic.Int64
+	exhaustOnce sync.Once
+}
+
+type entityPair struct {
+	from string
+	to   string
+}
+
+type IdentityEntityMergeTestConfig struct {
+	NumPairs int `hcl:"num_pairs,optional"`
+}
+
+func (i *IdentityEntityMergeTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *IdentityEntityMergeTestConfig `hcl:"config,block"`
+	}{
+		Config: &IdentityEntityMergeTestConfig{
+			NumPairs: 1000,
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	i.config = testConfig.Config
+
+	if i.config.NumPairs < 1 {
+		return fmt.Errorf("num_pairs must be at least 1")
+	}
+	return nil
+}
+
+func (i *IdentityEntityMergeTest) Target(client *api.Client) vegeta.Target {
+	// Once every pair has been merged, requests merge entities which no
+	// longer exist
+	n := i.next.Add(1) - 1
+	if n >= int64(len(i.pairs)) {
+		i.exhaustOnce.Do(func() {
+			i.logger.Warn("all entity pairs have been merged, increase num_pairs to merge live entities for the whole test")
+		})
+	}
+	pair := i.pairs[n%int64(len(i.pairs))]
+
+	body, err := json.Marshal(map[string]interface{}{
+		"from_entity_ids": []string{pair.from},
+		"to_entity_id":    pair.to,
+	})
+	if err != nil {
+		i.logger.Error("error marshaling entity merge request", "error", err)
+	}
+
+	return vegeta.Target{
+		Method: IdentityEntityMergeTestMethod,
+		URL:    client.Address() + i.pathPrefix,
+		Body:   body,
+		Header: i.header,
+	}
+}
+
+func (i *IdentityEntityMergeTest) Cleanup(client *api.Client) error {
+	i.logger.Trace("cleaning up entities", "count", 2*len(i.pairs))
+	var errs []error
+
+	// Deleting an entity which was merged away succeeds
+	for _, pair := range i.pairs {
+		for _, id := range []string{pair.from, pair.to} {
+			if _, err := client.Logical().Delete("identity/entity/id/" + id); err != nil {
+				errs = append(errs, fmt.Errorf("error deleting entity %v: %v", id, err))
+			}
+		}
+	}
+
+	for _, side := range []string{"from", "to"} {
+		path := i.name + "-" + side
+		i.logger.Trace(cleanupLogMessage(path))
+		if err := client.Sys().DisableAuth(path); err != nil {
+			errs = append(errs, fmt.Errorf("error cleaning up %v: %v", path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (i *IdentityEntityMergeTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     IdentityEntityMergeTestMethod,
+		pathPrefix: i.pathPrefix,
+	}
+}
+
+func (i *IdentityEntityMergeTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	name := mountName
+	i.logger = targetLogger.Named(IdentityEntityMergeTestType)
+
+	if topLevelConfig.RandomMounts {
+		name, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	// The entities of each side of the pairs have their aliases on a mount
+	// of their own
+	accessors := make(map[string]string, 2)
+	for _, side := range []string{"from", "to"} {
+		path := name + "-" + side
+		i.logger.Trace(mountLogMessage("auth", "userpass", path))
+		err = retrySetup(topLevelConfig, func() error {
+			return client.Sys().EnableAuthWithOptions(path, &api.EnableAuthOptions{
+				Type: "userpass",
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error enabling userpass: %v", err)
+		}
+
+		auth, err := client.Logical().Read("sys/auth/" + path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading userpass mount: %v", err)
+		}
+		if auth == nil || auth.Data["accessor"] == nil {
+			return nil, fmt.Errorf("no accessor returned for %v", path)
+		}
+		accessors[side] = fmt.Sprint(auth.Data["accessor"])
+	}
+
+	setupLogger := i.logger.Named(name)
+	setupLogger.Trace("creating entity pairs", "count", i.config.NumPairs)
+	pairs := make([]entityPair, 0, i.config.NumPairs)
+	for n := 0; n < i.config.NumPairs; n++ {
+		var pair entityPair
+		pair.from, err = createAliasedEntity(client, name+"-from-"+strconv.Itoa(n), accessors["from"], topLevelConfig)
+		if err != nil {
+			return nil, err
+		}
+		pair.to, err = createAliasedEntity(client, name+"-to-"+strconv.Itoa(n), accessors["to"], topLevelConfig)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair)
+	}
+
+	return &IdentityEntityMergeTest{
+		pathPrefix: "/v1/identity/entity/merge",
+		header:     generateHeader(client),
+		config:     i.config,
+		logger:     i.logger,
+		name:       name,
+		pairs:      pairs,
+	}, nil
+}
+
+// createAliasedEntity creates an entity with an alias of the same name on the
+// auth mount with the given accessor, returning the entity's ID
+func createAliasedEntity(client *api.Client, name, accessor string, topLevelConfig *TopLevelTargetConfig) (string, error) {
+	var entity *api.Secret
+	err := retrySetup(topLevelConfig, func() error {
+		var err error
+		entity, err = client.Logical().Write("identity/entity", map[string]interface{}{
+			"name": name,
+		})
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating entity: %v", err)
+	}
+	if entity == nil || entity.Data["id"] == nil {
+		return "", fmt.Errorf("no ID returned for entity %v", name)
+	}
+	id := fmt.Sprint(entity.Data["id"])
+
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write("identity/entity-alias", map[string]interface{}{
+			"name":           name,
+			"canonical_id":   id,
+			"mount_accessor": accessor,
+		})
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating entity alias: %v", err)
+	}
+	return id, nil
+}
+
+// ConsumesSetup reports that the test merges each pair of entities once
+func (i *IdentityEntityMergeTest) ConsumesSetup() bool {
+	return true
+}
+
+func (i *IdentityEntityMergeTest) Flags(fs *flag.FlagSet) {}
@@ -0,0 +1,322 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	IdentityGroupPolicyTestType   = "identity_group_policy"
+	IdentityGroupPolicyTestMethod = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[IdentityGroupPolicyTestType] = func() BenchmarkBuilder { return &IdentityGroupPolicyTest{} }
+}
+
+// IdentityGroupPolicyTest measures how long a policy added to an identity
+// group takes to apply to the tokens of its members. Each request adds a
+// policy to a group which has an entity as its only member, and is verified
+// by polling the capabilities of a token of that entity until the policy's
+// capability appears. Every group is given a policy of its own by a single
+// request, so that concurrent requests don't replace each other's policies.
+type IdentityGroupPolicyTest struct {
+	pathPrefix string
+	header     http.Header
+	config     *IdentityGroupPolicyTestConfig
+	logger     hclog.Logger
+
+	// name prefixes the groups, policies and token role created during
+	// setup, along with the paths the policies grant access to
+	name     string
+	accessor string
+	entityID string
+
+	// groups are the IDs of the groups created during setup. Requests add
+	// a policy to the next group, tracked by next.
+	groups      []string
+	next        atomic.Int64
+	exhaustOnce sync.Once
+
+	// memberHeader authenticates the capability checks as the member
+	memberHeader http.Header
+	maxLag       time.Duration
+	pollInterval time.Duration
+}
+
+type IdentityGroupPolicyTestConfig struct {
+	NumGroups    int    `hcl:"num_groups,optional"`
+	MaxLag       string `hcl:"max_lag,optional"`
+	PollInterval string `hcl:"poll_interval,optional"`
+}
+
+func (i *IdentityGroupPolicyTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *IdentityGroupPolicyTestConfig `hcl:"config,block"`
+	}{
+		Config: &IdentityGroupPolicyTestConfig{
+			NumGroups:    1000,
+			MaxLag:       "10s",
+			PollInterval: "10ms",
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	i.config = testConfig.Config
+
+	if i.config.NumGroups < 1 {
+		return fmt.Errorf("num_groups must be at least 1")
+	}
+	if _, err := time.ParseDuration(i.config.MaxLag); err != nil {
+		return fmt.Errorf("error parsing max_lag: %v", err)
+	}
+	if _, err := time.ParseDuration(i.config.PollInterval); err != nil {
+		return fmt.Errorf("error parsing poll_interval: %v", err)
+	}
+	return nil
+}
+
+func (i *IdentityGroupPolicyTest) Target(client *api.Client) vegeta.Target {
+	// Once every group has been given its policy, requests add the same
+	// policy again, which is visible as soon as they complete
+	n := i.next.Add(1) - 1
+	if n >= int64(len(i.groups)) {
+		i.exhaustOnce.Do(func() {
+			i.logger.Warn("every group has been given its policy, increase num_groups to measure propagation for the whole test")
+		})
+	}
+	n %= int64(len(i.groups))
+
+	return vegeta.Target{
+		Method: IdentityGroupPolicyTestMethod,
+		URL:    client.Address() + i.pathPrefix + "/" + i.groups[n],
+		Body:   []byte(`{"policies":["` + i.policyName(int(n)) + `"]}`),
+		Header: i.header,
+	}
+}
+
+func (i *IdentityGroupPolicyTest) policyName(n int) string {
+	return i.name + "-" + strconv.Itoa(n)
+}
+
+// Verify polls the capabilities of the member's token on the path granted by
+// the policy the request added, until they include the policy's capability.
+// The request is counted as a mismatch if the first check didn't include it,
+// and as an error if it isn't included within max_lag.
+func (i *IdentityGroupPolicyTest) Verify(client *http.Client, result *vegeta.Result) (Verified, error) {
+	updated := result.Timestamp.Add(result.Latency)
+
+	j := strings.Index(result.URL, i.pathPrefix)
+	if j < 0 {
+		return Verified{}, fmt.Errorf("unexpected group url: %v", result.URL)
+	}
+	n := slices.Index(i.groups, strings.TrimPrefix(result.URL[j:], i.pathPrefix+"/"))
+	if n < 0 {
+		return Verified{}, fmt.Errorf("unexpected group url: %v", result.URL)
+	}
+	url := result.URL[:j] + "/v1/sys/capabilities-self"
+	path := i.policyName(n)
+
+	deadline := updated.Add(i.maxLag)
+	for checks := 1; ; checks++ {
+		capabilities, err := i.capabilities(client, url, path)
+		if err != nil {
+			return Verified{}, err
+		}
+		if slices.Contains(capabilities, "read") {
+			return Verified{Matched: checks == 1, Lag: time.Since(updated)}, nil
+		}
+		if time.Now().After(deadline) {
+			return Verified{}, fmt.Errorf("policy %v not applied to member token after %v", path, i.maxLag)
+		}
+		time.Sleep(i.pollInterval)
+	}
+}
+
+// capabilities returns the capabilities of the member's token on path
+func (i *IdentityGroupPolicyTest) capabilities(client *http.Client, url, path string) ([]string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"paths": []string{path},
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = i.memberHeader.Clone()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status checking capabilities: %v", resp.Status)
+	}
+
+	var capabilities map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&capabilities); err != nil {
+		return nil, fmt.Errorf("error decoding capabilities: %v", err)
+	}
+	raw, _ := capabilities[path].([]interface{})
+	out := make([]string, 0, len(raw))
+	for _, capability := range raw {
+		out = append(out, fmt.Sprint(capability))
+	}
+	return out, nil
+}
+
+func (i *IdentityGroupPolicyTest) Cleanup(client *api.Client) error {
+	i.logger.Trace("cleaning up identity groups", "prefix", i.name, "count", len(i.groups))
+	var errs []error
+	if err := client.Auth().Token().RevokeAccessor(i.accessor); err != nil {
+		errs = append(errs, fmt.Errorf("error revoking member token: %v", err))
+	}
+
+	paths := make([]string, 0, 2*len(i.groups)+2)
+	for n, id := range i.groups {
+		paths = append(paths, "identity/group/id/"+id, "sys/policies/acl/"+i.policyName(n))
+	}
+	paths = append(paths, "identity/entity/id/"+i.entityID, "auth/token/roles/"+i.name)
+	for _, path := range paths {
+		if _, err := client.Logical().Delete(path); err != nil {
+			errs = append(errs, fmt.Errorf("error deleting %v: %v", path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (i *IdentityGroupPolicyTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     IdentityGroupPolicyTestMethod,
+		pathPrefix: i.pathPrefix,
+	}
+}
+
+func (i *IdentityGroupPolicyTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	name := mountName
+	i.logger = targetLogger.Named(IdentityGroupPolicyTestType)
+
+	if topLevelConfig.RandomMounts {
+		name, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+	test := &IdentityGroupPolicyTest{
+		pathPrefix: "/v1/identity/group/id",
+		header:     generateHeader(client),
+		config:     i.config,
+		logger:     i.logger,
+		name:       name,
+	}
+
+	// Tokens created through a token role with an entity alias are
+	// assigned the alias' entity, which is created if it doesn't exist. The
+	// default policy allows the token to check its own capabilities.
+	i.logger.Trace("creating member token")
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write("auth/token/roles/"+name, map[string]interface{}{
+			"allowed_entity_aliases": []string{name},
+			"orphan":                 true,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating token role: %v", err)
+	}
+
+	var secret *api.Secret
+	err = retrySetup(topLevelConfig, func() error {
+		var err error
+		secret, err = client.Auth().Token().CreateWithRole(&api.TokenCreateRequest{
+			EntityAlias: name,
+		}, name)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating member token: %v", err)
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.EntityID == "" {
+		return nil, fmt.Errorf("no entity assigned to created token")
+	}
+	test.accessor = secret.Auth.Accessor
+	test.entityID = secret.Auth.EntityID
+
+	test.memberHeader = generateHeader(client)
+	test.memberHeader.Set("X-Vault-Token", secret.Auth.ClientToken)
+
+	// Each policy grants read on a path of its own name, which no other
+	// policy of the member grants
+	setupLogger := i.logger.Named(name)
+	setupLogger.Trace("creating groups and policies", "count", i.config.NumGroups)
+	test.groups = make([]string, 0, i.config.NumGroups)
+	for n := 0; n < i.config.NumGroups; n++ {
+		policyName := test.policyName(n)
+		policy := fmt.Sprintf("path %q {\n  capabilities = [\"read\"]\n}\n", policyName)
+		err = retrySetup(topLevelConfig, func() error {
+			return client.Sys().PutPolicy(policyName, policy)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error writing group policy: %v", err)
+		}
+
+		var group *api.Secret
+		err = retrySetup(topLevelConfig, func() error {
+			var err error
+			group, err = client.Logical().Write("identity/group", map[string]interface{}{
+				"name":              policyName,
+				"member_entity_ids": []string{test.entityID},
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error creating group: %v", err)
+		}
+		if group == nil || group.Data["id"] == nil {
+			return nil, fmt.Errorf("no ID returned for group %v", policyName)
+		}
+		test.groups = append(test.groups, fmt.Sprint(group.Data["id"]))
+	}
+
+	// Both were validated while parsing the config
+	test.maxLag, _ = time.ParseDuration(i.config.MaxLag)
+	test.pollInterval, _ = time.ParseDuration(i.config.PollInterval)
+	return test, nil
+}
+
+// ConsumesSetup reports that the test gives each group its policy once
+func (i *IdentityGroupPolicyTest) ConsumesSetup() bool {
+	return true
+}
+
+func (i *IdentityGroupPolicyTest) Flags(fs *flag.FlagSet) {}
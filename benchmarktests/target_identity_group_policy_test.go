@@ -0,0 +1,58 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+func TestIdentityGroupPolicyTest_Verify(t *testing.T) {
+	// The policy is applied on the third check
+	var checks int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/sys/capabilities-self" || r.Header.Get("X-Vault-Token") != "member" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		checks++
+		capability := "deny"
+		if checks >= 3 {
+			capability = "read"
+		}
+		fmt.Fprintf(w, `{"bench-1":[%q],"capabilities":[%q]}`, capability, capability)
+	}))
+	defer server.Close()
+
+	test := &IdentityGroupPolicyTest{
+		pathPrefix:   "/v1/identity/group/id",
+		name:         "bench",
+		groups:       []string{"group-a", "group-b"},
+		memberHeader: http.Header{"X-Vault-Token": []string{"member"}},
+		maxLag:       time.Second,
+		pollInterval: time.Millisecond,
+	}
+	verified, err := test.Verify(server.Client(), &vegeta.Result{
+		Code:      204,
+		URL:       server.URL + "/v1/identity/group/id/group-b",
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if verified.Matched || verified.Lag <= 0 || checks != 3 {
+		t.Fatalf("expected a lagging update after 3 checks, got %+v after %d checks", verified, checks)
+	}
+
+	test.maxLag = 0
+	checks = 0
+	if _, err := test.Verify(server.Client(), &vegeta.Result{Code: 204, URL: server.URL + "/v1/identity/group/id/group-b", Timestamp: time.Now()}); err == nil {
+		t.Fatal("expected an error once max_lag has passed")
+	}
+}
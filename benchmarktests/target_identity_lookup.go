@@ -0,0 +1,238 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	IdentityLookupTestType   = "identity_lookup"
+	IdentityLookupTestMethod = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[IdentityLookupTestType] = func() BenchmarkBuilder { return &IdentityLookupTest{} }
+}
+
+// IdentityLookupTest looks up entities or groups created during setup by
+// their ID, name or alias, as happens when aliases are resolved during login
+type IdentityLookupTest struct {
+	pathPrefix string
+	header     http.Header
+	config     *IdentityLookupTestConfig
+	rng        *rand.Rand
+	logger     hclog.Logger
+
+	// mountPath is the auth mount the aliases are on
+	mountPath string
+
+	// ids are the IDs of the entities or groups created during setup, and
+	// bodies the lookup request of each
+	ids    []string
+	bodies [][]byte
+}
+
+type IdentityLookupTestConfig struct {
+	NumEntities int    `hcl:"num_entities,optional"`
+	By          string `hcl:"by,optional"`
+	Lookup      string `hcl:"lookup,optional"`
+}
+
+func (i *IdentityLookupTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *IdentityLookupTestConfig `hcl:"config,block"`
+	}{
+		Config: &IdentityLookupTestConfig{
+			NumEntities: 1000,
+			By:          "alias",
+			Lookup:      "entity",
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	i.config = testConfig.Config
+
+	if i.config.NumEntities < 1 {
+		return fmt.Errorf("num_entities must be at least 1")
+	}
+	switch i.config.By {
+	case "id", "name", "alias":
+	default:
+		return fmt.Errorf("by must be one of id, name or alias")
+	}
+	switch i.config.Lookup {
+	case "entity", "group":
+	default:
+		return fmt.Errorf("lookup must be one of entity or group")
+	}
+	return nil
+}
+
+func (i *IdentityLookupTest) Target(client *api.Client) vegeta.Target {
+	return vegeta.Target{
+		Method: IdentityLookupTestMethod,
+		URL:    client.Address() + i.pathPrefix,
+		Body:   i.bodies[i.rng.Intn(len(i.bodies))],
+		Header: i.header,
+	}
+}
+
+func (i *IdentityLookupTest) Cleanup(client *api.Client) error {
+	i.logger.Trace("cleaning up "+i.config.Lookup+"s", "count", len(i.ids))
+	var errs []error
+	for _, id := range i.ids {
+		if _, err := client.Logical().Delete("identity/" + i.config.Lookup + "/id/" + id); err != nil {
+			errs = append(errs, fmt.Errorf("error deleting %v %v: %v", i.config.Lookup, id, err))
+		}
+	}
+
+	i.logger.Trace(cleanupLogMessage(i.mountPath))
+	if err := client.Sys().DisableAuth(i.mountPath); err != nil {
+		errs = append(errs, fmt.Errorf("error cleaning up %v: %v", i.mountPath, err))
+	}
+	return errors.Join(errs...)
+}
+
+func (i *IdentityLookupTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     IdentityLookupTestMethod,
+		pathPrefix: i.pathPrefix,
+	}
+}
+
+func (i *IdentityLookupTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	name := mountName
+	i.logger = targetLogger.Named(IdentityLookupTestType)
+
+	if topLevelConfig.RandomMounts {
+		name, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	i.logger.Trace(mountLogMessage("auth", "userpass", name))
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().EnableAuthWithOptions(name, &api.EnableAuthOptions{
+			Type: "userpass",
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error enabling userpass: %v", err)
+	}
+
+	auth, err := client.Logical().Read("sys/auth/" + name)
+	if err != nil {
+		return nil, fmt.Errorf("error reading userpass mount: %v", err)
+	}
+	if auth == nil || auth.Data["accessor"] == nil {
+		return nil, fmt.Errorf("no accessor returned for %v", name)
+	}
+	accessor := fmt.Sprint(auth.Data["accessor"])
+
+	setupLogger := i.logger.Named(name)
+	setupLogger.Trace("creating aliased "+i.config.Lookup+"s", "count", i.config.NumEntities)
+	ids := make([]string, 0, i.config.NumEntities)
+	bodies := make([][]byte, 0, i.config.NumEntities)
+	for n := 0; n < i.config.NumEntities; n++ {
+		// Every entity or group has an alias of the same name
+		itemName := name + "-" + strconv.Itoa(n)
+		var id string
+		if i.config.Lookup == "group" {
+			id, err = createAliasedGroup(client, itemName, accessor, topLevelConfig)
+		} else {
+			id, err = createAliasedEntity(client, itemName, accessor, topLevelConfig)
+		}
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+
+		var data map[string]interface{}
+		switch i.config.By {
+		case "id":
+			data = map[string]interface{}{"id": id}
+		case "name":
+			data = map[string]interface{}{"name": itemName}
+		case "alias":
+			data = map[string]interface{}{
+				"alias_name":           itemName,
+				"alias_mount_accessor": accessor,
+			}
+		}
+		body, err := json.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling lookup data: %v", err)
+		}
+		bodies = append(bodies, body)
+	}
+
+	return &IdentityLookupTest{
+		pathPrefix: "/v1/identity/lookup/" + i.config.Lookup,
+		header:     generateHeader(client),
+		config:     i.config,
+		rng:        topLevelConfig.Rand,
+		logger:     i.logger,
+		mountPath:  name,
+		ids:        ids,
+		bodies:     bodies,
+	}, nil
+}
+
+// createAliasedGroup creates an external group with an alias of the same name
+// on the auth mount with the given accessor, returning the group's ID. Only
+// external groups can have aliases.
+func createAliasedGroup(client *api.Client, name, accessor string, topLevelConfig *TopLevelTargetConfig) (string, error) {
+	var group *api.Secret
+	err := retrySetup(topLevelConfig, func() error {
+		var err error
+		group, err = client.Logical().Write("identity/group", map[string]interface{}{
+			"name": name,
+			"type": "external",
+		})
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating group: %v", err)
+	}
+	if group == nil || group.Data["id"] == nil {
+		return "", fmt.Errorf("no ID returned for group %v", name)
+	}
+	id := fmt.Sprint(group.Data["id"])
+
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write("identity/group-alias", map[string]interface{}{
+			"name":           name,
+			"canonical_id":   id,
+			"mount_accessor": accessor,
+		})
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating group alias: %v", err)
+	}
+	return id, nil
+}
+
+func (i *IdentityLookupTest) Flags(fs *flag.FlagSet) {}
@@ -0,0 +1,208 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	IdentityOIDCTokenTestType   = "identity_oidc_token"
+	IdentityOIDCTokenTestMethod = "GET"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[IdentityOIDCTokenTestType] = func() BenchmarkBuilder { return &IdentityOIDCTokenTest{} }
+}
+
+// IdentityOIDCTokenTest requests signed identity tokens for an entity. Tokens
+// can only be generated for requests made with a token which has an entity,
+// so Setup creates one through a token role rather than using its own token.
+type IdentityOIDCTokenTest struct {
+	pathPrefix string
+	header     http.Header
+	config     *IdentityOIDCTokenTestConfig
+	logger     hclog.Logger
+
+	// name is shared by the key, role, policy and token role created
+	// during setup
+	name     string
+	accessor string
+	entityID string
+}
+
+type IdentityOIDCTokenTestConfig struct {
+	KeyAlgorithm string `hcl:"key_algorithm,optional"`
+	TTL          string `hcl:"ttl,optional"`
+}
+
+func (i *IdentityOIDCTokenTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *IdentityOIDCTokenTestConfig `hcl:"config,block"`
+	}{
+		Config: &IdentityOIDCTokenTestConfig{
+			KeyAlgorithm: "RS256",
+			TTL:          "24h",
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	i.config = testConfig.Config
+
+	switch i.config.KeyAlgorithm {
+	case "RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA":
+	default:
+		return fmt.Errorf("key_algorithm must be one of RS256, RS384, RS512, ES256, ES384, ES512 or EdDSA")
+	}
+	if _, err := time.ParseDuration(i.config.TTL); err != nil {
+		return fmt.Errorf("error parsing ttl: %v", err)
+	}
+	return nil
+}
+
+func (i *IdentityOIDCTokenTest) Target(client *api.Client) vegeta.Target {
+	return vegeta.Target{
+		Method: IdentityOIDCTokenTestMethod,
+		URL:    client.Address() + i.pathPrefix,
+		Header: i.header,
+	}
+}
+
+func (i *IdentityOIDCTokenTest) Cleanup(client *api.Client) error {
+	i.logger.Trace("cleaning up identity token role " + i.name)
+	var errs []error
+	if err := client.Auth().Token().RevokeAccessor(i.accessor); err != nil {
+		errs = append(errs, fmt.Errorf("error revoking entity token: %v", err))
+	}
+	paths := []string{
+		"identity/oidc/role/" + i.name,
+		"identity/oidc/key/" + i.name,
+		"identity/entity/id/" + i.entityID,
+		"auth/token/roles/" + i.name,
+		"sys/policies/acl/" + i.name,
+	}
+	for _, path := range paths {
+		if _, err := client.Logical().Delete(path); err != nil {
+			errs = append(errs, fmt.Errorf("error deleting %v: %v", path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (i *IdentityOIDCTokenTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     IdentityOIDCTokenTestMethod,
+		pathPrefix: i.pathPrefix,
+	}
+}
+
+func (i *IdentityOIDCTokenTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	name := mountName
+	i.logger = targetLogger.Named(IdentityOIDCTokenTestType)
+
+	if topLevelConfig.RandomMounts {
+		name, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	// A role's ttl can't be longer than its key's verification_ttl
+	i.logger.Trace("creating identity token key " + name)
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write("identity/oidc/key/"+name, map[string]interface{}{
+			"algorithm":          i.config.KeyAlgorithm,
+			"allowed_client_ids": "*",
+			"verification_ttl":   i.config.TTL,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating identity token key: %v", err)
+	}
+
+	i.logger.Trace("creating identity token role " + name)
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write("identity/oidc/role/"+name, map[string]interface{}{
+			"key": name,
+			"ttl": i.config.TTL,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating identity token role: %v", err)
+	}
+
+	policy := fmt.Sprintf("path %q {\n  capabilities = [\"read\"]\n}\n", "identity/oidc/token/"+name)
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().PutPolicy(name, policy)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error writing identity token policy: %v", err)
+	}
+
+	// Tokens created through a token role with an entity alias are
+	// assigned the alias' entity, which is created if it doesn't exist
+	i.logger.Trace("creating entity token")
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write("auth/token/roles/"+name, map[string]interface{}{
+			"allowed_policies":       []string{name},
+			"allowed_entity_aliases": []string{name},
+			"orphan":                 true,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating token role: %v", err)
+	}
+
+	var secret *api.Secret
+	err = retrySetup(topLevelConfig, func() error {
+		var err error
+		secret, err = client.Auth().Token().CreateWithRole(&api.TokenCreateRequest{
+			Policies:        []string{name},
+			NoDefaultPolicy: true,
+			EntityAlias:     name,
+		}, name)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating entity token: %v", err)
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.EntityID == "" {
+		return nil, fmt.Errorf("no entity assigned to created token")
+	}
+
+	header := generateHeader(client)
+	header.Set("X-Vault-Token", secret.Auth.ClientToken)
+
+	return &IdentityOIDCTokenTest{
+		pathPrefix: "/v1/identity/oidc/token/" + name,
+		header:     header,
+		config:     i.config,
+		logger:     i.logger,
+		name:       name,
+		accessor:   secret.Auth.Accessor,
+		entityID:   secret.Auth.EntityID,
+	}, nil
+}
+
+func (i *IdentityOIDCTokenTest) Flags(fs *flag.FlagSet) {}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 
@@ -21,27 +22,31 @@ import (
 
 // Constants for test
 const (
-	AWSSecretTestType   = "aws_secret"
-	AWSSecretTestMethod = "GET"
-	AWSSecretAccessKey  = VaultBenchmarkEnvVarPrefix + "AWS_ACCESS_KEY"
-	AWSSecretSecretKey  = VaultBenchmarkEnvVarPrefix + "AWS_SECRET_KEY"
+	AWSSecretTestType        = "aws_secret"
+	AWSDynamicSecretTestType = "aws_dynamic_secret"
+	AWSSecretTestMethod      = "GET"
+	AWSSecretAccessKey       = VaultBenchmarkEnvVarPrefix + "AWS_ACCESS_KEY"
+	AWSSecretSecretKey       = VaultBenchmarkEnvVarPrefix + "AWS_SECRET_KEY"
+	AWSSecretRegion          = VaultBenchmarkEnvVarPrefix + "AWS_REGION"
 )
 
 func init() {
 	// "Register" this test to the main test registry
 	TestList[AWSSecretTestType] = func() BenchmarkBuilder { return &AWSTest{} }
+	TestList[AWSDynamicSecretTestType] = func() BenchmarkBuilder { return &AWSTest{} }
 }
 
 type AWSTest struct {
 	pathPrefix string
 	header     http.Header
-	roleName   string
+	credsPath  string
 	config     *AWSSecretTestConfig
 	logger     hclog.Logger
 }
 
 // Main Config Struct
 type AWSSecretTestConfig struct {
+	TTL                 string               `hcl:"ttl,optional"`
 	AWSConnectionConfig *AWSConnectionConfig `hcl:"connection,block"`
 	AWSRoleConfig       *AWSRoleConfig       `hcl:"role,block"`
 }
@@ -78,6 +83,7 @@ func (a *AWSTest) ParseConfig(body hcl.Body) error {
 			AWSConnectionConfig: &AWSConnectionConfig{
 				AccessKey: os.Getenv(AWSSecretAccessKey),
 				SecretKey: os.Getenv(AWSSecretSecretKey),
+				Region:    os.Getenv(AWSSecretRegion),
 			},
 			AWSRoleConfig: &AWSRoleConfig{
 				Name:           "benchmark-role",
@@ -86,7 +92,7 @@ func (a *AWSTest) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -100,13 +106,18 @@ func (a *AWSTest) ParseConfig(body hcl.Body) error {
 		return fmt.Errorf("no aws secret_key provided but required")
 	}
 
+	// Only STS credentials can be requested with a TTL
+	if a.config.TTL != "" && a.config.AWSRoleConfig.CredentialType == "iam_user" {
+		return fmt.Errorf("ttl is only supported when credential_type is assumed_role or federation_token")
+	}
+
 	return nil
 }
 
 func (a *AWSTest) Target(client *api.Client) vegeta.Target {
 	return vegeta.Target{
 		Method: AWSSecretTestMethod,
-		URL:    client.Address() + a.pathPrefix + "/creds/" + a.roleName,
+		URL:    client.Address() + a.pathPrefix + a.credsPath,
 		Header: a.header,
 	}
 }
@@ -140,8 +151,10 @@ func (a *AWSTest) Setup(client *api.Client, mountName string, topLevelConfig *To
 	}
 
 	a.logger.Trace(mountLogMessage("secrets", "aws", secretPath))
-	err = client.Sys().Mount(secretPath, &api.MountInput{
-		Type: "aws",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "aws",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting aws secrets engine: %v", err)
@@ -177,10 +190,20 @@ func (a *AWSTest) Setup(client *api.Client, mountName string, topLevelConfig *To
 		return nil, fmt.Errorf("error writing aws role: %v", err)
 	}
 
+	// IAM users are created through creds, while STS credentials are
+	// requested through sts
+	credsPath := "/creds/" + a.config.AWSRoleConfig.Name
+	if a.config.AWSRoleConfig.CredentialType != "iam_user" {
+		credsPath = "/sts/" + a.config.AWSRoleConfig.Name
+	}
+	if a.config.TTL != "" {
+		credsPath += "?" + url.Values{"ttl": []string{a.config.TTL}}.Encode()
+	}
+
 	return &AWSTest{
 		pathPrefix: "/v1/" + secretPath,
 		header:     generateHeader(client),
-		roleName:   a.config.AWSRoleConfig.Name,
+		credsPath:  credsPath,
 		logger:     a.logger,
 	}, nil
 }
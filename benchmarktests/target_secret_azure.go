@@ -85,7 +85,7 @@ func (a *AzureTest) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -142,8 +142,10 @@ func (a *AzureTest) Setup(client *api.Client, mountName string, topLevelConfig *
 	a.logger.Trace(mountLogMessage("secrets", "azure", secretPath))
 	setupLogger := a.logger.Named(secretPath)
 
-	err = client.Sys().Mount(secretPath, &api.MountInput{
-		Type: "azure",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "azure",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting azure: %v", err)
@@ -112,7 +112,7 @@ func (c *CassandraSecret) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -167,8 +167,10 @@ func (c *CassandraSecret) Setup(client *api.Client, mountName string, topLevelCo
 
 	// Create Database Secret Mount
 	c.logger.Trace(mountLogMessage("secrets", "database", secretPath))
-	err = client.Sys().Mount(secretPath, &api.MountInput{
-		Type: "database",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "database",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting database secrets engine: %v", err)
@@ -186,7 +188,10 @@ func (c *CassandraSecret) Setup(client *api.Client, mountName string, topLevelCo
 	// Set up db
 	setupLogger.Trace(writingLogMessage("cassandra db config"), "name", c.config.CassandraDBConfig.Name)
 	dbPath := filepath.Join(secretPath, "config", c.config.CassandraDBConfig.Name)
-	_, err = client.Logical().Write(dbPath, dbData)
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write(dbPath, dbData)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error writing cassandra db config: %v", err)
 	}
@@ -22,14 +22,17 @@ import (
 
 // Constants for test
 const (
-	ConsulSecretTestType   = "consul_secret"
-	ConsulSecretTestMethod = "GET"
-	ConsulTokenEnvVar      = VaultBenchmarkEnvVarPrefix + "CONSUL_TOKEN"
+	ConsulSecretTestType        = "consul_secret"
+	ConsulDynamicSecretTestType = "consul_dynamic_secret"
+	ConsulSecretTestMethod      = "GET"
+	ConsulAddressEnvVar         = VaultBenchmarkEnvVarPrefix + "CONSUL_ADDRESS"
+	ConsulTokenEnvVar           = VaultBenchmarkEnvVarPrefix + "CONSUL_TOKEN"
 )
 
 func init() {
 	// "Register" this test to the main test registry
 	TestList[ConsulSecretTestType] = func() BenchmarkBuilder { return &ConsulTest{} }
+	TestList[ConsulDynamicSecretTestType] = func() BenchmarkBuilder { return &ConsulTest{} }
 }
 
 type ConsulTest struct {
@@ -47,7 +50,7 @@ type ConsulSecretTestConfig struct {
 }
 
 type ConsulConfig struct {
-	Address    string `hcl:"address"`
+	Address    string `hcl:"address,optional"`
 	Scheme     string `hcl:"scheme,optional"`
 	Token      string `hcl:"token,optional"`
 	CaCert     string `hcl:"ca_cert,optional"`
@@ -79,7 +82,8 @@ func (c *ConsulTest) ParseConfig(body hcl.Body) error {
 		Config: &ConsulSecretTestConfig{
 			Version: "1.14.0",
 			ConsulConfig: &ConsulConfig{
-				Token: os.Getenv(ConsulTokenEnvVar),
+				Address: os.Getenv(ConsulAddressEnvVar),
+				Token:   os.Getenv(ConsulTokenEnvVar),
 			},
 			ConsulRoleConfig: &ConsulRoleConfig{
 				Name: "benchmark-role",
@@ -87,13 +91,16 @@ func (c *ConsulTest) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
 	c.config = testConfig.Config
 
-	// Ensure that the token has been set by either the environment variable or the config
+	// Ensure that the address and token have been set by either the environment variables or the config
+	if c.config.ConsulConfig.Address == "" {
+		return fmt.Errorf("consul address must be set")
+	}
 	if c.config.ConsulConfig.Token == "" {
 		return fmt.Errorf("consul token must be set")
 	}
@@ -137,8 +144,10 @@ func (c *ConsulTest) Setup(client *api.Client, mountName string, topLevelConfig
 	}
 
 	c.logger.Trace(mountLogMessage("secrets", "consul", secretPath))
-	err = client.Sys().Mount(secretPath, &api.MountInput{
-		Type: "consul",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "consul",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting consul: %v", err)
@@ -95,7 +95,7 @@ func (c *CouchbaseSecretTest) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -150,8 +150,10 @@ func (c *CouchbaseSecretTest) Setup(client *api.Client, mountName string, topLev
 
 	// Create Database Secret Mount
 	c.logger.Trace(mountLogMessage("secrets", "database", secretPath))
-	err = client.Sys().Mount(secretPath, &api.MountInput{
-		Type: "database",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "database",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting database secrets engine: %v", err)
@@ -169,7 +171,10 @@ func (c *CouchbaseSecretTest) Setup(client *api.Client, mountName string, topLev
 	// Write Config
 	setupLogger.Trace(writingLogMessage("couchbase db config"), "name", c.config.DBConfig.Name)
 	dbPath := filepath.Join(secretPath, "config", c.config.DBConfig.Name)
-	_, err = client.Logical().Write(dbPath, dbData)
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write(dbPath, dbData)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error writing couchbase db config: %v", err)
 	}
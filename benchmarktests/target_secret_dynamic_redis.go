@@ -76,7 +76,7 @@ func (r *RedisDynamicSecret) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -131,8 +131,10 @@ func (r *RedisDynamicSecret) Setup(client *api.Client, mountName string, topLeve
 
 	// Create Database Secret Mount
 	r.logger.Trace(mountLogMessage("secrets", "database", secretPath))
-	err = client.Sys().Mount(secretPath, &api.MountInput{
-		Type: "database",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "database",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting db secrets engine: %v", err)
@@ -150,7 +152,10 @@ func (r *RedisDynamicSecret) Setup(client *api.Client, mountName string, topLeve
 	// Set up db
 	setupLogger.Trace(writingLogMessage("redis db config"), "name", r.config.DBConfig.Name)
 	dbPath := filepath.Join(secretPath, "config", r.config.DBConfig.Name)
-	_, err = client.Logical().Write(dbPath, dbData)
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write(dbPath, dbData)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error writing redis db config: %v", err)
 	}
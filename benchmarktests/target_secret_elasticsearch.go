@@ -98,7 +98,7 @@ func (e *ElasticSearchTest) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -152,8 +152,10 @@ func (e *ElasticSearchTest) Setup(client *api.Client, mountName string, topLevel
 	}
 
 	e.logger.Trace(mountLogMessage("secrets", "database", secretPath))
-	err = client.Sys().Mount(secretPath, &api.MountInput{
-		Type: "database",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "database",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting database secrets engine: %v", err)
@@ -171,7 +173,10 @@ func (e *ElasticSearchTest) Setup(client *api.Client, mountName string, topLevel
 	// Write DB config
 	setupLogger.Trace(writingLogMessage("elasticsearch db config"), "name", e.config.ElasticSearchConfig.Name)
 	dbPath := filepath.Join(secretPath, "config", e.config.ElasticSearchConfig.Name)
-	_, err = client.Logical().Write(dbPath, elasticSearchConfigData)
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write(dbPath, elasticSearchConfigData)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error writing Elasticsearch db config: %v", err)
 	}
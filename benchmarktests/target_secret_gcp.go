@@ -71,7 +71,7 @@ func (g *GCPTest) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -140,8 +140,10 @@ func (g *GCPTest) Setup(client *api.Client, mountName string, topLevelConfig *To
 	g.logger.Trace(mountLogMessage("secrets", "gcp", secretPath))
 	setupLogger := g.logger.Named(secretPath)
 
-	err = client.Sys().Mount(secretPath, &api.MountInput{
-		Type: "gcp",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "gcp",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting gcp: %v", err)
@@ -61,7 +61,7 @@ func (g *GCPImpersonationTest) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -118,8 +118,10 @@ func (g *GCPImpersonationTest) Setup(client *api.Client, mountName string, topLe
 	g.logger.Trace(mountLogMessage("secrets", "gcp_impersonation", secretPath))
 	setupLogger := g.logger.Named(secretPath)
 
-	err = client.Sys().Mount(secretPath, &api.MountInput{
-		Type: "gcp",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "gcp",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting gcp: %v", err)
@@ -0,0 +1,265 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+// Constants for test
+const (
+	KMIPScopeCreateTestType        = "kmip_scope_create"
+	KMIPRoleCreateTestType         = "kmip_role_create"
+	KMIPCredentialGenerateTestType = "kmip_credential_generate"
+	KMIPTestMethod                 = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[KMIPScopeCreateTestType] = func() BenchmarkBuilder { return &KMIPTest{action: "scope_create"} }
+	TestList[KMIPRoleCreateTestType] = func() BenchmarkBuilder { return &KMIPTest{action: "role_create"} }
+	TestList[KMIPCredentialGenerateTestType] = func() BenchmarkBuilder { return &KMIPTest{action: "credential_generate"} }
+}
+
+type KMIPTest struct {
+	action     string
+	pathPrefix string
+	scopeName  string
+	roleName   string
+	body       []byte
+	header     http.Header
+	config     *KMIPTestConfig
+	logger     hclog.Logger
+}
+
+// Main Config Struct
+type KMIPTestConfig struct {
+	Scope      string          `hcl:"scope,optional"`
+	Format     string          `hcl:"format,optional"`
+	KMIPConfig *KMIPConfig     `hcl:"kmip,block"`
+	RoleConfig *KMIPRoleConfig `hcl:"role,block"`
+}
+
+type KMIPConfig struct {
+	ListenAddrs             []string `hcl:"listen_addrs,optional"`
+	ServerHostnames         []string `hcl:"server_hostnames,optional"`
+	ServerIPs               []string `hcl:"server_ips,optional"`
+	TLSCAKeyType            string   `hcl:"tls_ca_key_type,optional"`
+	TLSCAKeyBits            int      `hcl:"tls_ca_key_bits,optional"`
+	TLSMinVersion           string   `hcl:"tls_min_version,optional"`
+	DefaultTLSClientKeyType string   `hcl:"default_tls_client_key_type,optional"`
+	DefaultTLSClientKeyBits int      `hcl:"default_tls_client_key_bits,optional"`
+	DefaultTLSClientTTL     string   `hcl:"default_tls_client_ttl,optional"`
+}
+
+type KMIPRoleConfig struct {
+	Name             string `hcl:"name,optional"`
+	OperationAll     bool   `hcl:"operation_all,optional"`
+	TLSClientKeyType string `hcl:"tls_client_key_type,optional"`
+	TLSClientKeyBits int    `hcl:"tls_client_key_bits,optional"`
+	TLSClientTTL     string `hcl:"tls_client_ttl,optional"`
+}
+
+func (k *KMIPTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *KMIPTestConfig `hcl:"config,block"`
+	}{
+		Config: &KMIPTestConfig{
+			Scope:  "benchmark-scope",
+			Format: "pem",
+			KMIPConfig: &KMIPConfig{
+				ListenAddrs: []string{"127.0.0.1:5696"},
+			},
+			RoleConfig: &KMIPRoleConfig{
+				Name:         "benchmark-role",
+				OperationAll: true,
+			},
+		},
+	}
+
+	// Scope creation only needs the engine to be configured, so it has no
+	// scope or role and generates no credentials
+	if k.action == "scope_create" {
+		testConfig.Config.Scope = ""
+		testConfig.Config.Format = ""
+		testConfig.Config.RoleConfig = nil
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	k.config = testConfig.Config
+
+	if k.action == "scope_create" {
+		if k.config.Scope != "" || k.config.Format != "" || k.config.RoleConfig != nil {
+			return fmt.Errorf("scope, format and role are not used by %v", KMIPScopeCreateTestType)
+		}
+		return nil
+	}
+
+	switch k.config.Format {
+	case "pem", "pem_bundle", "der":
+	default:
+		return fmt.Errorf("format must be one of pem, pem_bundle or der")
+	}
+	return nil
+}
+
+func (k *KMIPTest) Target(client *api.Client) vegeta.Target {
+	var path string
+	switch k.action {
+	case "scope_create":
+		path = "/scope/" + k.uniqueName()
+	case "role_create":
+		path = "/scope/" + k.scopeName + "/role/" + k.uniqueName()
+	default:
+		path = "/scope/" + k.scopeName + "/role/" + k.roleName + "/credential/generate"
+	}
+
+	return vegeta.Target{
+		Method: KMIPTestMethod,
+		URL:    client.Address() + k.pathPrefix + path,
+		Body:   k.body,
+		Header: k.header,
+	}
+}
+
+// uniqueName returns a new name for each scope or role created during the
+// benchmark
+func (k *KMIPTest) uniqueName() string {
+	name, err := uuid.GenerateUUID()
+	if err != nil {
+		k.logger.Error("error generating name", "error", err)
+	}
+	return name
+}
+
+func (k *KMIPTest) Cleanup(client *api.Client) error {
+	k.logger.Trace(cleanupLogMessage(k.pathPrefix))
+	_, err := client.Logical().Delete(strings.Replace(k.pathPrefix, "/v1/", "/sys/mounts/", 1))
+	if err != nil {
+		return fmt.Errorf("error cleaning up mount: %v", err)
+	}
+	return nil
+}
+
+func (k *KMIPTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     KMIPTestMethod,
+		pathPrefix: k.pathPrefix,
+	}
+}
+
+func (k *KMIPTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	secretPath := mountName
+	k.logger = targetLogger.Named("kmip_" + k.action)
+
+	if topLevelConfig.RandomMounts {
+		secretPath, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	k.logger.Trace(mountLogMessage("secrets", "kmip", secretPath))
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "kmip",
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error mounting kmip secrets engine: %v", err)
+	}
+
+	setupLogger := k.logger.Named(secretPath)
+
+	// Decode KMIP Config
+	setupLogger.Trace(parsingConfigLogMessage("kmip"))
+	kmipConfigData, err := structToMap(k.config.KMIPConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing kmip config from struct: %v", err)
+	}
+
+	// Write KMIP config
+	setupLogger.Trace(writingLogMessage("kmip config"))
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write(secretPath+"/config", kmipConfigData)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error writing kmip config: %v", err)
+	}
+
+	test := &KMIPTest{
+		action:     k.action,
+		pathPrefix: "/v1/" + secretPath,
+		header:     generateHeader(client),
+		logger:     k.logger,
+	}
+	// Scope creation creates a new scope with each request, so it needs no
+	// scope or role
+	if k.action == "scope_create" {
+		return test, nil
+	}
+
+	// Decode Role Config
+	setupLogger.Trace(parsingConfigLogMessage("role"))
+	roleConfigData, err := structToMap(k.config.RoleConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing role config from struct: %v", err)
+	}
+	delete(roleConfigData, "name")
+
+	setupLogger.Trace(writingLogMessage("kmip scope"), "name", k.config.Scope)
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write(secretPath+"/scope/"+k.config.Scope, nil)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error writing kmip scope: %v", err)
+	}
+	if k.action == "credential_generate" {
+		setupLogger.Trace(writingLogMessage("kmip role"), "name", k.config.RoleConfig.Name)
+		err = retrySetup(topLevelConfig, func() error {
+			_, err := client.Logical().Write(secretPath+"/scope/"+k.config.Scope+"/role/"+k.config.RoleConfig.Name, roleConfigData)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error writing kmip role: %v", err)
+		}
+	}
+
+	// Each request body either configures a new role or requests a credential
+	var body []byte
+	switch k.action {
+	case "role_create":
+		body, err = json.Marshal(roleConfigData)
+	case "credential_generate":
+		body, err = json.Marshal(map[string]interface{}{"format": k.config.Format})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling kmip request body: %v", err)
+	}
+
+	test.scopeName = k.config.Scope
+	test.roleName = k.config.RoleConfig.Name
+	test.body = body
+	return test, nil
+}
+
+func (k *KMIPTest) Flags(fs *flag.FlagSet) {}
@@ -11,6 +11,8 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-uuid"
@@ -22,12 +24,20 @@ import (
 
 // Constants for test
 const (
-	KVV1ReadTestType    = "kvv1_read"
-	KVV1ListTestType    = "kvv1_list"
-	KVV1WriteTestType   = "kvv1_write"
-	KVV1ReadTestMethod  = "GET"
-	KVV1ListTestMethod  = "LIST"
-	KVV1WriteTestMethod = "POST"
+	KVV1ReadTestType     = "kvv1_read"
+	KVV1ListTestType     = "kvv1_list"
+	KVV1WriteTestType    = "kvv1_write"
+	KVV1DeleteTestType   = "kvv1_delete"
+	KVV1ReadTestMethod   = "GET"
+	KVV1ListTestMethod   = "LIST"
+	KVV1WriteTestMethod  = "POST"
+	KVV1DeleteTestMethod = "DELETE"
+
+	// KVV1DeleteModePool deletes each seeded secret once, in order, and
+	// KVV1DeleteModeRepeat deletes seeded secrets chosen by the access
+	// distribution whether or not they have already been deleted
+	KVV1DeleteModePool   = "pool"
+	KVV1DeleteModeRepeat = "repeat"
 )
 
 func init() {
@@ -40,6 +50,9 @@ func init() {
 	TestList[KVV1WriteTestType] = func() BenchmarkBuilder {
 		return &KVV1Test{action: "write"}
 	}
+	TestList[KVV1DeleteTestType] = func() BenchmarkBuilder {
+		return &KVV1Test{action: "delete"}
+	}
 }
 
 type KVV1Test struct {
@@ -47,14 +60,29 @@ type KVV1Test struct {
 	header     http.Header
 	config     *KVV1SecretTestConfig
 	action     string
-	numKVs     int
-	kvSize     int
+	keys       *keyDistribution
+	writeKeys  *writeKeys
+	kvSize     payloadSize
+	body       *bodyTemplate
+	rng        *rand.Rand
 	logger     hclog.Logger
+
+	// deleted counts the secrets deleted from the pool
+	deleted     atomic.Int64
+	exhaustOnce sync.Once
 }
 
 type KVV1SecretTestConfig struct {
-	KVSize int `hcl:"kvsize,optional"`
-	NumKVs int `hcl:"numkvs,optional"`
+	KVSize             int     `hcl:"kvsize,optional"`
+	KVSizeMin          int     `hcl:"kvsize_min,optional"`
+	KVSizeMax          int     `hcl:"kvsize_max,optional"`
+	KVSizeDistribution string  `hcl:"kvsize_distribution,optional"`
+	NumKVs             int     `hcl:"numkvs,optional"`
+	AccessDistribution string  `hcl:"access_distribution,optional"`
+	KeyMode            string  `hcl:"key_mode,optional"`
+	ZipfS              float64 `hcl:"zipf_s,optional"`
+	BodyTemplate       string  `hcl:"body_template,optional"`
+	DeleteMode         string  `hcl:"delete_mode,optional"`
 }
 
 func (k *KVV1Test) ParseConfig(body hcl.Body) error {
@@ -62,21 +90,43 @@ func (k *KVV1Test) ParseConfig(body hcl.Body) error {
 		Config *KVV1SecretTestConfig `hcl:"config,block"`
 	}{
 		Config: &KVV1SecretTestConfig{
-			KVSize: 1,
-			NumKVs: 1000,
+			KVSize:             1,
+			KVSizeDistribution: PayloadSizeUniform,
+			NumKVs:             1000,
+			AccessDistribution: AccessDistributionUniform,
+			KeyMode:            KeyModeRandomExisting,
+			ZipfS:              1.1,
+			DeleteMode:         KVV1DeleteModePool,
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
 	k.config = testConfig.Config
-	return nil
+
+	// The distribution itself is built during Setup
+	if _, err := newKeyDistribution(nil, k.config.NumKVs, k.config.AccessDistribution, k.config.ZipfS); err != nil {
+		return err
+	}
+	if _, err := newWriteKeys(nil, k.config.NumKVs, k.config.KeyMode); err != nil {
+		return err
+	}
+
+	switch k.config.DeleteMode {
+	case KVV1DeleteModePool, KVV1DeleteModeRepeat:
+	default:
+		return fmt.Errorf("delete_mode must be one of %v or %v", KVV1DeleteModePool, KVV1DeleteModeRepeat)
+	}
+
+	var err error
+	k.kvSize, err = newPayloadSize(k.config.KVSize, k.config.KVSizeMin, k.config.KVSizeMax, k.config.KVSizeDistribution)
+	return err
 }
 
 func (k *KVV1Test) read(client *api.Client) vegeta.Target {
-	secnum := int(1 + rand.Int31n(int32(k.numKVs)))
+	secnum := k.keys.next()
 	return vegeta.Target{
 		Method: KVV1ReadTestMethod,
 		URL:    client.Address() + k.pathPrefix + "/secret-" + strconv.Itoa(secnum),
@@ -93,20 +143,57 @@ func (k *KVV1Test) list(client *api.Client) vegeta.Target {
 }
 
 func (k *KVV1Test) write(client *api.Client) vegeta.Target {
-	secnum := int(1 + rand.Int31n(int32(k.numKVs)))
-	value := strings.Repeat("a", k.kvSize)
+	secnum := k.writeKeys.next()
 	return vegeta.Target{
 		Method: KVV1WriteTestMethod,
 		URL:    client.Address() + k.pathPrefix + "/secret-" + strconv.Itoa(secnum),
-		Body:   []byte(`{"data": {"foo": "` + value + `"}}`),
+		Body:   k.writeBody(secnum),
 		Header: k.header,
 	}
 }
 
+// delete deletes the next secret of the pool, or a secret chosen by the
+// access distribution when deletes repeat. KV v1 responds to deleting a
+// secret which no longer exists just as it does to deleting one which does.
+func (k *KVV1Test) delete(client *api.Client) vegeta.Target {
+	var secnum int
+	if k.config.DeleteMode == KVV1DeleteModeRepeat {
+		secnum = k.keys.next()
+	} else {
+		n := k.deleted.Add(1) - 1
+		if n >= int64(k.config.NumKVs) {
+			k.exhaustOnce.Do(func() {
+				k.logger.Warn("every seeded secret has been deleted, increase numkvs to delete existing secrets for the whole test")
+			})
+		}
+		secnum = int(1 + n%int64(k.config.NumKVs))
+	}
+	return vegeta.Target{
+		Method: KVV1DeleteTestMethod,
+		URL:    client.Address() + k.pathPrefix + "/secret-" + strconv.Itoa(secnum),
+		Header: k.header,
+	}
+}
+
+func (k *KVV1Test) writeBody(secnum int) []byte {
+	if k.body == nil {
+		value := strings.Repeat("a", k.kvSize.next(k.rng))
+		return []byte(`{"data": {"foo": "` + value + `"}}`)
+	}
+
+	body, err := k.body.render(secnum)
+	if err != nil {
+		k.logger.Error("error executing body template", "error", err)
+	}
+	return body
+}
+
 func (k *KVV1Test) Target(client *api.Client) vegeta.Target {
 	switch k.action {
 	case "write":
 		return k.write(client)
+	case "delete":
+		return k.delete(client)
 	case "list":
 		return k.list(client)
 	default:
@@ -119,6 +206,8 @@ func (k *KVV1Test) GetTargetInfo() TargetInfo {
 	switch k.action {
 	case "write":
 		method = KVV1WriteTestMethod
+	case "delete":
+		method = KVV1DeleteTestMethod
 	case "list":
 		method = KVV1ListTestMethod
 	default:
@@ -151,39 +240,112 @@ func (k *KVV1Test) Setup(client *api.Client, mountName string, topLevelConfig *T
 		}
 	}
 
-	k.logger.Trace(mountLogMessage("secrets", "kvv1", mountPath))
-	err = client.Sys().Mount(mountPath, &api.MountInput{
-		Type: "kv",
-	})
+	if topLevelConfig.SkipSetup {
+		k.logger.Trace("reusing existing mount", "path", mountPath)
+		err = k.checkSeeded(client, mountPath)
+	} else {
+		err = k.seed(client, mountPath, topLevelConfig)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("error mounting kv secrets engine: %v", err)
+		// The mount is returned to be cleaned up if seeding is interrupted
+		if topLevelConfig.interrupted() && !topLevelConfig.SkipSetup {
+			return &KVV1Test{pathPrefix: "/v1/" + mountPath, logger: k.logger}, err
+		}
+		return nil, err
 	}
 
 	setupLogger := k.logger.Named(mountPath)
 
+	var body *bodyTemplate
+	if k.action == "write" && k.config.BodyTemplate != "" {
+		setupLogger.Trace("parsing body template", "path", k.config.BodyTemplate)
+		body, err = newBodyTemplate(k.config.BodyTemplate, topLevelConfig.Rand)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	keys, err := newKeyDistribution(topLevelConfig.Rand, k.config.NumKVs, k.config.AccessDistribution, k.config.ZipfS)
+	if err != nil {
+		return nil, err
+	}
+	writeKeys, err := newWriteKeys(topLevelConfig.Rand, k.config.NumKVs, k.config.KeyMode)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := generateHeader(client)
+	return &KVV1Test{
+		pathPrefix: "/v1/" + mountPath,
+		action:     k.action,
+		config:     k.config,
+		header:     headers,
+		keys:       keys,
+		writeKeys:  writeKeys,
+		kvSize:     k.kvSize,
+		body:       body,
+		rng:        topLevelConfig.Rand,
+		logger:     k.logger,
+	}, nil
+}
+
+// seed mounts the KV secrets engine and writes the secrets read by the test
+func (k *KVV1Test) seed(client *api.Client, mountPath string, topLevelConfig *TopLevelTargetConfig) error {
+	k.logger.Trace(mountLogMessage("secrets", "kvv1", mountPath))
+	err := retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(mountPath, &api.MountInput{
+			Type: "kv",
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("error mounting kv secrets engine: %v", err)
+	}
+
 	secval := map[string]interface{}{
 		"data": map[string]interface{}{
 			"foo": 1,
 		},
 	}
 
+	setupLogger := k.logger.Named(mountPath)
 	setupLogger.Trace("seeding secrets")
 	for i := 1; i <= k.config.NumKVs; i++ {
-		_, err = client.Logical().Write(mountPath+"/secret-"+strconv.Itoa(i), secval)
+		err = retrySetup(topLevelConfig, func() error {
+			_, err := client.Logical().Write(mountPath+"/secret-"+strconv.Itoa(i), secval)
+			return err
+		})
 		if err != nil {
-			return nil, fmt.Errorf("error writing kvv1 secret: %v", err)
+			return fmt.Errorf("error writing kvv1 secret: %v", err)
 		}
 	}
+	return nil
+}
 
-	headers := http.Header{"X-Vault-Token": []string{client.Token()}, "X-Vault-Namespace": []string{client.Headers().Get("X-Vault-Namespace")}}
-	return &KVV1Test{
-		pathPrefix: "/v1/" + mountPath,
-		action:     k.action,
-		header:     headers,
-		numKVs:     k.config.NumKVs,
-		kvSize:     k.config.KVSize,
-		logger:     k.logger,
-	}, nil
+// checkSeeded checks that a previous run set up the mount with as many
+// secrets as the test reads, so that it can be reused
+func (k *KVV1Test) checkSeeded(client *api.Client, mountPath string) error {
+	last := strconv.Itoa(k.config.NumKVs)
+	secret, err := client.Logical().Read(mountPath + "/secret-" + last)
+	if err != nil {
+		return fmt.Errorf("error reading kvv1 secret: %v", err)
+	}
+	if secret == nil {
+		return fmt.Errorf("secret-%v not found in %v, run without skip_setup to seed %v secrets", last, mountPath, k.config.NumKVs)
+	}
+	return nil
+}
+
+// ReusesSetup reports that the test reuses the mount of a previous run when
+// setup is skipped, except for deletes, which use up the secrets a previous
+// run seeded
+func (k *KVV1Test) ReusesSetup() bool {
+	return k.action != "delete"
+}
+
+// ConsumesSetup reports that deletes use up the seeded secrets, unless they
+// repeat deletes
+func (k *KVV1Test) ConsumesSetup() bool {
+	return k.action == "delete" && k.config.DeleteMode == KVV1DeleteModePool
 }
 
 func (k *KVV1Test) Flags(fs *flag.FlagSet) {}
@@ -0,0 +1,32 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/openbao/openbao/api/v2"
+)
+
+func TestKVV1Test_DeletePool(t *testing.T) {
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Each secret of the pool is deleted once before the pool wraps around
+	k := &KVV1Test{
+		action:     "delete",
+		pathPrefix: "/v1/kv",
+		config:     &KVV1SecretTestConfig{NumKVs: 2, DeleteMode: KVV1DeleteModePool},
+		logger:     hclog.NewNullLogger(),
+	}
+	for _, expected := range []string{"/secret-1", "/secret-2", "/secret-1"} {
+		target := k.Target(client)
+		if target.Method != KVV1DeleteTestMethod || target.URL != client.Address()+"/v1/kv"+expected {
+			t.Fatalf("expected a delete of %v, got %v %v", expected, target.Method, target.URL)
+		}
+	}
+}
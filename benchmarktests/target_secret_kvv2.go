@@ -9,8 +9,11 @@ import (
 	"log"
 	"math/rand"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
@@ -22,26 +25,45 @@ import (
 )
 
 const (
-	KVV2ReadTestType    = "kvv2_read"
-	KVV2ListTestType    = "kvv2_list"
-	KVV2WriteTestType   = "kvv2_write"
-	KVV2ReadTestMethod  = "GET"
-	KVV2ListTestMethod  = "LIST"
-	KVV2WriteTestMethod = "POST"
+	KVV2ReadTestType            = "kvv2_read"
+	KVV2ReadVersionTestType     = "kvv2_read_version"
+	KVV2ListTestType            = "kvv2_list"
+	KVV2ListPaginatedTestType   = "kvv2_list_paginated"
+	KVV2WriteTestType           = "kvv2_write"
+	KVV2ConsistencyTestType     = "kvv2_consistency"
+	KVV2ReadTestMethod          = "GET"
+	KVV2ReadVersionTestMethod   = "GET"
+	KVV2ListTestMethod          = "LIST"
+	KVV2ListPaginatedTestMethod = "LIST"
+	KVV2WriteTestMethod         = "POST"
+	KVV2ConsistencyTestMethod   = "POST"
 
 	MAX_UPGRADE_RETRY = 100
+
+	// kvv2DefaultMaxVersions is the number of versions KVv2 keeps when the
+	// mount's max_versions is unset
+	kvv2DefaultMaxVersions = 10
 )
 
 func init() {
 	TestList[KVV2ReadTestType] = func() BenchmarkBuilder {
 		return &KVV2Test{action: "read"}
 	}
+	TestList[KVV2ReadVersionTestType] = func() BenchmarkBuilder {
+		return &KVV2Test{action: "read_version"}
+	}
 	TestList[KVV2WriteTestType] = func() BenchmarkBuilder {
 		return &KVV2Test{action: "write"}
 	}
 	TestList[KVV2ListTestType] = func() BenchmarkBuilder {
 		return &KVV2Test{action: "list"}
 	}
+	TestList[KVV2ListPaginatedTestType] = func() BenchmarkBuilder {
+		return &KVV2Test{action: "list_paginated"}
+	}
+	TestList[KVV2ConsistencyTestType] = func() BenchmarkBuilder {
+		return &KVV2Test{action: "consistency"}
+	}
 }
 
 type KVV2Test struct {
@@ -49,16 +71,39 @@ type KVV2Test struct {
 	header     http.Header
 	config     *KVV2SecretTestConfig
 	action     string
-	numKVs     int
-	kvSize     int
+	keys       *keyDistribution
+	writeKeys  *writeKeys
+	kvSize     payloadSize
+	versions   int
+	body       *bodyTemplate
+	rng        *rand.Rand
 	detailed   bool
 	logger     hclog.Logger
+
+	// pageSize is the number of keys listed per page by paginated lists,
+	// and pages holds the key each page starts after. The pages are walked
+	// in turn, tracked by nextPage.
+	pageSize int
+	pages    []string
+	nextPage atomic.Int64
 }
 
 type KVV2SecretTestConfig struct {
-	KVSize   int  `hcl:"kvsize,optional"`
-	NumKVs   int  `hcl:"numkvs,optional"`
-	Detailed bool `hcl:"detailed,optional"`
+	KVSize             int     `hcl:"kvsize,optional"`
+	KVSizeMin          int     `hcl:"kvsize_min,optional"`
+	KVSizeMax          int     `hcl:"kvsize_max,optional"`
+	KVSizeDistribution string  `hcl:"kvsize_distribution,optional"`
+	NumKVs             int     `hcl:"numkvs,optional"`
+	AccessDistribution string  `hcl:"access_distribution,optional"`
+	KeyMode            string  `hcl:"key_mode,optional"`
+	ZipfS              float64 `hcl:"zipf_s,optional"`
+	VersionsPerSecret  int     `hcl:"versions_per_secret,optional"`
+	VersionsBeforeRead int     `hcl:"versions_before_read,optional"`
+	BodyTemplate       string  `hcl:"body_template,optional"`
+	Detailed           bool    `hcl:"detailed,optional"`
+	MaxLag             string  `hcl:"max_lag,optional"`
+	PollInterval       string  `hcl:"poll_interval,optional"`
+	PageSize           int     `hcl:"page_size,optional"`
 }
 
 func (k *KVV2Test) ParseConfig(body hcl.Body) error {
@@ -66,22 +111,79 @@ func (k *KVV2Test) ParseConfig(body hcl.Body) error {
 		Config *KVV2SecretTestConfig `hcl:"config,block"`
 	}{
 		Config: &KVV2SecretTestConfig{
-			KVSize:   1,
-			NumKVs:   1000,
-			Detailed: false,
+			KVSize:             1,
+			KVSizeDistribution: PayloadSizeUniform,
+			NumKVs:             1000,
+			AccessDistribution: AccessDistributionUniform,
+			KeyMode:            KeyModeRandomExisting,
+			ZipfS:              1.1,
+			VersionsPerSecret:  1,
+			Detailed:           false,
+			MaxLag:             "10s",
+			PollInterval:       "10ms",
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	// Reading a historical version requires more than one version
+	if k.action == "read_version" {
+		testConfig.Config.VersionsPerSecret = 5
+	}
+	if k.action == "list_paginated" {
+		testConfig.Config.PageSize = 100
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
 	k.config = testConfig.Config
-	return nil
+
+	// Reading the current version of secrets with a deep version history
+	// measures whether the size of their metadata affects reads
+	switch {
+	case k.config.VersionsBeforeRead < 0:
+		return fmt.Errorf("versions_before_read must not be negative")
+	case k.config.VersionsBeforeRead > 0 && k.action != "read":
+		return fmt.Errorf("versions_before_read is only supported by %v", KVV2ReadTestType)
+	case k.config.VersionsBeforeRead > 0 && k.config.VersionsPerSecret != 1:
+		// versions_per_secret defaults to 1 for reads
+		return fmt.Errorf("versions_before_read cannot be combined with versions_per_secret")
+	case k.config.VersionsBeforeRead > 0:
+		k.config.VersionsPerSecret = k.config.VersionsBeforeRead
+	}
+
+	switch {
+	case k.action == "list_paginated" && k.config.PageSize < 1:
+		return fmt.Errorf("page_size must be at least 1")
+	case k.action != "list_paginated" && k.config.PageSize != 0:
+		return fmt.Errorf("page_size is only supported by %v", KVV2ListPaginatedTestType)
+	case k.action == "read_version" && k.config.VersionsPerSecret < 2:
+		return fmt.Errorf("versions_per_secret must be at least 2 to read historical versions")
+	case k.config.VersionsPerSecret < 1:
+		return fmt.Errorf("versions_per_secret must be at least 1")
+	}
+	if _, err := time.ParseDuration(k.config.MaxLag); err != nil {
+		return fmt.Errorf("error parsing max_lag: %v", err)
+	}
+	if _, err := time.ParseDuration(k.config.PollInterval); err != nil {
+		return fmt.Errorf("error parsing poll_interval: %v", err)
+	}
+
+	// The distribution itself is built during Setup
+	if _, err := newKeyDistribution(nil, k.config.NumKVs, k.config.AccessDistribution, k.config.ZipfS); err != nil {
+		return err
+	}
+	if _, err := newWriteKeys(nil, k.config.NumKVs, k.config.KeyMode); err != nil {
+		return err
+	}
+
+	var err error
+	k.kvSize, err = newPayloadSize(k.config.KVSize, k.config.KVSizeMin, k.config.KVSizeMax, k.config.KVSizeDistribution)
+	return err
 }
 
 func (k *KVV2Test) read(client *api.Client) vegeta.Target {
-	secnum := int(1 + rand.Int31n(int32(k.numKVs)))
+	secnum := k.keys.next()
 	return vegeta.Target{
 		Method: "GET",
 		URL:    client.Address() + k.pathPrefix + "/data/secret-" + strconv.Itoa(secnum),
@@ -89,6 +191,17 @@ func (k *KVV2Test) read(client *api.Client) vegeta.Target {
 	}
 }
 
+// readVersion reads a random non-current version of a secret
+func (k *KVV2Test) readVersion(client *api.Client) vegeta.Target {
+	secnum := k.keys.next()
+	version := int(1 + k.rng.Int63n(int64(k.versions-1)))
+	return vegeta.Target{
+		Method: "GET",
+		URL:    client.Address() + k.pathPrefix + "/data/secret-" + strconv.Itoa(secnum) + "?version=" + strconv.Itoa(version),
+		Header: k.header,
+	}
+}
+
 func (k *KVV2Test) list(client *api.Client) vegeta.Target {
 	path := "metadata"
 	if k.detailed {
@@ -102,23 +215,52 @@ func (k *KVV2Test) list(client *api.Client) vegeta.Target {
 	}
 }
 
+// listPage lists the next page of keys, starting over from the first page
+// once the last has been listed
+func (k *KVV2Test) listPage(client *api.Client) vegeta.Target {
+	target := k.list(client)
+	page := (k.nextPage.Add(1) - 1) % int64(len(k.pages))
+	query := url.Values{"limit": []string{strconv.Itoa(k.pageSize)}}
+	if after := k.pages[page]; after != "" {
+		query.Set("after", after)
+	}
+	target.URL += "?" + query.Encode()
+	return target
+}
+
 func (k *KVV2Test) write(client *api.Client) vegeta.Target {
-	secnum := int(1 + rand.Int31n(int32(k.numKVs)))
-	value := strings.Repeat("a", k.kvSize)
+	secnum := k.writeKeys.next()
 	return vegeta.Target{
 		Method: "POST",
 		URL:    client.Address() + k.pathPrefix + "/data/secret-" + strconv.Itoa(secnum),
 		Header: k.header,
-		Body:   []byte(`{"data": {"foo": "` + value + `"}}`),
+		Body:   k.writeBody(secnum),
+	}
+}
+
+func (k *KVV2Test) writeBody(secnum int) []byte {
+	if k.body == nil {
+		value := strings.Repeat("a", k.kvSize.next(k.rng))
+		return []byte(`{"data": {"foo": "` + value + `"}}`)
+	}
+
+	body, err := k.body.render(secnum)
+	if err != nil {
+		k.logger.Error("error executing body template", "error", err)
 	}
+	return body
 }
 
 func (k *KVV2Test) Target(client *api.Client) vegeta.Target {
 	switch k.action {
-	case "write":
+	case "write", "consistency":
 		return k.write(client)
 	case "list":
 		return k.list(client)
+	case "list_paginated":
+		return k.listPage(client)
+	case "read_version":
+		return k.readVersion(client)
 	default:
 		return k.read(client)
 	}
@@ -131,6 +273,12 @@ func (k *KVV2Test) GetTargetInfo() TargetInfo {
 		method = KVV2WriteTestMethod
 	case "list":
 		method = KVV2ListTestMethod
+	case "list_paginated":
+		method = KVV2ListPaginatedTestMethod
+	case "read_version":
+		method = KVV2ReadVersionTestMethod
+	case "consistency":
+		method = KVV2ConsistencyTestMethod
 	default:
 		method = KVV2ReadTestMethod
 	}
@@ -157,6 +305,12 @@ func (k *KVV2Test) Setup(client *api.Client, mountName string, topLevelConfig *T
 		k.logger = targetLogger.Named(KVV2WriteTestType)
 	case "list":
 		k.logger = targetLogger.Named(KVV2ListTestType)
+	case "list_paginated":
+		k.logger = targetLogger.Named(KVV2ListPaginatedTestType)
+	case "read_version":
+		k.logger = targetLogger.Named(KVV2ReadVersionTestType)
+	case "consistency":
+		k.logger = targetLogger.Named(KVV2ConsistencyTestType)
 	default:
 		k.logger = targetLogger.Named(KVV2ReadTestType)
 	}
@@ -168,57 +322,211 @@ func (k *KVV2Test) Setup(client *api.Client, mountName string, topLevelConfig *T
 		}
 	}
 
-	k.logger.Trace(mountLogMessage("secrets", "kvv2", mountPath))
-	err = client.Sys().Mount(mountPath, &api.MountInput{
-		Type: "kv",
-		Options: map[string]string{
-			"version": "2",
-		},
-	})
+	if topLevelConfig.SkipSetup {
+		k.logger.Trace("reusing existing mount", "path", mountPath)
+		err = k.checkSeeded(client, mountPath)
+	} else {
+		err = k.seed(client, mountPath, topLevelConfig)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("error mounting kv secrets engine: %v", err)
+		// The mount is returned to be cleaned up if seeding is interrupted
+		if topLevelConfig.interrupted() && !topLevelConfig.SkipSetup {
+			return &KVV2Test{pathPrefix: "/v1/" + mountPath, logger: k.logger}, err
+		}
+		return nil, err
 	}
 
 	setupLogger := k.logger.Named(mountPath)
 
+	var body *bodyTemplate
+	if (k.action == "write" || k.action == "consistency") && k.config.BodyTemplate != "" {
+		setupLogger.Trace("parsing body template", "path", k.config.BodyTemplate)
+		body, err = newBodyTemplate(k.config.BodyTemplate, topLevelConfig.Rand)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	keys, err := newKeyDistribution(topLevelConfig.Rand, k.config.NumKVs, k.config.AccessDistribution, k.config.ZipfS)
+	if err != nil {
+		return nil, err
+	}
+	writeKeys, err := newWriteKeys(topLevelConfig.Rand, k.config.NumKVs, k.config.KeyMode)
+	if err != nil {
+		return nil, err
+	}
+
+	test := &KVV2Test{
+		pathPrefix: "/v1/" + mountPath,
+		header:     generateHeader(client),
+		keys:       keys,
+		writeKeys:  writeKeys,
+		kvSize:     k.kvSize,
+		versions:   k.config.VersionsPerSecret,
+		body:       body,
+		rng:        topLevelConfig.Rand,
+		detailed:   k.config.Detailed,
+		logger:     k.logger,
+		action:     k.action,
+	}
+	if k.action == "consistency" {
+		return k.setupConsistency(client, test, topLevelConfig)
+	}
+	if k.action == "list_paginated" {
+		if err := k.checkPagination(client, mountPath); err != nil {
+			// The mount is returned to be cleaned up if pagination isn't
+			// supported, unless it was set up by a previous run
+			if isUnsupported(err) && !topLevelConfig.SkipSetup {
+				return test, err
+			}
+			return nil, err
+		}
+		test.pageSize = k.config.PageSize
+		test.pages = kvv2Pages(k.config.NumKVs, k.config.PageSize)
+	}
+	return test, nil
+}
+
+// checkPagination checks that the server limits the keys it lists, as
+// servers without support for paginated lists ignore limit and list every key
+func (k *KVV2Test) checkPagination(client *api.Client, mountPath string) error {
+	path := mountPath + "/metadata"
+	if k.config.Detailed {
+		path = mountPath + "/detailed-metadata"
+	}
+	secret, err := client.Logical().ListPage(path, "", 1)
+	if err != nil {
+		return fmt.Errorf("error listing a page of kv secrets: %v", err)
+	}
+	if secret == nil {
+		return fmt.Errorf("no kv secrets found in %v", mountPath)
+	}
+	if keys, _ := secret.Data["keys"].([]interface{}); len(keys) > 1 {
+		return fmt.Errorf("error listing a page of kv secrets: %w: listed %d keys with a limit of 1", errUnsupported, len(keys))
+	}
+	return nil
+}
+
+// kvv2Pages returns the key each page of n seeded secrets starts after, with
+// the first page starting from the beginning. Keys are listed in
+// lexicographic order, so secret-10 comes before secret-2.
+func kvv2Pages(n, pageSize int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = "secret-" + strconv.Itoa(i+1)
+	}
+	sort.Strings(keys)
+
+	pages := []string{""}
+	for i := pageSize; i < n; i += pageSize {
+		pages = append(pages, keys[i-1])
+	}
+	return pages
+}
+
+// seed mounts the KVv2 secrets engine and writes every version of the
+// secrets read by the test
+func (k *KVV2Test) seed(client *api.Client, mountPath string, topLevelConfig *TopLevelTargetConfig) error {
+	k.logger.Trace(mountLogMessage("secrets", "kvv2", mountPath))
+	err := retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(mountPath, &api.MountInput{
+			Type: "kv",
+			Options: map[string]string{
+				"version": "2",
+			},
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("error mounting kv secrets engine: %v", err)
+	}
+
 	secval := map[string]interface{}{
 		"data": map[string]interface{}{
 			"foo": 1,
 		},
 	}
 
+	if err := waitForKVV2Upgrade(client, mountPath); err != nil {
+		return err
+	}
+
+	// Make sure the mount keeps every version that is written
+	if k.config.VersionsPerSecret > kvv2DefaultMaxVersions {
+		err = retrySetup(topLevelConfig, func() error {
+			_, err := client.Logical().Write(mountPath+"/config", map[string]interface{}{
+				"max_versions": k.config.VersionsPerSecret,
+			})
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("error configuring kv secrets engine: %v", err)
+		}
+	}
+
+	setupLogger := k.logger.Named(mountPath)
+	setupLogger.Trace("seeding secrets", "versions", k.config.VersionsPerSecret)
+	for i := 1; i <= k.config.NumKVs; i++ {
+		for v := 1; v <= k.config.VersionsPerSecret; v++ {
+			err = retrySetup(topLevelConfig, func() error {
+				_, err := client.Logical().Write(mountPath+"/data/secret-"+strconv.Itoa(i), secval)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("error writing kv secret: %v", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// waitForKVV2Upgrade waits for a newly mounted KVv2 secrets engine to finish
+// upgrading its storage, which it does before serving any requests
+func waitForKVV2Upgrade(client *api.Client, mountPath string) error {
 	// TODO: Find more deterministic way of avoiding this
 	// Avoid error of the form:
 	// * Upgrading from non-versioned to versioned data. This backend will be unavailable for a brief period and will resume service shortly.
+	var err error
 	for i := 1; i <= MAX_UPGRADE_RETRY; i++ {
 		_, err = client.Logical().Read(mountPath + "/config")
 		if err == nil {
-			break
+			return nil
 		}
 		if !strings.Contains(err.Error(), "Upgrading from non-versioned to versioned data.") {
-			return nil, fmt.Errorf("cannot read KVv2 configuration: %w", err)
+			return fmt.Errorf("cannot read KVv2 configuration: %w", err)
 		}
 
 		time.Sleep(time.Duration(i) * 10 * time.Millisecond)
 	}
+	return nil
+}
 
-	setupLogger.Trace("seeding secrets")
-	for i := 1; i <= k.config.NumKVs; i++ {
-		_, err = client.Logical().Write(mountPath+"/data/secret-"+strconv.Itoa(i), secval)
-		if err != nil {
-			return nil, fmt.Errorf("error writing kv secret: %v", err)
-		}
+// checkSeeded checks that a previous run set up the mount with as many
+// secrets and versions as the test reads, so that it can be reused
+func (k *KVV2Test) checkSeeded(client *api.Client, mountPath string) error {
+	last := strconv.Itoa(k.config.NumKVs)
+	secret, err := client.Logical().Read(mountPath + "/metadata/secret-" + last)
+	if err != nil {
+		return fmt.Errorf("error reading kv secret metadata: %v", err)
+	}
+	if secret == nil {
+		return fmt.Errorf("secret-%v not found in %v, run without skip_setup to seed %v secrets", last, mountPath, k.config.NumKVs)
 	}
 
-	return &KVV2Test{
-		pathPrefix: "/v1/" + mountPath,
-		header:     http.Header{"X-Vault-Token": []string{client.Token()}, "X-Vault-Namespace": []string{client.Headers().Get("X-Vault-Namespace")}},
-		numKVs:     k.config.NumKVs,
-		kvSize:     k.config.KVSize,
-		detailed:   k.config.Detailed,
-		logger:     k.logger,
-		action:     k.action,
-	}, nil
+	version, err := strconv.Atoi(fmt.Sprint(secret.Data["current_version"]))
+	if err != nil {
+		return fmt.Errorf("error parsing current_version of secret-%v: %v", last, err)
+	}
+	if version < k.config.VersionsPerSecret {
+		return fmt.Errorf("secret-%v in %v has %v versions, run without skip_setup to seed %v", last, mountPath, version, k.config.VersionsPerSecret)
+	}
+	return nil
+}
+
+// ReusesSetup reports that the test reuses the mount of a previous run when
+// setup is skipped
+func (k *KVV2Test) ReusesSetup() bool {
+	return true
 }
 
 func (k *KVV2Test) Flags(fs *flag.FlagSet) {}
@@ -0,0 +1,143 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+// consistencyKVV2Test is a KVv2 write test which reads every secret it
+// successfully writes back from a standby node, waiting until the written
+// version is visible there
+type consistencyKVV2Test struct {
+	*KVV2Test
+
+	standbys     []string
+	next         atomic.Uint64
+	maxLag       time.Duration
+	pollInterval time.Duration
+}
+
+// setupConsistency finds the standby nodes among the cluster's addresses and
+// wraps the set up test so that its writes are read back from them
+func (k *KVV2Test) setupConsistency(client *api.Client, test *KVV2Test, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var standbys []string
+	for _, addr := range topLevelConfig.Addrs {
+		nodeClient, err := client.Clone()
+		if err != nil {
+			return nil, fmt.Errorf("error creating client for %v: %v", addr, err)
+		}
+		if err := nodeClient.SetAddress(addr); err != nil {
+			return nil, fmt.Errorf("error setting address %v: %v", addr, err)
+		}
+
+		health, err := nodeClient.Sys().Health()
+		if err != nil {
+			return nil, fmt.Errorf("error checking health of %v: %v", addr, err)
+		}
+		if !health.Standby {
+			continue
+		}
+		if addr == client.Address() {
+			k.logger.Warn("writes are sent to a standby node and forwarded to the active node", "address", addr)
+		}
+		standbys = append(standbys, strings.TrimSuffix(addr, "/"))
+	}
+	if len(standbys) == 0 {
+		return nil, fmt.Errorf("no standby nodes found, set vault_addrs or cluster_json to the addresses of every node")
+	}
+	k.logger.Debug("reading writes back from standby nodes", "standbys", standbys)
+
+	// Both were validated while parsing the config
+	maxLag, _ := time.ParseDuration(k.config.MaxLag)
+	pollInterval, _ := time.ParseDuration(k.config.PollInterval)
+
+	return &consistencyKVV2Test{
+		KVV2Test:     test,
+		standbys:     standbys,
+		maxLag:       maxLag,
+		pollInterval: pollInterval,
+	}, nil
+}
+
+// Verify polls a standby until the version of the secret created by the
+// write is visible. The write is counted as a mismatch if the first read was
+// stale, and as an error if the version isn't visible within max_lag.
+func (c *consistencyKVV2Test) Verify(client *http.Client, result *vegeta.Result) (Verified, error) {
+	written := result.Timestamp.Add(result.Latency)
+
+	var write struct {
+		Data struct {
+			Version int `json:"version"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(result.Body, &write); err != nil {
+		return Verified{}, fmt.Errorf("error decoding write response: %v", err)
+	}
+
+	i := strings.Index(result.URL, c.pathPrefix)
+	if i < 0 {
+		return Verified{}, fmt.Errorf("unexpected write url: %v", result.URL)
+	}
+	standby := c.standbys[(c.next.Add(1)-1)%uint64(len(c.standbys))]
+	url := standby + result.URL[i:]
+
+	deadline := written.Add(c.maxLag)
+	for reads := 1; ; reads++ {
+		version, err := c.readVersion(client, url)
+		if err != nil {
+			return Verified{}, err
+		}
+		if version >= write.Data.Version {
+			return Verified{Matched: reads == 1, Lag: time.Since(written)}, nil
+		}
+		if time.Now().After(deadline) {
+			return Verified{}, fmt.Errorf("version %d not visible on %v after %v", write.Data.Version, standby, c.maxLag)
+		}
+		time.Sleep(c.pollInterval)
+	}
+}
+
+// readVersion returns the current version of the secret at url, or 0 if it
+// doesn't exist yet
+func (c *consistencyKVV2Test) readVersion(client *http.Client, url string) (int, error) {
+	req, err := http.NewRequest(KVV2ReadTestMethod, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header = c.header.Clone()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status reading secret: %v", resp.Status)
+	}
+
+	var read struct {
+		Data struct {
+			Metadata struct {
+				Version int `json:"version"`
+			} `json:"metadata"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&read); err != nil {
+		return 0, fmt.Errorf("error decoding secret: %v", err)
+	}
+	return read.Data.Metadata.Version, nil
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/openbao/openbao/api/v2"
+)
+
+func TestKVV2Test_VersionsBeforeRead(t *testing.T) {
+	hclFile, diags := hclparse.NewParser().ParseHCL([]byte(`
+config {
+  numkvs = 2
+  versions_before_read = 50
+}
+`), "kvv2.hcl")
+	if diags.HasErrors() {
+		t.Fatalf("err: %v", diags)
+	}
+
+	write := &KVV2Test{action: "write"}
+	if err := write.ParseConfig(hclFile.Body); err == nil {
+		t.Fatal("expected error using versions_before_read with kvv2_write")
+	}
+
+	negative, diags := hclparse.NewParser().ParseHCL([]byte(`
+config {
+  versions_before_read = -1
+}
+`), "kvv2.hcl")
+	if diags.HasErrors() {
+		t.Fatalf("err: %v", diags)
+	}
+	if err := (&KVV2Test{action: "read"}).ParseConfig(negative.Body); err == nil {
+		t.Fatal("expected error using a negative versions_before_read")
+	}
+
+	both, diags := hclparse.NewParser().ParseHCL([]byte(`
+config {
+  versions_per_secret = 20
+  versions_before_read = 50
+}
+`), "kvv2.hcl")
+	if diags.HasErrors() {
+		t.Fatalf("err: %v", diags)
+	}
+	if err := (&KVV2Test{action: "read"}).ParseConfig(both.Body); err == nil {
+		t.Fatal("expected error using versions_before_read with versions_per_secret")
+	}
+
+	// Count the versions written to each secret and the max_versions the
+	// mount is configured with
+	var mu sync.Mutex
+	writes := make(map[string]int)
+	var maxVersions string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/kvv2/config":
+			fmt.Fprint(w, `{"data":{}}`)
+		case r.URL.Path == "/v1/kvv2/config":
+			var body struct {
+				MaxVersions json.Number `json:"max_versions"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			maxVersions = body.MaxVersions.String()
+			w.WriteHeader(http.StatusNoContent)
+		case strings.HasPrefix(r.URL.Path, "/v1/kvv2/data/"):
+			writes[strings.TrimPrefix(r.URL.Path, "/v1/kvv2/data/")]++
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	client, err := api.NewClient(config)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	logger := targetLogger
+	t.Cleanup(func() { targetLogger = logger })
+	targetLogger = hclog.NewNullLogger()
+
+	read := &KVV2Test{action: "read"}
+	if err := read.ParseConfig(hclFile.Body); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	builder, err := read.Setup(client, "kvv2", &TopLevelTargetConfig{Rand: NewRand(1)})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if expected := map[string]int{"secret-1": 50, "secret-2": 50}; !reflect.DeepEqual(writes, expected) {
+		t.Fatalf("expected 50 versions of each secret, got: %v", writes)
+	}
+	if maxVersions != "50" {
+		t.Fatalf("expected max_versions of 50, got: %q", maxVersions)
+	}
+
+	// Reads fetch the current version
+	for i := 0; i < 10; i++ {
+		target := builder.Target(client)
+		u, err := url.Parse(target.URL)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if target.Method != KVV2ReadTestMethod || !strings.HasPrefix(u.Path, "/v1/kvv2/data/secret-") || u.RawQuery != "" {
+			t.Fatalf("expected a read of the current version, got %v %v", target.Method, target.URL)
+		}
+	}
+}
+
+func TestKVV2Pages(t *testing.T) {
+	// Keys are listed as secret-1, secret-10, secret-11, secret-2, ...
+	pages := kvv2Pages(11, 2)
+	expected := []string{"", "secret-10", "secret-2", "secret-4", "secret-6", "secret-8"}
+	if !reflect.DeepEqual(pages, expected) {
+		t.Fatalf("expected pages after %v, got: %v", expected, pages)
+	}
+}
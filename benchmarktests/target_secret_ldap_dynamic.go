@@ -87,7 +87,7 @@ func (r *LDAPDynamicSecretTest) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -138,8 +138,10 @@ func (r *LDAPDynamicSecretTest) Setup(client *api.Client, mountName string, topL
 	}
 
 	r.logger.Trace(mountLogMessage("secrets", "ldap", secretPath))
-	err = client.Sys().Mount(secretPath, &api.MountInput{
-		Type: "ldap",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "ldap",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting ldap secrets engine: %v", err)
@@ -22,13 +22,16 @@ import (
 // Constants for test
 const (
 	LDAPStaticSecretTestType       = "ldap_static_secret"
+	LDAPStaticSecretReadTestType   = "ldap_static_secret_read"
 	LDAPStaticSecretTestMethod     = "POST"
+	LDAPStaticSecretReadTestMethod = "GET"
 	LDAPStaticSecretBindPassEnvVar = VaultBenchmarkEnvVarPrefix + "LDAP_BIND_PASS"
 )
 
 func init() {
 	// "Register" this test to the main test registry
 	TestList[LDAPStaticSecretTestType] = func() BenchmarkBuilder { return &LDAPStaticSecretTest{action: "rotate"} }
+	TestList[LDAPStaticSecretReadTestType] = func() BenchmarkBuilder { return &LDAPStaticSecretTest{action: "read"} }
 }
 
 type LDAPStaticSecretTest struct {
@@ -82,7 +85,7 @@ func (r *LDAPStaticSecretTest) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -97,10 +100,19 @@ func (r *LDAPStaticSecretTest) ParseConfig(body hcl.Body) error {
 }
 
 func (r *LDAPStaticSecretTest) Target(client *api.Client) vegeta.Target {
-	return vegeta.Target{
-		Method: LDAPStaticSecretTestMethod,
-		URL:    client.Address() + r.pathPrefix + "/rotate-role/" + r.roleName,
-		Header: r.header,
+	switch r.action {
+	case "read":
+		return vegeta.Target{
+			Method: LDAPStaticSecretReadTestMethod,
+			URL:    client.Address() + r.pathPrefix + "/static-cred/" + r.roleName,
+			Header: r.header,
+		}
+	default:
+		return vegeta.Target{
+			Method: LDAPStaticSecretTestMethod,
+			URL:    client.Address() + r.pathPrefix + "/rotate-role/" + r.roleName,
+			Header: r.header,
+		}
 	}
 }
 
@@ -114,8 +126,12 @@ func (r *LDAPStaticSecretTest) Cleanup(client *api.Client) error {
 }
 
 func (r *LDAPStaticSecretTest) GetTargetInfo() TargetInfo {
+	method := LDAPStaticSecretTestMethod
+	if r.action == "read" {
+		method = LDAPStaticSecretReadTestMethod
+	}
 	return TargetInfo{
-		method:     LDAPStaticSecretTestMethod,
+		method:     method,
 		pathPrefix: r.pathPrefix,
 	}
 }
@@ -133,8 +149,10 @@ func (r *LDAPStaticSecretTest) Setup(client *api.Client, mountName string, topLe
 	}
 
 	r.logger.Trace(mountLogMessage("secrets", "ldap", secretPath))
-	err = client.Sys().Mount(secretPath, &api.MountInput{
-		Type: "ldap",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "ldap",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting ldap secrets engine: %v", err)
@@ -175,6 +193,7 @@ func (r *LDAPStaticSecretTest) Setup(client *api.Client, mountName string, topLe
 		header:     generateHeader(client),
 		roleName:   r.config.LDAPStaticRoleConfig.Username,
 		logger:     r.logger,
+		action:     r.action,
 	}, nil
 }
 
@@ -90,7 +90,7 @@ func (m *MongoDBTest) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -145,8 +145,10 @@ func (m *MongoDBTest) Setup(client *api.Client, mountName string, topLevelConfig
 	}
 
 	m.logger.Trace(mountLogMessage("secrets", "database", secretPath))
-	err = client.Sys().Mount(secretPath, &api.MountInput{
-		Type: "database",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "database",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting db secrets engine: %v", err)
@@ -101,7 +101,7 @@ func (m *MSSQLSecret) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -156,8 +156,10 @@ func (m *MSSQLSecret) Setup(client *api.Client, mountName string, topLevelConfig
 
 	// Create Database Secret Mount
 	m.logger.Trace(mountLogMessage("secrets", "database", secretPath))
-	err = client.Sys().Mount(secretPath, &api.MountInput{
-		Type: "database",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "database",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting db secrets engine: %v", err)
@@ -175,7 +177,10 @@ func (m *MSSQLSecret) Setup(client *api.Client, mountName string, topLevelConfig
 	// Set up db
 	setupLogger.Trace(writingLogMessage("mssql db config"), "name", m.config.MSSQLDBConfig.Name)
 	dbPath := filepath.Join(secretPath, "config", m.config.MSSQLDBConfig.Name)
-	_, err = client.Logical().Write(dbPath, dbData)
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write(dbPath, dbData)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error writing mssql db config: %v", err)
 	}
@@ -104,7 +104,7 @@ func (m *MySQLSecret) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -160,8 +160,10 @@ func (m *MySQLSecret) Setup(client *api.Client, mountName string, topLevelConfig
 
 	// Create Database Secret Mount
 	m.logger.Trace(mountLogMessage("secrets", "database", secretPath))
-	err = client.Sys().Mount(secretPath, &api.MountInput{
-		Type: "database",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "database",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting db secrets engine: %v", err)
@@ -179,7 +181,10 @@ func (m *MySQLSecret) Setup(client *api.Client, mountName string, topLevelConfig
 	// Set up db
 	setupLogger.Trace(writingLogMessage("mysql db config"), "name", m.config.MySQLDBConfig.Name)
 	dbPath := filepath.Join(secretPath, "config", m.config.MySQLDBConfig.Name)
-	_, err = client.Logical().Write(dbPath, dbData)
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write(dbPath, dbData)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error writing mysql db config: %v", err)
 	}
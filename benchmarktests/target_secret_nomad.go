@@ -74,7 +74,7 @@ func (c *NomadTest) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -125,8 +125,10 @@ func (c *NomadTest) Setup(client *api.Client, mountName string, topLevelConfig *
 	}
 
 	c.logger.Trace(mountLogMessage("secrets", "nomad", secretPath))
-	err = client.Sys().Mount(secretPath, &api.MountInput{
-		Type: "nomad",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "nomad",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting nomad: %v", err)
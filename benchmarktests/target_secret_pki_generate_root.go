@@ -0,0 +1,199 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	PKIGenerateRootTestType   = "pki_generate_root"
+	PKIGenerateRootTestMethod = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[PKIGenerateRootTestType] = func() BenchmarkBuilder { return &PKIGenerateRootTest{} }
+}
+
+// PKIGenerateRootTest generates CAs in PKI mounts created during setup. Each
+// request goes to the next mount in turn, so that the first num_cas requests
+// each generate the only CA of a freshly mounted engine.
+type PKIGenerateRootTest struct {
+	pathPrefix  string
+	mountPrefix string
+	genPath     string
+	body        []byte
+	header      http.Header
+	config      *PKIGenerateRootTestConfig
+	logger      hclog.Logger
+
+	// sent counts the requests sent so far, picking the mount of the next
+	sent atomic.Uint64
+}
+
+type PKIGenerateRootTestConfig struct {
+	NumCAs       int    `hcl:"num_cas,optional"`
+	KeyType      string `hcl:"key_type,optional"`
+	KeyBits      int    `hcl:"key_bits,optional"`
+	CommonName   string `hcl:"common_name,optional"`
+	Intermediate bool   `hcl:"intermediate,optional"`
+	SetupDelay   string `hcl:"setup_delay,optional"`
+}
+
+// pkiKeyBits are the key sizes each key type can be generated with, where 0
+// is the key type's default
+var pkiKeyBits = map[string][]int{
+	"rsa":     {0, 2048, 3072, 4096, 8192},
+	"ec":      {0, 224, 256, 384, 521},
+	"ed25519": {0},
+}
+
+func (p *PKIGenerateRootTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *PKIGenerateRootTestConfig `hcl:"config,block"`
+	}{
+		Config: &PKIGenerateRootTestConfig{
+			NumCAs:     100,
+			KeyType:    "rsa",
+			CommonName: "example.com",
+			SetupDelay: "1s",
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	p.config = testConfig.Config
+
+	if p.config.NumCAs < 1 {
+		return fmt.Errorf("num_cas must be at least 1")
+	}
+	bits, ok := pkiKeyBits[p.config.KeyType]
+	if !ok {
+		return fmt.Errorf("key_type must be one of rsa, ec or ed25519")
+	}
+	valid := false
+	for _, b := range bits {
+		valid = valid || b == p.config.KeyBits
+	}
+	if !valid {
+		return fmt.Errorf("key_bits %d is not supported with key_type %v", p.config.KeyBits, p.config.KeyType)
+	}
+	if _, err := time.ParseDuration(p.config.SetupDelay); err != nil {
+		return fmt.Errorf("error parsing setup_delay: %v", err)
+	}
+	return nil
+}
+
+func (p *PKIGenerateRootTest) Target(client *api.Client) vegeta.Target {
+	n := (p.sent.Add(1) - 1) % uint64(p.config.NumCAs)
+	return vegeta.Target{
+		Method: PKIGenerateRootTestMethod,
+		URL:    client.Address() + p.pathPrefix + strconv.FormatUint(n, 10) + p.genPath,
+		Body:   p.body,
+		Header: p.header,
+	}
+}
+
+func (p *PKIGenerateRootTest) Cleanup(client *api.Client) error {
+	p.logger.Trace("cleaning up mounts", "prefix", p.mountPrefix, "count", p.config.NumCAs)
+	var errs []error
+	for i := 0; i < p.config.NumCAs; i++ {
+		mountPath := p.mountPrefix + "-" + strconv.Itoa(i)
+		if err := client.Sys().Unmount(mountPath); err != nil {
+			errs = append(errs, fmt.Errorf("error cleaning up %v: %w", mountPath, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *PKIGenerateRootTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     PKIGenerateRootTestMethod,
+		pathPrefix: p.pathPrefix,
+	}
+}
+
+func (p *PKIGenerateRootTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	mountPrefix := mountName
+	p.logger = targetLogger.Named(PKIGenerateRootTestType)
+
+	if topLevelConfig.RandomMounts {
+		mountPrefix, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	setupLogger := p.logger.Named(mountPrefix)
+	setupLogger.Info("creating mounts", "count", p.config.NumCAs)
+	for i := 0; i < p.config.NumCAs; i++ {
+		mountPath := mountPrefix + "-" + strconv.Itoa(i)
+		err = retrySetup(topLevelConfig, func() error {
+			return client.Sys().Mount(mountPath, &api.MountInput{
+				Type: "pki",
+				Config: api.MountConfigInput{
+					MaxLeaseTTL: "87600h",
+				},
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error mounting pki secrets engine: %v", err)
+		}
+
+		if (i+1)%mountRoutingProgress == 0 {
+			setupLogger.Debug("created mounts", "count", i+1)
+		}
+	}
+
+	// New PKI mounts may not route requests straight away, see pki_issue
+	delay, _ := time.ParseDuration(p.config.SetupDelay)
+	time.Sleep(delay)
+
+	genPath := "/root/generate/internal"
+	if p.config.Intermediate {
+		genPath = "/intermediate/generate/internal"
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"common_name": p.config.CommonName,
+		"key_type":    p.config.KeyType,
+		"key_bits":    p.config.KeyBits,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling pki generate data: %v", err)
+	}
+
+	// The prefix ends with the separator before each mount's number, so
+	// that results of other mounts sharing the prefix aren't matched
+	return &PKIGenerateRootTest{
+		pathPrefix:  "/v1/" + mountPrefix + "-",
+		mountPrefix: mountPrefix,
+		genPath:     genPath,
+		body:        body,
+		header:      generateHeader(client),
+		config:      p.config,
+		logger:      p.logger,
+	}, nil
+}
+
+func (p *PKIGenerateRootTest) Flags(fs *flag.FlagSet) {}
@@ -0,0 +1,55 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/openbao/openbao/api/v2"
+)
+
+func TestPKIGenerateRootTest_ParseConfig(t *testing.T) {
+	for fixture, valid := range map[string]bool{
+		"pki_generate_root.hcl":                     true,
+		"pki_generate_root_ec.hcl":                  true,
+		"pki_generate_root_ed25519.hcl":             true,
+		"invalid_pki_generate_root_key_bits.hcl":    false,
+		"invalid_pki_generate_root_ec_key_bits.hcl": false,
+		"invalid_pki_generate_root_key_type.hcl":    false,
+		"invalid_pki_generate_root_num_cas.hcl":     false,
+	} {
+		hclFile, diags := hclparse.NewParser().ParseHCLFile(filepath.Join(FixturePath, fixture))
+		if diags != nil {
+			t.Fatalf("err: %v", diags)
+		}
+		err := (&PKIGenerateRootTest{}).ParseConfig(hclFile.Body)
+		if valid && err != nil {
+			t.Fatalf("unexpected error for %v: %v", fixture, err)
+		}
+		if !valid && err == nil {
+			t.Fatalf("expected error for %v", fixture)
+		}
+	}
+}
+
+func TestPKIGenerateRootTest_Target(t *testing.T) {
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Requests go to each mount in turn, starting over after the last
+	p := &PKIGenerateRootTest{
+		pathPrefix: "/v1/pki-",
+		genPath:    "/root/generate/internal",
+		config:     &PKIGenerateRootTestConfig{NumCAs: 2},
+	}
+	for _, mount := range []string{"pki-0", "pki-1", "pki-0"} {
+		if url := p.Target(client).URL; url != client.Address()+"/v1/"+mount+"/root/generate/internal" {
+			t.Fatalf("expected a request to %v, got %v", mount, url)
+		}
+	}
+}
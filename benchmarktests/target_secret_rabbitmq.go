@@ -78,7 +78,7 @@ func (r *RabbitMQTest) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -132,8 +132,10 @@ func (r *RabbitMQTest) Setup(client *api.Client, mountName string, topLevelConfi
 	}
 
 	r.logger.Trace(mountLogMessage("secrets", "rabbitmq", secretPath))
-	err = client.Sys().Mount(secretPath, &api.MountInput{
-		Type: "rabbitmq",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "rabbitmq",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting rabbitmq secrets engine: %v", err)
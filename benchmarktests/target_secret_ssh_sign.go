@@ -127,7 +127,7 @@ func (s *SSHKeySignTest) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -174,8 +174,10 @@ func (s *SSHKeySignTest) Setup(client *api.Client, mountName string, topLevelCon
 
 	// Create SSH Secrets engine Mount
 	s.logger.Trace(mountLogMessage("secrets", "ssh", mountPath))
-	err = client.Sys().Mount(mountPath, &api.MountInput{
-		Type: "ssh",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(mountPath, &api.MountInput{
+			Type: "ssh",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting ssh secrets engine: %v", err)
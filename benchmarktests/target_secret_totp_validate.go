@@ -0,0 +1,257 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"hash"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+// Constants for test
+const (
+	TOTPValidateTestType   = "totp_validate"
+	TOTPValidateTestMethod = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[TOTPValidateTestType] = func() BenchmarkBuilder { return &TOTPValidateTest{} }
+}
+
+type TOTPValidateTest struct {
+	pathPrefix string
+	header     http.Header
+	keys       []totpKey
+	config     *TOTPValidateTestConfig
+	rng        *rand.Rand
+	logger     hclog.Logger
+
+	// lastStep is the last time step a valid code was sent for each key.
+	// Codes are rejected once used, so any further requests for a key in
+	// the same time step send an invalid code instead.
+	mu       sync.Mutex
+	lastStep []int64
+}
+
+type TOTPValidateTestConfig struct {
+	NumKeys   int    `hcl:"num_keys,optional"`
+	Period    int    `hcl:"period,optional"`
+	Digits    int    `hcl:"digits,optional"`
+	Algorithm string `hcl:"algorithm,optional"`
+	Skew      int    `hcl:"skew,optional"`
+}
+
+// totpKey is a key which OpenBao validates codes for, while the codes
+// themselves are generated by the benchmark
+type totpKey struct {
+	name   string
+	secret []byte
+}
+
+func (t *TOTPValidateTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *TOTPValidateTestConfig `hcl:"config,block"`
+	}{
+		Config: &TOTPValidateTestConfig{
+			NumKeys:   100,
+			Period:    30,
+			Digits:    6,
+			Algorithm: "SHA1",
+			Skew:      1,
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	t.config = testConfig.Config
+
+	switch {
+	case t.config.NumKeys < 1:
+		return fmt.Errorf("num_keys must be at least 1")
+	case t.config.Period < 1:
+		return fmt.Errorf("period must be at least 1")
+	case t.config.Digits != 6 && t.config.Digits != 8:
+		return fmt.Errorf("digits must be either 6 or 8")
+	case t.config.Skew != 0 && t.config.Skew != 1:
+		return fmt.Errorf("skew must be either 0 or 1")
+	}
+	if totpHash(t.config.Algorithm) == nil {
+		return fmt.Errorf("algorithm must be one of SHA1, SHA256 or SHA512")
+	}
+	return nil
+}
+
+func (t *TOTPValidateTest) Target(client *api.Client) vegeta.Target {
+	i := t.rng.Intn(len(t.keys))
+	key := t.keys[i]
+	step := time.Now().Unix() / int64(t.config.Period)
+
+	code := totpCode(t.config.Algorithm, key.secret, step, t.config.Digits)
+
+	t.mu.Lock()
+	if t.lastStep[i] == step {
+		// Shifting the code makes it invalid, so OpenBao compares it against
+		// every window within the skew before rejecting it
+		code = (code + 1) % totpModulus(t.config.Digits)
+	}
+	t.lastStep[i] = step
+	t.mu.Unlock()
+
+	body, err := json.Marshal(map[string]string{
+		"code": fmt.Sprintf("%0*d", t.config.Digits, code),
+	})
+	if err != nil {
+		t.logger.Error("error marshaling totp code", "error", err)
+	}
+
+	return vegeta.Target{
+		Method: TOTPValidateTestMethod,
+		URL:    client.Address() + t.pathPrefix + "/code/" + key.name,
+		Body:   body,
+		Header: t.header,
+	}
+}
+
+func (t *TOTPValidateTest) Cleanup(client *api.Client) error {
+	t.logger.Trace(cleanupLogMessage(t.pathPrefix))
+	_, err := client.Logical().Delete(strings.Replace(t.pathPrefix, "/v1/", "/sys/mounts/", 1))
+	if err != nil {
+		return fmt.Errorf("error cleaning up mount: %v", err)
+	}
+	return nil
+}
+
+func (t *TOTPValidateTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     TOTPValidateTestMethod,
+		pathPrefix: t.pathPrefix,
+	}
+}
+
+func (t *TOTPValidateTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	secretPath := mountName
+	t.logger = targetLogger.Named(TOTPValidateTestType)
+
+	if topLevelConfig.RandomMounts {
+		secretPath, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	t.logger.Trace(mountLogMessage("secrets", "totp", secretPath))
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "totp",
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error mounting totp secrets engine: %v", err)
+	}
+
+	setupLogger := t.logger.Named(secretPath)
+
+	// The keys are imported rather than generated by OpenBao so that the
+	// benchmark can generate codes for them
+	setupLogger.Trace("creating totp keys", "count", t.config.NumKeys)
+	keys := make([]totpKey, t.config.NumKeys)
+	for i := range keys {
+		secret, err := uuid.GenerateRandomBytes(20)
+		if err != nil {
+			return nil, fmt.Errorf("error generating totp secret: %v", err)
+		}
+		keys[i] = totpKey{name: "key-" + strconv.Itoa(i), secret: secret}
+
+		err = retrySetup(topLevelConfig, func() error {
+			_, err := client.Logical().Write(secretPath+"/keys/"+keys[i].name, map[string]interface{}{
+				"generate":  false,
+				"key":       base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secret),
+				"issuer":    "openbao-benchmark",
+				"account":   keys[i].name,
+				"period":    t.config.Period,
+				"digits":    t.config.Digits,
+				"algorithm": t.config.Algorithm,
+				"skew":      t.config.Skew,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error writing totp key: %v", err)
+		}
+	}
+
+	return &TOTPValidateTest{
+		pathPrefix: "/v1/" + secretPath,
+		header:     generateHeader(client),
+		keys:       keys,
+		config:     t.config,
+		rng:        topLevelConfig.Rand,
+		logger:     t.logger,
+		lastStep:   make([]int64, len(keys)),
+	}, nil
+}
+
+func (t *TOTPValidateTest) Flags(fs *flag.FlagSet) {}
+
+// totpHash returns the hash function for the passed in algorithm, or nil if
+// it isn't supported
+func totpHash(algorithm string) func() hash.Hash {
+	switch algorithm {
+	case "SHA1":
+		return sha1.New
+	case "SHA256":
+		return sha256.New
+	case "SHA512":
+		return sha512.New
+	}
+	return nil
+}
+
+func totpModulus(digits int) int {
+	m := 1
+	for i := 0; i < digits; i++ {
+		m *= 10
+	}
+	return m
+}
+
+// totpCode generates the code for the passed in time step as described in
+// RFC 6238
+func totpCode(algorithm string, secret []byte, step int64, digits int) int {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(totpHash(algorithm), secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0xf
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return int(value) % totpModulus(digits)
+}
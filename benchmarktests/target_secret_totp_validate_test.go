@@ -0,0 +1,34 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import "testing"
+
+func TestTOTPCode(t *testing.T) {
+	// Test vectors from RFC 6238 Appendix B at 59 seconds with a 30 second period
+	tests := []struct {
+		algorithm string
+		secret    string
+		expected  int
+	}{
+		{"SHA1", "12345678901234567890", 94287082},
+		{"SHA256", "12345678901234567890123456789012", 46119246},
+		{"SHA512", "1234567890123456789012345678901234567890123456789012345678901234", 90693936},
+	}
+	for _, tc := range tests {
+		if code := totpCode(tc.algorithm, []byte(tc.secret), 59/30, 8); code != tc.expected {
+			t.Fatalf("expected %v code %d, got: %d", tc.algorithm, tc.expected, code)
+		}
+	}
+}
+
+func TestTOTPSecretFromURL(t *testing.T) {
+	secret, err := totpSecretFromURL("otpauth://totp/openbao-benchmark:user-0?algorithm=SHA1&digits=6&issuer=openbao-benchmark&period=30&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if string(secret) != "12345678901234567890" {
+		t.Fatalf("expected secret 12345678901234567890, got: %q", secret)
+	}
+}
@@ -121,7 +121,7 @@ func (t *TransformTokenizationTest) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -169,8 +169,10 @@ func (t *TransformTokenizationTest) Setup(client *api.Client, mountName string,
 
 	// Create Transform mount
 	t.logger.Trace(mountLogMessage("secrets", "transform", secretPath))
-	err = client.Sys().Mount(secretPath, &api.MountInput{
-		Type: "transform",
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "transform",
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting transform secrets engine: %v", err)
@@ -9,9 +9,11 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"math/rand"
 	"net/http"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-uuid"
@@ -44,11 +46,24 @@ type TransitTest struct {
 	header     http.Header
 	config     *TransitTestConfig
 	logger     hclog.Logger
+
+	// bodyTemplate replaces body when set. requests counts the requests
+	// made so far and is used as the template's index.
+	bodyTemplate *bodyTemplate
+	requests     atomic.Int64
+
+	// encryptData is the encrypt request sent with a new random context for
+	// every request when convergent encryption is benchmarked
+	encryptData map[string]interface{}
+	contextLen  int
+	rng         *rand.Rand
 }
 
 type TransitTestConfig struct {
 	PayloadLen           int                   `hcl:"payload_len,optional"`
 	ContextLen           int                   `hcl:"context_len,optional"`
+	BodyTemplate         string                `hcl:"body_template,optional"`
+	Convergent           bool                  `hcl:"convergent,optional"`
 	TransitConfigKeys    *TransitConfigKeys    `hcl:"keys,block"`
 	TransitConfigSign    *TransitConfigSign    `hcl:"sign,block"`
 	TransitConfigVerify  *TransitConfigVerify  `hcl:"verify,block"`
@@ -162,24 +177,73 @@ func (t *TransitTest) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
 	t.config = testConfig.Config
 
+	// Convergent encryption derives the key from the context of each
+	// request, so it is only supported by derived keys
+	if t.config.Convergent {
+		if t.action != "encrypt" {
+			return fmt.Errorf("convergent is only supported by the %v test", TransitEncryptSecretTestType)
+		}
+		switch t.config.TransitConfigKeys.Type {
+		case "aes128-gcm96", "aes256-gcm96", "chacha20-poly1305":
+		default:
+			return fmt.Errorf("convergent requires a key type of aes128-gcm96, aes256-gcm96 or chacha20-poly1305")
+		}
+		if t.config.ContextLen < 1 {
+			return fmt.Errorf("context_len must be at least 1 with convergent")
+		}
+		t.config.TransitConfigKeys.ConvergentEncryption = true
+		t.config.TransitConfigKeys.Derived = true
+	}
 	return nil
 }
 
 func (t *TransitTest) Target(client *api.Client) vegeta.Target {
+	body := t.body
+	if t.bodyTemplate != nil {
+		var err error
+		body, err = t.bodyTemplate.render(int(t.requests.Add(1)))
+		if err != nil {
+			t.logger.Error("error executing body template", "error", err)
+		}
+	} else if t.encryptData != nil {
+		body = t.convergentBody()
+	}
+
 	return vegeta.Target{
 		Method: TransitSecretTestMethod,
 		URL:    client.Address() + t.pathPrefix,
-		Body:   t.body,
+		Body:   body,
 		Header: t.header,
 	}
 }
 
+// convergentBody returns the encrypt request with a new random context, so
+// that every request derives a different key
+func (t *TransitTest) convergentBody() []byte {
+	rawContext := make([]byte, t.contextLen)
+	for i := range rawContext {
+		rawContext[i] = byte(t.rng.Intn(256))
+	}
+
+	data := make(map[string]interface{}, len(t.encryptData)+1)
+	for k, v := range t.encryptData {
+		data[k] = v
+	}
+	data["context"] = base64.StdEncoding.EncodeToString(rawContext)
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		t.logger.Error("error marshaling transit encrypt data", "error", err)
+	}
+	return body
+}
+
 func (t *TransitTest) Cleanup(client *api.Client) error {
 	parts := strings.Split(t.pathPrefix, "/")
 	t.logger.Trace(cleanupLogMessage(parts[2]))
@@ -218,11 +282,13 @@ func (t *TransitTest) Setup(client *api.Client, mountName string, topLevelConfig
 	}
 
 	t.logger.Trace(mountLogMessage("secrets", "transit", secretPath))
-	err = client.Sys().Mount(secretPath, &api.MountInput{
-		Type: "transit",
-		Config: api.MountConfigInput{
-			MaxLeaseTTL: "87600h",
-		},
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "transit",
+			Config: api.MountConfigInput{
+				MaxLeaseTTL: "87600h",
+			},
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error mounting transit backend: %v", err)
@@ -256,6 +322,15 @@ func (t *TransitTest) Setup(client *api.Client, mountName string, topLevelConfig
 	}
 	base64Context := base64.StdEncoding.EncodeToString(rawContext)
 
+	var bodyTemplate *bodyTemplate
+	if t.config.BodyTemplate != "" {
+		setupLogger.Trace("parsing body template", "path", t.config.BodyTemplate)
+		bodyTemplate, err = newBodyTemplate(t.config.BodyTemplate, topLevelConfig.Rand)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Now dispatch the operation.
 	switch t.action {
 	case "sign":
@@ -272,10 +347,11 @@ func (t *TransitTest) Setup(client *api.Client, mountName string, topLevelConfig
 		}
 
 		return &TransitTest{
-			pathPrefix: "/v1/" + secretPath,
-			header:     generateHeader(client),
-			body:       []byte(signingDataString),
-			logger:     t.logger,
+			pathPrefix:   "/v1/" + secretPath,
+			header:       generateHeader(client),
+			body:         []byte(signingDataString),
+			logger:       t.logger,
+			bodyTemplate: bodyTemplate,
 		}, nil
 
 	case "verify":
@@ -310,10 +386,11 @@ func (t *TransitTest) Setup(client *api.Client, mountName string, topLevelConfig
 		}
 
 		return &TransitTest{
-			pathPrefix: "/v1/" + verifyPath,
-			header:     generateHeader(client),
-			body:       []byte(verifyDataString),
-			logger:     t.logger,
+			pathPrefix:   "/v1/" + verifyPath,
+			header:       generateHeader(client),
+			body:         []byte(verifyDataString),
+			logger:       t.logger,
+			bodyTemplate: bodyTemplate,
 		}, nil
 
 	case "encrypt":
@@ -334,12 +411,19 @@ func (t *TransitTest) Setup(client *api.Client, mountName string, topLevelConfig
 		}
 
 		encryptPath := filepath.Join(secretPath, "encrypt", t.config.TransitConfigEncrypt.Name)
-		return &TransitTest{
-			pathPrefix: "/v1/" + encryptPath,
-			header:     generateHeader(client),
-			body:       []byte(encryptDataString),
-			logger:     t.logger,
-		}, nil
+		test := &TransitTest{
+			pathPrefix:   "/v1/" + encryptPath,
+			header:       generateHeader(client),
+			body:         []byte(encryptDataString),
+			logger:       t.logger,
+			bodyTemplate: bodyTemplate,
+		}
+		if t.config.Convergent {
+			test.encryptData = encryptData
+			test.contextLen = t.config.ContextLen
+			test.rng = topLevelConfig.Rand
+		}
+		return test, nil
 
 	case "decrypt":
 		// Encrypt test payload
@@ -380,10 +464,11 @@ func (t *TransitTest) Setup(client *api.Client, mountName string, topLevelConfig
 
 		// Now decrypt it
 		return &TransitTest{
-			pathPrefix: "/v1/" + decryptPath,
-			header:     generateHeader(client),
-			body:       []byte(decryptDataString),
-			logger:     t.logger,
+			pathPrefix:   "/v1/" + decryptPath,
+			header:       generateHeader(client),
+			body:         []byte(decryptDataString),
+			logger:       t.logger,
+			bodyTemplate: bodyTemplate,
 		}, nil
 
 	default:
@@ -0,0 +1,195 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	TransitBackupTestType    = "transit_backup"
+	TransitRestoreTestType   = "transit_restore"
+	TransitBackupTestMethod  = "GET"
+	TransitRestoreTestMethod = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[TransitBackupTestType] = func() BenchmarkBuilder { return &TransitBackupTest{action: "backup"} }
+	TestList[TransitRestoreTestType] = func() BenchmarkBuilder { return &TransitBackupTest{action: "restore"} }
+}
+
+// TransitBackupTest backs up transit keys, or restores backups of them. Both
+// move a key's full material, including every version of it.
+type TransitBackupTest struct {
+	action     string
+	pathPrefix string
+	mountPath  string
+	header     http.Header
+	config     *TransitBackupTestConfig
+	rng        *rand.Rand
+	logger     hclog.Logger
+
+	// backups are the backups taken during setup for restores. Restoring
+	// to an existing key fails, so every restore creates a new key named
+	// after the number of restores made so far, tracked by restores.
+	backups  []string
+	restores atomic.Int64
+}
+
+type TransitBackupTestConfig struct {
+	NumKeys int    `hcl:"num_keys,optional"`
+	KeyType string `hcl:"key_type,optional"`
+}
+
+func (t *TransitBackupTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *TransitBackupTestConfig `hcl:"config,block"`
+	}{
+		Config: &TransitBackupTestConfig{
+			NumKeys: 1,
+			KeyType: "aes256-gcm96",
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	t.config = testConfig.Config
+
+	if t.config.NumKeys < 1 {
+		return fmt.Errorf("num_keys must be at least 1")
+	}
+	return nil
+}
+
+func (t *TransitBackupTest) Target(client *api.Client) vegeta.Target {
+	if t.action == "backup" {
+		return vegeta.Target{
+			Method: TransitBackupTestMethod,
+			URL:    client.Address() + t.pathPrefix + "/key-" + strconv.Itoa(t.rng.Intn(t.config.NumKeys)),
+			Header: t.header,
+		}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"backup": t.backups[t.rng.Intn(len(t.backups))],
+	})
+	if err != nil {
+		t.logger.Error("error marshaling transit restore request", "error", err)
+	}
+
+	return vegeta.Target{
+		Method: TransitRestoreTestMethod,
+		URL:    client.Address() + t.pathPrefix + "/restored-" + strconv.FormatInt(t.restores.Add(1), 10),
+		Body:   body,
+		Header: t.header,
+	}
+}
+
+func (t *TransitBackupTest) Cleanup(client *api.Client) error {
+	// Unmounting also removes the keys created by restores
+	t.logger.Trace(cleanupLogMessage(t.mountPath))
+	_, err := client.Logical().Delete("/sys/mounts/" + t.mountPath)
+	if err != nil {
+		return fmt.Errorf("error cleaning up mount: %v", err)
+	}
+	return nil
+}
+
+func (t *TransitBackupTest) GetTargetInfo() TargetInfo {
+	method := TransitBackupTestMethod
+	if t.action == "restore" {
+		method = TransitRestoreTestMethod
+	}
+	return TargetInfo{
+		method:     method,
+		pathPrefix: t.pathPrefix,
+	}
+}
+
+func (t *TransitBackupTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	secretPath := mountName
+	t.logger = targetLogger.Named("transit_" + t.action)
+
+	if topLevelConfig.RandomMounts {
+		secretPath, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	t.logger.Trace(mountLogMessage("secrets", "transit", secretPath))
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "transit",
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error mounting transit backend: %v", err)
+	}
+
+	// Backups can only be taken of keys which are exportable and allow
+	// plaintext backups
+	setupLogger := t.logger.Named(secretPath)
+	setupLogger.Trace(writingLogMessage("backup keys"), "count", t.config.NumKeys, "type", t.config.KeyType)
+	for i := 0; i < t.config.NumKeys; i++ {
+		err = retrySetup(topLevelConfig, func() error {
+			_, err := client.Logical().Write(secretPath+"/keys/key-"+strconv.Itoa(i), map[string]interface{}{
+				"type":                   t.config.KeyType,
+				"exportable":             true,
+				"allow_plaintext_backup": true,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error writing transit key: %v", err)
+		}
+	}
+
+	test := &TransitBackupTest{
+		action:     t.action,
+		pathPrefix: "/v1/" + secretPath + "/" + t.action,
+		mountPath:  secretPath,
+		header:     generateHeader(client),
+		config:     t.config,
+		rng:        topLevelConfig.Rand,
+		logger:     t.logger,
+	}
+	if t.action == "backup" {
+		return test, nil
+	}
+
+	setupLogger.Trace("backing up keys", "count", t.config.NumKeys)
+	test.backups = make([]string, 0, t.config.NumKeys)
+	for i := 0; i < t.config.NumKeys; i++ {
+		secret, err := client.Logical().Read(secretPath + "/backup/key-" + strconv.Itoa(i))
+		if err != nil {
+			return nil, fmt.Errorf("error backing up transit key: %v", err)
+		}
+		if secret == nil || secret.Data["backup"] == nil {
+			return nil, fmt.Errorf("no backup returned for key-%d", i)
+		}
+		test.backups = append(test.backups, fmt.Sprint(secret.Data["backup"]))
+	}
+	return test, nil
+}
+
+func (t *TransitBackupTest) Flags(fs *flag.FlagSet) {}
@@ -0,0 +1,176 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	TransitCMACTestType   = "transit_cmac"
+	TransitCMACTestMethod = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[TransitCMACTestType] = func() BenchmarkBuilder { return &TransitCMACTest{} }
+}
+
+// TransitCMACTest generates CMACs of a payload with an AES CMAC key created
+// during setup
+type TransitCMACTest struct {
+	pathPrefix string
+	body       []byte
+	header     http.Header
+	config     *TransitCMACTestConfig
+	logger     hclog.Logger
+}
+
+type TransitCMACTestConfig struct {
+	PayloadLen int    `hcl:"payload_len,optional"`
+	KeyType    string `hcl:"key_type,optional"`
+	MACLength  int    `hcl:"mac_length,optional"`
+}
+
+func (t *TransitCMACTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *TransitCMACTestConfig `hcl:"config,block"`
+	}{
+		Config: &TransitCMACTestConfig{
+			PayloadLen: 128,
+			KeyType:    "aes256-cmac",
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	t.config = testConfig.Config
+
+	if t.config.PayloadLen < 1 {
+		return fmt.Errorf("payload_len must be at least 1")
+	}
+	switch t.config.KeyType {
+	case "aes128-cmac", "aes192-cmac", "aes256-cmac":
+	default:
+		return fmt.Errorf("key_type must be one of aes128-cmac, aes192-cmac or aes256-cmac")
+	}
+	// CMACs are at most the AES block size of 16 bytes, and 0 leaves them
+	// untruncated
+	if t.config.MACLength < 0 || t.config.MACLength > 16 {
+		return fmt.Errorf("mac_length must be between 0 and 16")
+	}
+	return nil
+}
+
+func (t *TransitCMACTest) Target(client *api.Client) vegeta.Target {
+	return vegeta.Target{
+		Method: TransitCMACTestMethod,
+		URL:    client.Address() + t.pathPrefix,
+		Body:   t.body,
+		Header: t.header,
+	}
+}
+
+func (t *TransitCMACTest) Cleanup(client *api.Client) error {
+	t.logger.Trace(cleanupLogMessage(t.pathPrefix))
+	mountPath, _, _ := strings.Cut(strings.TrimPrefix(t.pathPrefix, "/v1/"), "/cmac/")
+	_, err := client.Logical().Delete("/sys/mounts/" + mountPath)
+	if err != nil {
+		return fmt.Errorf("error cleaning up mount: %v", err)
+	}
+	return nil
+}
+
+func (t *TransitCMACTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     TransitCMACTestMethod,
+		pathPrefix: t.pathPrefix,
+	}
+}
+
+func (t *TransitCMACTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	secretPath := mountName
+	t.logger = targetLogger.Named(TransitCMACTestType)
+
+	if topLevelConfig.RandomMounts {
+		secretPath, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	t.logger.Trace(mountLogMessage("secrets", "transit", secretPath))
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "transit",
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error mounting transit backend: %v", err)
+	}
+
+	setupLogger := t.logger.Named(secretPath)
+	keyName := "cmac-" + t.config.KeyType
+	test := &TransitCMACTest{
+		pathPrefix: "/v1/" + secretPath + "/cmac/" + keyName,
+		header:     generateHeader(client),
+		config:     t.config,
+		logger:     t.logger,
+	}
+	setupLogger.Trace("writing transit key", "name", keyName, "type", t.config.KeyType)
+	_, err = client.Logical().Write(secretPath+"/keys/"+keyName, map[string]interface{}{
+		"type": t.config.KeyType,
+	})
+	if err != nil {
+		// Servers without CMAC support don't know the key types, in which
+		// case the mount is returned to be cleaned up
+		if strings.Contains(err.Error(), "unknown key type") {
+			return test, fmt.Errorf("error writing transit key: %w: %v", errUnsupported, err)
+		}
+		return nil, fmt.Errorf("error writing transit key: %v", err)
+	}
+
+	data := map[string]interface{}{
+		"input": base64.StdEncoding.EncodeToString([]byte(strings.Repeat("a", t.config.PayloadLen))),
+	}
+	if t.config.MACLength > 0 {
+		data["mac_length"] = t.config.MACLength
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling transit cmac data: %v", err)
+	}
+
+	// The key types may be known without the endpoint being present, which
+	// a single request finds out before the attack
+	setupLogger.Trace("checking cmac endpoint")
+	_, err = client.Logical().Write(secretPath+"/cmac/"+keyName, data)
+	if err := probeError("the cmac endpoint", err); err != nil {
+		if isUnsupported(err) {
+			return test, err
+		}
+		return nil, err
+	}
+
+	test.body = body
+	return test, nil
+}
+
+func (t *TransitCMACTest) Flags(fs *flag.FlagSet) {}
@@ -0,0 +1,32 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+)
+
+func TestTransitCMACTest_ParseConfig(t *testing.T) {
+	for fixture, valid := range map[string]bool{
+		"transit_cmac.hcl":                     true,
+		"invalid_transit_cmac_key_type.hcl":    false,
+		"invalid_transit_cmac_mac_length.hcl":  false,
+		"invalid_transit_cmac_payload_len.hcl": false,
+	} {
+		hclFile, diags := hclparse.NewParser().ParseHCLFile(filepath.Join(FixturePath, fixture))
+		if diags != nil {
+			t.Fatalf("err: %v", diags)
+		}
+		err := (&TransitCMACTest{}).ParseConfig(hclFile.Body)
+		if valid && err != nil {
+			t.Fatalf("unexpected error for %v: %v", fixture, err)
+		}
+		if !valid && err == nil {
+			t.Fatalf("expected error for %v", fixture)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	TransitExportTestType   = "transit_export"
+	TransitExportTestMethod = "GET"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[TransitExportTestType] = func() BenchmarkBuilder { return &TransitExportTest{} }
+}
+
+// TransitExportTest exports exportable transit keys, which decrypts their key
+// material from storage for every request
+type TransitExportTest struct {
+	pathPrefix string
+	mountPath  string
+	header     http.Header
+	config     *TransitExportTestConfig
+	rng        *rand.Rand
+	logger     hclog.Logger
+}
+
+type TransitExportTestConfig struct {
+	NumKeys              int    `hcl:"num_keys,optional"`
+	KeyType              string `hcl:"key_type,optional"`
+	ExportType           string `hcl:"export_type,optional"`
+	AllowPlaintextBackup bool   `hcl:"allow_plaintext_backup,optional"`
+}
+
+func (t *TransitExportTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *TransitExportTestConfig `hcl:"config,block"`
+	}{
+		Config: &TransitExportTestConfig{
+			NumKeys:    1,
+			KeyType:    "aes256-gcm96",
+			ExportType: "encryption-key",
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	t.config = testConfig.Config
+
+	if t.config.NumKeys < 1 {
+		return fmt.Errorf("num_keys must be at least 1")
+	}
+	switch t.config.ExportType {
+	case "encryption-key", "signing-key", "hmac-key":
+	default:
+		return fmt.Errorf("export_type must be one of encryption-key, signing-key or hmac-key")
+	}
+	return nil
+}
+
+func (t *TransitExportTest) Target(client *api.Client) vegeta.Target {
+	return vegeta.Target{
+		Method: TransitExportTestMethod,
+		URL:    client.Address() + t.pathPrefix + "/key-" + strconv.Itoa(t.rng.Intn(t.config.NumKeys)),
+		Header: t.header,
+	}
+}
+
+func (t *TransitExportTest) Cleanup(client *api.Client) error {
+	t.logger.Trace(cleanupLogMessage(t.mountPath))
+	_, err := client.Logical().Delete("/sys/mounts/" + t.mountPath)
+	if err != nil {
+		return fmt.Errorf("error cleaning up mount: %v", err)
+	}
+	return nil
+}
+
+func (t *TransitExportTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     TransitExportTestMethod,
+		pathPrefix: t.pathPrefix,
+	}
+}
+
+func (t *TransitExportTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	secretPath := mountName
+	t.logger = targetLogger.Named(TransitExportTestType)
+
+	if topLevelConfig.RandomMounts {
+		secretPath, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	t.logger.Trace(mountLogMessage("secrets", "transit", secretPath))
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "transit",
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error mounting transit backend: %v", err)
+	}
+
+	setupLogger := t.logger.Named(secretPath)
+	setupLogger.Trace(writingLogMessage("exportable keys"), "count", t.config.NumKeys, "type", t.config.KeyType)
+	for i := 0; i < t.config.NumKeys; i++ {
+		err = retrySetup(topLevelConfig, func() error {
+			_, err := client.Logical().Write(secretPath+"/keys/key-"+strconv.Itoa(i), map[string]interface{}{
+				"type":                   t.config.KeyType,
+				"exportable":             true,
+				"allow_plaintext_backup": t.config.AllowPlaintextBackup,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error writing transit key: %v", err)
+		}
+	}
+
+	// Not every key type can be exported as every export type, so export
+	// one key up front rather than failing every request of the test
+	exportPath := secretPath + "/export/" + t.config.ExportType
+	setupLogger.Trace("checking key export", "export_type", t.config.ExportType)
+	_, err = client.Logical().Read(exportPath + "/key-0")
+	if err != nil {
+		return nil, fmt.Errorf("error exporting %v key as %v: %v", t.config.KeyType, t.config.ExportType, err)
+	}
+
+	return &TransitExportTest{
+		pathPrefix: "/v1/" + exportPath,
+		mountPath:  secretPath,
+		header:     generateHeader(client),
+		config:     t.config,
+		rng:        topLevelConfig.Rand,
+		logger:     t.logger,
+	}, nil
+}
+
+func (t *TransitExportTest) Flags(fs *flag.FlagSet) {}
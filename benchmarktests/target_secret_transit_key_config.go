@@ -0,0 +1,259 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	TransitKeyConfigTestType   = "transit_key_config"
+	TransitKeyTrimTestType     = "transit_key_trim"
+	TransitKeyConfigTestMethod = "POST"
+	TransitKeyTrimTestMethod   = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[TransitKeyConfigTestType] = func() BenchmarkBuilder { return &TransitKeyConfigTest{action: "config"} }
+	TestList[TransitKeyTrimTestType] = func() BenchmarkBuilder { return &TransitKeyConfigTest{action: "trim"} }
+}
+
+// TransitKeyConfigTest updates the minimum decryption and encryption versions
+// of transit keys, or trims their old versions. Both are key lifecycle
+// maintenance operations which rewrite the key's policy.
+type TransitKeyConfigTest struct {
+	action     string
+	pathPrefix string
+	mountPath  string
+	header     http.Header
+	config     *TransitKeyConfigTestConfig
+	rng        *rand.Rand
+	logger     hclog.Logger
+
+	// trims counts the trims made so far. Versions can't be restored once
+	// they have been trimmed, so each trim removes the next version of the
+	// next key until every key has been trimmed to its latest version. A key
+	// rejects being trimmed to an older version than it already was, so the
+	// keys are trimmed in turn, and consecutive trims of the same key are
+	// num_keys requests apart so that they aren't in flight at once.
+	trims       atomic.Int64
+	exhaustOnce sync.Once
+}
+
+type TransitKeyConfigTestConfig struct {
+	NumKeys   int    `hcl:"num_keys,optional"`
+	KeyType   string `hcl:"key_type,optional"`
+	Rotations int    `hcl:"rotations,optional"`
+}
+
+func (t *TransitKeyConfigTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *TransitKeyConfigTestConfig `hcl:"config,block"`
+	}{
+		Config: &TransitKeyConfigTestConfig{
+			NumKeys:   1,
+			KeyType:   "aes256-gcm96",
+			Rotations: 100,
+		},
+	}
+	// Trims of the same key mustn't be in flight at once, so there need to
+	// be more keys than requests in flight
+	if t.action == "trim" {
+		testConfig.Config.NumKeys = 100
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	t.config = testConfig.Config
+
+	switch {
+	case t.config.NumKeys < 1:
+		return fmt.Errorf("num_keys must be at least 1")
+	case t.config.Rotations < 1:
+		return fmt.Errorf("rotations must be at least 1")
+	}
+	return nil
+}
+
+// latestVersion is the version of each key once it has been rotated
+func (t *TransitKeyConfigTest) latestVersion() int {
+	return t.config.Rotations + 1
+}
+
+func (t *TransitKeyConfigTest) Target(client *api.Client) vegeta.Target {
+	if t.action == "trim" {
+		return t.trim(client)
+	}
+
+	// The minimum encryption version can't be below the minimum decryption
+	// version
+	latest := t.latestVersion()
+	minDecryption := 1 + t.rng.Intn(latest)
+	minEncryption := minDecryption + t.rng.Intn(latest-minDecryption+1)
+	body, err := json.Marshal(map[string]interface{}{
+		"min_decryption_version": minDecryption,
+		"min_encryption_version": minEncryption,
+	})
+	if err != nil {
+		t.logger.Error("error marshaling transit key config request", "error", err)
+	}
+
+	return vegeta.Target{
+		Method: TransitKeyConfigTestMethod,
+		URL:    client.Address() + t.pathPrefix + "/key-" + strconv.Itoa(t.rng.Intn(t.config.NumKeys)) + "/config",
+		Body:   body,
+		Header: t.header,
+	}
+}
+
+func (t *TransitKeyConfigTest) trim(client *api.Client) vegeta.Target {
+	// Trimming to the version a key was already trimmed to succeeds without
+	// removing anything, so the keys are trimmed again once exhausted
+	n := t.trims.Add(1) - 1
+	key := n % int64(t.config.NumKeys)
+	version := 2 + n/int64(t.config.NumKeys)
+	if latest := int64(t.latestVersion()); version > latest {
+		t.exhaustOnce.Do(func() {
+			t.logger.Warn("every key has been trimmed to its latest version, increase num_keys or rotations to trim versions for the whole test")
+		})
+		version = latest
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"min_available_version": version,
+	})
+	if err != nil {
+		t.logger.Error("error marshaling transit key trim request", "error", err)
+	}
+
+	return vegeta.Target{
+		Method: TransitKeyTrimTestMethod,
+		URL:    client.Address() + t.pathPrefix + "/key-" + strconv.FormatInt(key, 10) + "/trim",
+		Body:   body,
+		Header: t.header,
+	}
+}
+
+func (t *TransitKeyConfigTest) Cleanup(client *api.Client) error {
+	t.logger.Trace(cleanupLogMessage(t.mountPath))
+	_, err := client.Logical().Delete("/sys/mounts/" + t.mountPath)
+	if err != nil {
+		return fmt.Errorf("error cleaning up mount: %v", err)
+	}
+	return nil
+}
+
+func (t *TransitKeyConfigTest) GetTargetInfo() TargetInfo {
+	method := TransitKeyConfigTestMethod
+	if t.action == "trim" {
+		method = TransitKeyTrimTestMethod
+	}
+	return TargetInfo{
+		method:     method,
+		pathPrefix: t.pathPrefix,
+	}
+}
+
+func (t *TransitKeyConfigTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	secretPath := mountName
+	t.logger = targetLogger.Named("transit_key_" + t.action)
+
+	if topLevelConfig.RandomMounts {
+		secretPath, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	t.logger.Trace(mountLogMessage("secrets", "transit", secretPath))
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "transit",
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error mounting transit backend: %v", err)
+	}
+
+	// Every key is rotated so that there are versions to require or trim
+	setupLogger := t.logger.Named(secretPath)
+	if t.action == "trim" && t.config.NumKeys <= topLevelConfig.Workers {
+		setupLogger.Warn("num_keys should be larger than the number of workers, or trims of the same key may be in flight at once and fail", "num_keys", t.config.NumKeys, "workers", topLevelConfig.Workers)
+	}
+	setupLogger.Trace(writingLogMessage("rotated keys"), "count", t.config.NumKeys, "type", t.config.KeyType, "rotations", t.config.Rotations)
+	for i := 0; i < t.config.NumKeys; i++ {
+		keyPath := secretPath + "/keys/key-" + strconv.Itoa(i)
+		err = retrySetup(topLevelConfig, func() error {
+			_, err := client.Logical().Write(keyPath, map[string]interface{}{
+				"type": t.config.KeyType,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error writing transit key: %v", err)
+		}
+
+		for r := 0; r < t.config.Rotations; r++ {
+			err = retrySetup(topLevelConfig, func() error {
+				_, err := client.Logical().Write(keyPath+"/rotate", nil)
+				return err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error rotating transit key: %v", err)
+			}
+		}
+
+		// Versions can only be trimmed up to the minimum decryption and
+		// encryption versions
+		if t.action == "trim" {
+			err = retrySetup(topLevelConfig, func() error {
+				_, err := client.Logical().Write(keyPath+"/config", map[string]interface{}{
+					"min_decryption_version": t.latestVersion(),
+					"min_encryption_version": t.latestVersion(),
+				})
+				return err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error configuring transit key: %v", err)
+			}
+		}
+	}
+
+	return &TransitKeyConfigTest{
+		action:     t.action,
+		pathPrefix: "/v1/" + secretPath + "/keys",
+		mountPath:  secretPath,
+		header:     generateHeader(client),
+		config:     t.config,
+		rng:        topLevelConfig.Rand,
+		logger:     t.logger,
+	}, nil
+}
+
+// ConsumesSetup reports that trims use up the key versions created during
+// setup
+func (t *TransitKeyConfigTest) ConsumesSetup() bool {
+	return t.action == "trim"
+}
+
+func (t *TransitKeyConfigTest) Flags(fs *flag.FlagSet) {}
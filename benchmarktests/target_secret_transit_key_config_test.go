@@ -0,0 +1,42 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/openbao/openbao/api/v2"
+)
+
+func TestTransitKeyConfigTest_Trim(t *testing.T) {
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Each key loses its oldest version in turn, until both keys are left
+	// with only their latest version
+	test := &TransitKeyConfigTest{
+		action:     "trim",
+		pathPrefix: "/v1/transit/keys",
+		config:     &TransitKeyConfigTestConfig{NumKeys: 2, Rotations: 2},
+		logger:     hclog.NewNullLogger(),
+	}
+	for _, expected := range []struct {
+		url  string
+		body string
+	}{
+		{"/v1/transit/keys/key-0/trim", `{"min_available_version":2}`},
+		{"/v1/transit/keys/key-1/trim", `{"min_available_version":2}`},
+		{"/v1/transit/keys/key-0/trim", `{"min_available_version":3}`},
+		{"/v1/transit/keys/key-1/trim", `{"min_available_version":3}`},
+		{"/v1/transit/keys/key-0/trim", `{"min_available_version":3}`},
+	} {
+		target := test.Target(client)
+		if target.URL != client.Address()+expected.url || string(target.Body) != expected.body {
+			t.Fatalf("expected %v with %v, got %v with %s", expected.url, expected.body, target.URL, target.Body)
+		}
+	}
+}
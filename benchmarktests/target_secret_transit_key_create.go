@@ -0,0 +1,148 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	TransitKeyCreateTestType   = "transit_key_create"
+	TransitKeyCreateTestMethod = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[TransitKeyCreateTestType] = func() BenchmarkBuilder { return &TransitKeyCreateTest{} }
+}
+
+// transitKeyTypes are the key types transit keys can be created with
+var transitKeyTypes = []string{
+	"aes128-gcm96", "aes256-gcm96", "chacha20-poly1305", "ed25519",
+	"ecdsa-p256", "ecdsa-p384", "ecdsa-p521",
+	"rsa-2048", "rsa-3072", "rsa-4096", "hmac",
+}
+
+// TransitKeyCreateTest creates a new transit key with every request
+type TransitKeyCreateTest struct {
+	pathPrefix string
+	mountPath  string
+	body       []byte
+	header     http.Header
+	config     *TransitKeyCreateTestConfig
+	logger     hclog.Logger
+
+	// Creating a key which already exists only updates it, so every request
+	// creates a key named after the number of keys created so far, tracked
+	// by created
+	created atomic.Int64
+}
+
+type TransitKeyCreateTestConfig struct {
+	KeyType string `hcl:"key_type,optional"`
+}
+
+func (t *TransitKeyCreateTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *TransitKeyCreateTestConfig `hcl:"config,block"`
+	}{
+		Config: &TransitKeyCreateTestConfig{
+			KeyType: "aes256-gcm96",
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	t.config = testConfig.Config
+
+	for _, keyType := range transitKeyTypes {
+		if t.config.KeyType == keyType {
+			return nil
+		}
+	}
+	return fmt.Errorf("key_type must be one of %v", strings.Join(transitKeyTypes, ", "))
+}
+
+func (t *TransitKeyCreateTest) Target(client *api.Client) vegeta.Target {
+	return vegeta.Target{
+		Method: TransitKeyCreateTestMethod,
+		URL:    client.Address() + t.pathPrefix + "/key-" + strconv.FormatInt(t.created.Add(1), 10),
+		Body:   t.body,
+		Header: t.header,
+	}
+}
+
+func (t *TransitKeyCreateTest) Cleanup(client *api.Client) error {
+	// Unmounting also removes the keys created
+	t.logger.Trace(cleanupLogMessage(t.mountPath), "keys", t.created.Load())
+	_, err := client.Logical().Delete("/sys/mounts/" + t.mountPath)
+	if err != nil {
+		return fmt.Errorf("error cleaning up mount: %v", err)
+	}
+	return nil
+}
+
+func (t *TransitKeyCreateTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     TransitKeyCreateTestMethod,
+		pathPrefix: t.pathPrefix,
+	}
+}
+
+func (t *TransitKeyCreateTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	secretPath := mountName
+	t.logger = targetLogger.Named(TransitKeyCreateTestType)
+
+	if topLevelConfig.RandomMounts {
+		secretPath, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	t.logger.Trace(mountLogMessage("secrets", "transit", secretPath))
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "transit",
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error mounting transit backend: %v", err)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"type": t.config.KeyType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling transit key data: %v", err)
+	}
+
+	return &TransitKeyCreateTest{
+		pathPrefix: "/v1/" + secretPath + "/keys",
+		mountPath:  secretPath,
+		body:       body,
+		header:     generateHeader(client),
+		config:     t.config,
+		logger:     t.logger,
+	}, nil
+}
+
+func (t *TransitKeyCreateTest) Flags(fs *flag.FlagSet) {}
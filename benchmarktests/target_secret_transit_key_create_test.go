@@ -0,0 +1,38 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/openbao/openbao/api/v2"
+)
+
+func TestTransitKeyCreateTest_Target(t *testing.T) {
+	hclFile, diags := hclparse.NewParser().ParseHCL([]byte(`
+config {
+  key_type = "ed448"
+}
+`), "transit.hcl")
+	if diags.HasErrors() {
+		t.Fatalf("err: %v", diags)
+	}
+	if err := (&TransitKeyCreateTest{}).ParseConfig(hclFile.Body); err == nil {
+		t.Fatal("expected error using an unknown key type")
+	}
+
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Every request creates a key of its own
+	tt := &TransitKeyCreateTest{pathPrefix: "/v1/transit/keys"}
+	for _, key := range []string{"key-1", "key-2", "key-3"} {
+		if url := tt.Target(client).URL; url != client.Address()+"/v1/transit/keys/"+key {
+			t.Fatalf("expected a request creating %v, got %v", key, url)
+		}
+	}
+}
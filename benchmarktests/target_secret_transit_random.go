@@ -0,0 +1,137 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	TransitRandomTestType   = "transit_random"
+	TransitRandomTestMethod = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[TransitRandomTestType] = func() BenchmarkBuilder { return &TransitRandomTest{} }
+}
+
+type TransitRandomTest struct {
+	pathPrefix string
+	body       []byte
+	header     http.Header
+	config     *TransitRandomTestConfig
+	logger     hclog.Logger
+}
+
+type TransitRandomTestConfig struct {
+	NumBytes int    `hcl:"num_bytes,optional"`
+	Format   string `hcl:"format,optional"`
+}
+
+func (t *TransitRandomTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *TransitRandomTestConfig `hcl:"config,block"`
+	}{
+		Config: &TransitRandomTestConfig{
+			NumBytes: 32,
+			Format:   "base64",
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	t.config = testConfig.Config
+
+	if t.config.NumBytes < 1 {
+		return fmt.Errorf("num_bytes must be at least 1")
+	}
+	switch t.config.Format {
+	case "base64", "hex":
+	default:
+		return fmt.Errorf("format must be one of base64 or hex")
+	}
+	return nil
+}
+
+func (t *TransitRandomTest) Target(client *api.Client) vegeta.Target {
+	return vegeta.Target{
+		Method: TransitRandomTestMethod,
+		URL:    client.Address() + t.pathPrefix,
+		Body:   t.body,
+		Header: t.header,
+	}
+}
+
+func (t *TransitRandomTest) Cleanup(client *api.Client) error {
+	t.logger.Trace(cleanupLogMessage(t.pathPrefix))
+	mountPath := strings.TrimSuffix(t.pathPrefix, "/random/"+strconv.Itoa(t.config.NumBytes))
+	_, err := client.Logical().Delete(strings.Replace(mountPath, "/v1/", "/sys/mounts/", 1))
+	if err != nil {
+		return fmt.Errorf("error cleaning up mount: %v", err)
+	}
+	return nil
+}
+
+func (t *TransitRandomTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     TransitRandomTestMethod,
+		pathPrefix: t.pathPrefix,
+	}
+}
+
+func (t *TransitRandomTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	secretPath := mountName
+	t.logger = targetLogger.Named(TransitRandomTestType)
+
+	if topLevelConfig.RandomMounts {
+		secretPath, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	t.logger.Trace(mountLogMessage("secrets", "transit", secretPath))
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(secretPath, &api.MountInput{
+			Type: "transit",
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error mounting transit backend: %v", err)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"format": t.config.Format,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling transit random data: %v", err)
+	}
+
+	return &TransitRandomTest{
+		pathPrefix: "/v1/" + secretPath + "/random/" + strconv.Itoa(t.config.NumBytes),
+		header:     generateHeader(client),
+		body:       body,
+		config:     t.config,
+		logger:     t.logger,
+	}, nil
+}
+
+func (t *TransitRandomTest) Flags(fs *flag.FlagSet) {}
@@ -0,0 +1,68 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/openbao/openbao/api/v2"
+)
+
+func TestTransitTest_Convergent(t *testing.T) {
+	hclFile, diags := hclparse.NewParser().ParseHCL([]byte(`
+config {
+  convergent = true
+  keys {
+    type = "aes256-gcm96"
+  }
+}
+`), "transit.hcl")
+	if diags.HasErrors() {
+		t.Fatalf("err: %v", diags)
+	}
+
+	encrypt := &TransitTest{action: "encrypt"}
+	if err := encrypt.ParseConfig(hclFile.Body); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if keys := encrypt.config.TransitConfigKeys; !keys.ConvergentEncryption || !keys.Derived {
+		t.Fatalf("expected a convergent derived key, got: %+v", keys)
+	}
+
+	decrypt := &TransitTest{action: "decrypt"}
+	if err := decrypt.ParseConfig(hclFile.Body); err == nil {
+		t.Fatal("expected error using convergent with transit_decrypt")
+	}
+
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Every request carries its own context
+	test := &TransitTest{
+		pathPrefix:  "/v1/transit/encrypt/test",
+		encryptData: map[string]interface{}{"plaintext": "Zm9v"},
+		contextLen:  32,
+		rng:         NewRand(1),
+		logger:      hclog.NewNullLogger(),
+	}
+	contexts := make(map[string]bool)
+	for i := 0; i < 10; i++ {
+		var body map[string]interface{}
+		if err := json.Unmarshal(test.Target(client).Body, &body); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if body["plaintext"] != "Zm9v" {
+			t.Fatalf("expected the plaintext to be kept, got: %v", body)
+		}
+		contexts[body["context"].(string)] = true
+	}
+	if len(contexts) != 10 {
+		t.Fatalf("expected 10 different contexts, got: %d", len(contexts))
+	}
+}
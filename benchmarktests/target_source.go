@@ -0,0 +1,31 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+// TargetSource generates the requests of an attack directly, rather than
+// from tests set up by BuildTargets, such as requests replayed from captured
+// traffic
+type TargetSource interface {
+	// Targets returns the targets the source's requests are reported under.
+	// Results are matched to them by method and path prefix as they are for
+	// tests, so every request must match one of them.
+	Targets() []BenchmarkTarget
+
+	// Next fills in the next request to send to the client
+	Next(client *api.Client, tgt *vegeta.Target) error
+}
+
+// NewSourceTargets returns targets which send the requests generated by
+// source. Nothing is set up for them, so there is nothing to clean up.
+func NewSourceTargets(source TargetSource) *TargetMulti {
+	return &TargetMulti{
+		targets: source.Targets(),
+		source:  source,
+	}
+}
@@ -54,6 +54,7 @@ type SyncAWSTest struct {
 
 	config *SyncAWSTestConfig
 
+	rng    *rand.Rand
 	logger hclog.Logger
 }
 
@@ -76,7 +77,7 @@ func (t *SyncAWSTest) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, cfg)
+	diags := gohcl.DecodeBody(body, EvalContext(), cfg)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
@@ -97,11 +98,13 @@ func (t *SyncAWSTest) Setup(client *api.Client, mountName string, topLevelConfig
 	}
 	
 	t.logger.Debug(mountLogMessage("secrets", "kvv2", mountName))
-	err := client.Sys().Mount(mountName, &api.MountInput{
-		Type: "kv",
-		Options: map[string]string{
-			"version": "2",
-		},
+	err := retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(mountName, &api.MountInput{
+			Type: "kv",
+			Options: map[string]string{
+				"version": "2",
+			},
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error setupping KVv2 engine: %v", err)
@@ -154,6 +157,7 @@ func (t *SyncAWSTest) Setup(client *api.Client, mountName string, topLevelConfig
 		target: t.target,
 		config: t.config,
 		mount:  mountName,
+		rng:    topLevelConfig.Rand,
 		logger: t.logger,
 	}, nil
 }
@@ -236,7 +240,7 @@ func (t *SyncAWSTest) events(client *api.Client) vegeta.Target {
 			client.Address(),
 			t.mount,
 			fmt.Sprintf(secretNameFormat,
-				int(rand.Int31n(int32(t.config.NumAssociations))),
+				int(t.rng.Int31n(int32(t.config.NumAssociations))),
 			),
 		),
 		Header: http.Header{
@@ -266,7 +270,7 @@ func (t *SyncAWSTest) write(client *api.Client) vegeta.Target {
 			fmt.Sprintf(`{"mount": "%s", "secret_name": "%s"}`,
 				t.mount,
 				fmt.Sprintf(secretNameFormat,
-					int(rand.Int31n(int32(t.config.NumAssociations))),
+					int(t.rng.Int31n(int32(t.config.NumAssociations))),
 				),
 			),
 		),
@@ -281,7 +285,7 @@ func (t *SyncAWSTest) read(client *api.Client) vegeta.Target {
 			t.GetTargetInfo().pathPrefix,
 			t.mount,
 			fmt.Sprintf(secretNameFormat,
-				int(rand.Int31n(int32(t.config.NumAssociations))),
+				int(t.rng.Int31n(int32(t.config.NumAssociations))),
 			),
 		),
 		Header: http.Header{
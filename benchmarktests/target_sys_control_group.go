@@ -0,0 +1,323 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	ControlGroupTestType   = "control_group_authorize"
+	ControlGroupTestMethod = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[ControlGroupTestType] = func() BenchmarkBuilder { return &ControlGroupTest{} }
+}
+
+// ControlGroupTest authorizes control group requests. Reading a secret
+// protected by a control group is the first step of the flow and is done in
+// the background ahead of the attack's requests, so that only the second step,
+// authorizing the request with sys/control-group/authorize, is measured.
+//
+// Control groups aren't supported by every server, so Setup checks for them
+// before creating anything, letting the test be skipped if they aren't.
+type ControlGroupTest struct {
+	pathPrefix string
+	header     http.Header
+	config     *ControlGroupTestConfig
+	logger     hclog.Logger
+
+	// name is shared by the mount, policies, group and token role created
+	// during setup
+	name      string
+	accessors []string
+	entityIDs []string
+
+	// requesterToken reads the secret to create the requests which are
+	// authorized, and pending receives the accessors of their wrapping
+	// tokens from the background goroutine requesting them, which runs
+	// while the test is attacked
+	requesterToken string
+	requests       background
+	pending        chan string
+}
+
+var _ AttackFinisher = (*ControlGroupTest)(nil)
+
+type ControlGroupTestConfig struct {
+	QueueSize int `hcl:"queue_size,optional"`
+}
+
+func (c *ControlGroupTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *ControlGroupTestConfig `hcl:"config,block"`
+	}{
+		Config: &ControlGroupTestConfig{
+			QueueSize: 100,
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	c.config = testConfig.Config
+
+	if c.config.QueueSize < 1 {
+		return fmt.Errorf("queue_size must be at least 1")
+	}
+	return nil
+}
+
+func (c *ControlGroupTest) Target(client *api.Client) vegeta.Target {
+	// Requests are stopped once an attack finishes, in which case another
+	// attack which is still running starts them again
+	var accessor string
+	for waiting := true; waiting; {
+		ctx := c.requests.running(func(ctx context.Context) { c.request(ctx, client) })
+		select {
+		case accessor = <-c.pending:
+			waiting = false
+		case <-ctx.Done():
+		}
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"accessor": accessor,
+	})
+	if err != nil {
+		c.logger.Error("error marshaling control group authorize data", "error", err)
+	}
+
+	return vegeta.Target{
+		Method: ControlGroupTestMethod,
+		URL:    client.Address() + c.pathPrefix,
+		Body:   body,
+		Header: c.header,
+	}
+}
+
+// request reads the secret protected by the control group until the context
+// is cancelled, queuing the accessors of the wrapping tokens returned for
+// authorization. An empty accessor is queued if the read fails.
+func (c *ControlGroupTest) request(ctx context.Context, client *api.Client) {
+	requester, err := client.Clone()
+	if err != nil {
+		c.logger.Error("error cloning client", "error", err)
+		return
+	}
+	requester.SetToken(c.requesterToken)
+
+	for {
+		var accessor string
+		secret, err := requester.Logical().ReadWithContext(ctx, c.name+"/secret")
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			c.logger.Warn("error requesting secret", "error", err)
+		case secret == nil || secret.WrapInfo == nil:
+			c.logger.Warn("secret read did not require authorization")
+		default:
+			accessor = secret.WrapInfo.Accessor
+		}
+
+		select {
+		case c.pending <- accessor:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// AttackFinished stops requesting authorizations once the attack has
+// finished
+func (c *ControlGroupTest) AttackFinished() {
+	c.requests.stop()
+}
+
+func (c *ControlGroupTest) Cleanup(client *api.Client) error {
+	c.requests.stop()
+
+	c.logger.Trace("cleaning up control group " + c.name)
+	var errs []error
+	for _, accessor := range c.accessors {
+		if err := client.Auth().Token().RevokeAccessor(accessor); err != nil {
+			errs = append(errs, fmt.Errorf("error revoking token: %v", err))
+		}
+	}
+	paths := []string{
+		"identity/group/name/" + c.name,
+		"auth/token/roles/" + c.name,
+		"sys/policies/acl/" + c.name + "-requester",
+		"sys/policies/acl/" + c.name + "-authorizer",
+	}
+	for _, id := range c.entityIDs {
+		paths = append(paths, "identity/entity/id/"+id)
+	}
+	for _, path := range paths {
+		if _, err := client.Logical().Delete(path); err != nil {
+			errs = append(errs, fmt.Errorf("error deleting %v: %v", path, err))
+		}
+	}
+
+	c.logger.Trace(cleanupLogMessage("/v1/" + c.name))
+	if _, err := client.Logical().Delete("sys/mounts/" + c.name); err != nil {
+		errs = append(errs, fmt.Errorf("error cleaning up mount: %v", err))
+	}
+	return errors.Join(errs...)
+}
+
+func (c *ControlGroupTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     ControlGroupTestMethod,
+		pathPrefix: c.pathPrefix,
+	}
+}
+
+func (c *ControlGroupTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	name := mountName
+	c.logger = targetLogger.Named(ControlGroupTestType)
+
+	// Check for control group support before creating anything
+	_, err = client.Logical().Read("sys/config/control-group")
+	if err := probeError("control groups", err); err != nil {
+		return nil, err
+	}
+
+	if topLevelConfig.RandomMounts {
+		name, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	c.logger.Trace(mountLogMessage("secrets", "kvv1", name))
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(name, &api.MountInput{
+			Type: "kv",
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error mounting kv secrets engine: %v", err)
+	}
+
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write(name+"/secret", map[string]interface{}{
+			"foo": "bar",
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error writing kv secret: %v", err)
+	}
+
+	setupLogger := c.logger.Named(name)
+
+	// Reading the secret requires one approval from a member of the group
+	policies := map[string]string{
+		name + "-requester": fmt.Sprintf(`path %q {
+  capabilities = ["read"]
+  control_group = {
+    factor "authorizers" {
+      identity {
+        group_names = [%q]
+        approvals   = 1
+      }
+    }
+  }
+}
+`, name+"/secret", name),
+		name + "-authorizer": fmt.Sprintf("path %q {\n  capabilities = [\"create\", \"update\"]\n}\n", "sys/control-group/authorize"),
+	}
+	for policyName, policy := range policies {
+		setupLogger.Trace("writing policy " + policyName)
+		err = retrySetup(topLevelConfig, func() error {
+			return client.Sys().PutPolicy(policyName, policy)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error writing control group policy: %v", err)
+		}
+	}
+
+	// Both the requester and authorizer need entities, which are created
+	// for the tokens through their entity aliases
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write("auth/token/roles/"+name, map[string]interface{}{
+			"allowed_policies":       []string{name + "-requester", name + "-authorizer"},
+			"allowed_entity_aliases": []string{name + "-requester", name + "-authorizer"},
+			"orphan":                 true,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating token role: %v", err)
+	}
+
+	test := &ControlGroupTest{
+		pathPrefix: "/v1/sys/control-group/authorize",
+		config:     c.config,
+		logger:     c.logger,
+		name:       name,
+		pending:    make(chan string, c.config.QueueSize),
+	}
+
+	tokens := make(map[string]*api.SecretAuth)
+	for _, role := range []string{"requester", "authorizer"} {
+		setupLogger.Trace("creating " + role + " token")
+		var secret *api.Secret
+		err = retrySetup(topLevelConfig, func() error {
+			var err error
+			secret, err = client.Auth().Token().CreateWithRole(&api.TokenCreateRequest{
+				Policies:        []string{name + "-" + role},
+				NoDefaultPolicy: true,
+				EntityAlias:     name + "-" + role,
+			}, name)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error creating %v token: %v", role, err)
+		}
+		if secret == nil || secret.Auth == nil || secret.Auth.EntityID == "" {
+			return nil, fmt.Errorf("no entity assigned to created %v token", role)
+		}
+		tokens[role] = secret.Auth
+		test.accessors = append(test.accessors, secret.Auth.Accessor)
+		test.entityIDs = append(test.entityIDs, secret.Auth.EntityID)
+	}
+
+	setupLogger.Trace("creating authorizer group " + name)
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write("identity/group/name/"+name, map[string]interface{}{
+			"member_entity_ids": []string{tokens["authorizer"].EntityID},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating authorizer group: %v", err)
+	}
+
+	test.requesterToken = tokens["requester"].ClientToken
+	test.header = generateHeader(client)
+	test.header.Set("X-Vault-Token", tokens["authorizer"].ClientToken)
+	return test, nil
+}
+
+func (c *ControlGroupTest) Flags(fs *flag.FlagSet) {}
@@ -0,0 +1,259 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	LeaseLookupTestType = "lease_lookup"
+	LeaseRenewTestType  = "lease_renew"
+	LeaseRevokeTestType = "lease_revoke"
+	LeaseTestMethod     = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[LeaseLookupTestType] = func() BenchmarkBuilder { return &LeaseTest{action: "lookup"} }
+	TestList[LeaseRenewTestType] = func() BenchmarkBuilder { return &LeaseTest{action: "renew"} }
+	TestList[LeaseRevokeTestType] = func() BenchmarkBuilder { return &LeaseTest{action: "revoke"} }
+}
+
+type LeaseTest struct {
+	action     string
+	pathPrefix string
+	mountPath  string
+	header     http.Header
+	config     *LeaseTestConfig
+	rng        *rand.Rand
+	logger     hclog.Logger
+
+	// leases is the pool of lease IDs created during setup. Revocations
+	// consume the pool in order, tracked by next.
+	leases      []string
+	next        atomic.Int64
+	exhaustOnce sync.Once
+}
+
+type LeaseTestConfig struct {
+	NumLeases int    `hcl:"num_leases,optional"`
+	LeasePath string `hcl:"lease_path,optional"`
+	TTL       string `hcl:"ttl,optional"`
+	Increment string `hcl:"increment,optional"`
+}
+
+func (l *LeaseTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *LeaseTestConfig `hcl:"config,block"`
+	}{
+		Config: &LeaseTestConfig{
+			NumLeases: 100,
+			TTL:       "1h",
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	l.config = testConfig.Config
+
+	if l.config.NumLeases < 1 {
+		return fmt.Errorf("num_leases must be at least 1")
+	}
+	return nil
+}
+
+func (l *LeaseTest) Target(client *api.Client) vegeta.Target {
+	var leaseID string
+	switch l.action {
+	case "revoke":
+		// Revoking a lease consumes it, so each request revokes the next
+		// lease in the pool. Once all have been revoked, requests revoke
+		// leases which no longer exist.
+		i := l.next.Add(1) - 1
+		if i >= int64(len(l.leases)) {
+			l.exhaustOnce.Do(func() {
+				l.logger.Warn("all leases have been revoked, increase num_leases to revoke live leases for the whole test")
+			})
+		}
+		leaseID = l.leases[i%int64(len(l.leases))]
+	default:
+		leaseID = l.leases[l.rng.Intn(len(l.leases))]
+	}
+
+	data := map[string]interface{}{
+		"lease_id": leaseID,
+	}
+	if l.action == "renew" && l.config.Increment != "" {
+		data["increment"] = l.config.Increment
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		l.logger.Error("error marshaling lease request", "error", err)
+	}
+
+	return vegeta.Target{
+		Method: LeaseTestMethod,
+		URL:    client.Address() + l.pathPrefix,
+		Body:   body,
+		Header: l.header,
+	}
+}
+
+func (l *LeaseTest) Cleanup(client *api.Client) error {
+	// Unmounting the PKI mount revokes its leases, while leases created from
+	// a configured lease_path are revoked individually
+	if l.mountPath != "" {
+		l.logger.Trace(cleanupLogMessage(l.mountPath))
+		_, err := client.Logical().Delete("/sys/mounts/" + l.mountPath)
+		if err != nil {
+			return fmt.Errorf("error cleaning up mount: %v", err)
+		}
+		return nil
+	}
+
+	l.logger.Trace("revoking leases", "count", len(l.leases))
+	var errs []error
+	for _, leaseID := range l.leases {
+		if err := client.Sys().Revoke(leaseID); err != nil {
+			errs = append(errs, fmt.Errorf("error revoking lease %v: %w", leaseID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (l *LeaseTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     LeaseTestMethod,
+		pathPrefix: l.pathPrefix,
+	}
+}
+
+func (l *LeaseTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	l.logger = targetLogger.Named("lease_" + l.action)
+
+	// Without a configured lease_path, leases are created by issuing
+	// certificates from a PKI role which generates leases
+	leasePath := l.config.LeasePath
+	var mountPath string
+	if leasePath == "" {
+		mountPath = mountName
+		if topLevelConfig.RandomMounts {
+			mountPath, err = uuid.GenerateUUID()
+			if err != nil {
+				log.Fatalf("can't create UUID")
+			}
+		}
+
+		leasePath, err = setupLeasePKI(client, mountPath, l.config.TTL, l.logger, topLevelConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	l.logger.Trace("creating leases", "path", leasePath, "count", l.config.NumLeases)
+	leases := make([]string, 0, l.config.NumLeases)
+	for i := 0; i < l.config.NumLeases; i++ {
+		var secret *api.Secret
+		err = retrySetup(topLevelConfig, func() error {
+			var err error
+			if mountPath != "" {
+				secret, err = client.Logical().Write(leasePath, map[string]interface{}{
+					"common_name": "lease.example.com",
+				})
+			} else {
+				secret, err = client.Logical().Read(leasePath)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error creating lease: %v", err)
+		}
+		if secret == nil || secret.LeaseID == "" {
+			return nil, fmt.Errorf("no lease returned from %v", leasePath)
+		}
+		leases = append(leases, secret.LeaseID)
+	}
+
+	return &LeaseTest{
+		action:     l.action,
+		pathPrefix: "/v1/sys/leases/" + l.action,
+		mountPath:  mountPath,
+		header:     generateHeader(client),
+		config:     l.config,
+		rng:        topLevelConfig.Rand,
+		logger:     l.logger,
+		leases:     leases,
+	}, nil
+}
+
+// setupLeasePKI mounts a PKI secrets engine with a role whose certificates are
+// issued with leases of the given TTL, returning the path to issue them from
+func setupLeasePKI(client *api.Client, mountPath, ttl string, logger hclog.Logger, topLevelConfig *TopLevelTargetConfig) (string, error) {
+	logger.Trace(mountLogMessage("secrets", "pki", mountPath))
+	err := retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(mountPath, &api.MountInput{
+			Type: "pki",
+		})
+	})
+	if err != nil {
+		return "", fmt.Errorf("error mounting pki secrets engine: %v", err)
+	}
+
+	setupLogger := logger.Named(mountPath)
+
+	setupLogger.Trace(writingLogMessage("root ca"))
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write(mountPath+"/root/generate/internal", map[string]interface{}{
+			"common_name": "example.com",
+			"key_type":    "ec",
+			"ttl":         "87600h",
+		})
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("error generating root ca: %v", err)
+	}
+
+	setupLogger.Trace(writingLogMessage("pki role"))
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write(mountPath+"/roles/benchmark-role", map[string]interface{}{
+			"allow_any_name": true,
+			"generate_lease": true,
+			"key_type":       "ec",
+			"ttl":            ttl,
+		})
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("error writing pki role: %v", err)
+	}
+
+	return mountPath + "/issue/benchmark-role", nil
+}
+
+// ConsumesSetup reports that revokes use up the leases created during setup
+func (l *LeaseTest) ConsumesSetup() bool {
+	return l.action == "revoke"
+}
+
+func (l *LeaseTest) Flags(fs *flag.FlagSet) {}
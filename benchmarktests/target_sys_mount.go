@@ -5,10 +5,12 @@
 package benchmarktests
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/go-hclog"
@@ -20,31 +22,69 @@ import (
 )
 
 const (
-	MountType   = "mount"
-	MountMethod = "POST"
+	MountType        = "mount"
+	MountMethod      = "POST"
+	MountsListType   = "mounts_list"
+	MountsListMethod = "GET"
+	AuthListType     = "auth_list"
 )
 
 func init() {
 	// "Register" this test to the main test registry
 	TestList[MountType] = func() BenchmarkBuilder {
-		return &MountTest{}
+		return &MountTest{action: "mount"}
+	}
+	TestList[MountsListType] = func() BenchmarkBuilder {
+		return &MountTest{action: "list"}
+	}
+	TestList[AuthListType] = func() BenchmarkBuilder {
+		return &MountTest{action: "list", mountType: "auth"}
 	}
 }
 
 type MountTest struct {
+	action       string
 	pathPrefix   string
 	header       http.Header
 	config       *MountTestConfig
 	mountType    string
 	mountPrefix  string
 	plugin       string
+	body         []byte
 	capabilities []string
 	logger       hclog.Logger
+
+	// namespace is the full path of the namespace mounts are created in,
+	// and createdNamespaces are those which were created during setup
+	namespace         string
+	createdNamespaces []string
 }
 
 type MountTestConfig struct {
 	MountType string `hcl:"mount_type,optional"`
 	Plugin    string `hcl:"plugin,optional"`
+	Namespace string `hcl:"namespace,optional"`
+	NumMounts int    `hcl:"num_mounts,optional"`
+
+	// Description, Options and MountConfig are sent with every mount
+	// created, so that the cost reflects provisioning tools which send
+	// substantial configuration
+	Description string             `hcl:"description,optional"`
+	Options     map[string]string  `hcl:"options,optional"`
+	MountConfig *MountCreateConfig `hcl:"mount_config,block"`
+}
+
+// MountCreateConfig is the configuration of the mounts created
+//
+// /sys/mounts/:path and /sys/auth/:path
+type MountCreateConfig struct {
+	DefaultLeaseTTL           string   `hcl:"default_lease_ttl,optional"`
+	MaxLeaseTTL               string   `hcl:"max_lease_ttl,optional"`
+	ListingVisibility         string   `hcl:"listing_visibility,optional"`
+	AuditNonHMACRequestKeys   []string `hcl:"audit_non_hmac_request_keys,optional"`
+	AuditNonHMACResponseKeys  []string `hcl:"audit_non_hmac_response_keys,optional"`
+	PassthroughRequestHeaders []string `hcl:"passthrough_request_headers,optional"`
+	AllowedResponseHeaders    []string `hcl:"allowed_response_headers,optional"`
 }
 
 func (m *MountTest) ParseConfig(body hcl.Body) error {
@@ -56,16 +96,40 @@ func (m *MountTest) ParseConfig(body hcl.Body) error {
 			Plugin:    "kv-v2",
 		},
 	}
+	if m.action == "list" {
+		testConfig.Config.NumMounts = 1000
+	}
+	if m.mountType == "auth" {
+		testConfig.Config.MountType = "auth"
+		testConfig.Config.Plugin = "userpass"
+	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
 	m.config = testConfig.Config
+
+	switch {
+	case m.action == "list" && m.config.NumMounts < 1:
+		return fmt.Errorf("num_mounts must be at least 1")
+	case m.action != "list" && m.config.NumMounts != 0:
+		return fmt.Errorf("num_mounts is only supported by %v and %v", MountsListType, AuthListType)
+	case m.mountType == "auth" && m.config.MountType != "auth":
+		return fmt.Errorf("%v only lists auth mounts, use %v to list secret mounts", AuthListType, MountsListType)
+	}
 	return nil
 }
 
 func (m *MountTest) Target(client *api.Client) vegeta.Target {
+	if m.action == "list" {
+		return vegeta.Target{
+			Method: MountsListMethod,
+			URL:    client.Address() + m.pathPrefix,
+			Header: m.header,
+		}
+	}
+
 	mountPath, err := uuid.GenerateUUID()
 	if err != nil {
 		panic(err)
@@ -76,14 +140,18 @@ func (m *MountTest) Target(client *api.Client) vegeta.Target {
 	return vegeta.Target{
 		Method: MountMethod,
 		URL:    client.Address() + m.pathPrefix + "/" + mountPath,
-		Body:   []byte(`{"type":"` + m.plugin + `"}`),
+		Body:   m.body,
 		Header: m.header,
 	}
 }
 
 func (m *MountTest) GetTargetInfo() TargetInfo {
+	method := MountMethod
+	if m.action == "list" {
+		method = MountsListMethod
+	}
 	return TargetInfo{
-		method:     MountMethod,
+		method:     method,
 		pathPrefix: m.pathPrefix,
 	}
 }
@@ -91,6 +159,23 @@ func (m *MountTest) GetTargetInfo() TargetInfo {
 func (m *MountTest) Cleanup(client *api.Client) error {
 	m.logger.Trace("cleaning mounts under " + m.pathPrefix)
 
+	// The namespace is removed once the mounts inside it have been cleaned up
+	if m.namespace != "" {
+		defer func(client *api.Client) {
+			if err := cleanupNamespaces(client, m.createdNamespaces); err != nil {
+				m.logger.Error("error cleaning up namespace", "error", err)
+			}
+		}(client)
+		client = client.WithNamespace(m.namespace)
+	}
+
+	// kv-v2 is mounted as version 2 of the kv plugin, so its mounts are
+	// listed with the kv type
+	pluginType := m.plugin
+	if pluginType == "kv-v2" {
+		pluginType = "kv"
+	}
+
 	switch m.mountType {
 	case "secret":
 		mounts, err := client.Sys().ListMounts()
@@ -99,7 +184,7 @@ func (m *MountTest) Cleanup(client *api.Client) error {
 		}
 
 		for path, info := range mounts {
-			if info.Type != m.plugin {
+			if info.Type != pluginType {
 				continue
 			}
 
@@ -118,7 +203,7 @@ func (m *MountTest) Cleanup(client *api.Client) error {
 		}
 
 		for path, info := range mounts {
-			if info.Type != m.plugin {
+			if info.Type != pluginType {
 				continue
 			}
 
@@ -147,6 +232,18 @@ func (m *MountTest) Setup(client *api.Client, mountName string, topLevelConfig *
 		}
 	}
 
+	var nsPath string
+	var created []string
+	if m.config.Namespace != "" {
+		m.logger.Trace("setting up namespace", "namespace", m.config.Namespace)
+		created, err = setupNamespaces(client, []string{m.config.Namespace})
+		if err != nil {
+			return nil, err
+		}
+		nsPath = namespacePath(client, m.config.Namespace)
+		client = client.WithNamespace(nsPath)
+	}
+
 	var table string
 	switch m.config.MountType {
 	case "secret":
@@ -157,15 +254,84 @@ func (m *MountTest) Setup(client *api.Client, mountName string, topLevelConfig *
 		return nil, fmt.Errorf("unknown mount type: %v", m.config.MountType)
 	}
 
-	headers := http.Header{"X-Vault-Token": []string{client.Token()}, "X-Vault-Namespace": []string{client.Headers().Get("X-Vault-Namespace")}}
+	data, err := m.mountData()
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling mount data: %v", err)
+	}
+
+	// Listing reads the whole mount table, so it is filled with mounts named
+	// like those the mount test creates, which cleanup removes the same way
+	pathPrefix := "/v1/sys/" + table + "/" + mountPath
+	if m.action == "list" {
+		pathPrefix = "/v1/sys/" + table
+		if err := m.seedMounts(client, table, mountPath, data, topLevelConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	headers := generateHeader(client)
 	return &MountTest{
-		pathPrefix:  "/v1/sys/" + table + "/" + mountPath,
+		action:      m.action,
+		pathPrefix:  pathPrefix,
 		header:      headers,
 		mountPrefix: mountPath,
 		mountType:   m.config.MountType,
 		plugin:      m.config.Plugin,
+		body:        body,
 		logger:      m.logger,
+
+		namespace:         nsPath,
+		createdNamespaces: created,
 	}, nil
 }
 
+// mountData returns the request data every mount is created with
+func (m *MountTest) mountData() (map[string]interface{}, error) {
+	data := map[string]interface{}{
+		"type": m.config.Plugin,
+	}
+	if m.config.Description != "" {
+		data["description"] = m.config.Description
+	}
+	if len(m.config.Options) > 0 {
+		data["options"] = m.config.Options
+	}
+	if m.config.MountConfig != nil {
+		mountConfig, err := structToMap(m.config.MountConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing mount config from struct: %v", err)
+		}
+		if len(mountConfig) > 0 {
+			data["config"] = mountConfig
+		}
+	}
+	return data, nil
+}
+
+// seedMounts creates num_mounts mounts of the plugin under mountPrefix in the
+// given mount table, with the same data as the mount test
+func (m *MountTest) seedMounts(client *api.Client, table, mountPrefix string, data map[string]interface{}, topLevelConfig *TopLevelTargetConfig) error {
+	setupLogger := m.logger.Named(mountPrefix)
+	setupLogger.Info("creating mounts", "count", m.config.NumMounts, "type", m.config.MountType, "plugin", m.config.Plugin)
+	for i := 0; i < m.config.NumMounts; i++ {
+		mountPath := mountPrefix + "/" + m.config.Plugin + "-" + strconv.Itoa(i)
+		err := retrySetup(topLevelConfig, func() error {
+			_, err := client.Logical().Write("sys/"+table+"/"+mountPath, data)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("error mounting %v %v engine: %v", m.config.Plugin, m.config.MountType, err)
+		}
+
+		if (i+1)%mountRoutingProgress == 0 {
+			setupLogger.Debug("created mounts", "count", i+1)
+		}
+	}
+	return nil
+}
+
 func (m *MountTest) Flags(fs *flag.FlagSet) {}
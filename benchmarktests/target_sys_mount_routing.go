@@ -0,0 +1,180 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	MountRoutingTestType   = "mount_routing"
+	MountRoutingTestMethod = "GET"
+
+	// mountRoutingProgress is how often setup logs the number of mounts
+	// created so far
+	mountRoutingProgress = 1000
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[MountRoutingTestType] = func() BenchmarkBuilder { return &MountRoutingTest{} }
+}
+
+// MountRoutingTest reads a secret from a random mount out of a large number
+// created during setup, to measure how the size of the mount table affects
+// routing requests to their mount
+type MountRoutingTest struct {
+	pathPrefix  string
+	mountPrefix string
+	secretPath  string
+	header      http.Header
+	config      *MountRoutingTestConfig
+	rng         *rand.Rand
+	logger      hclog.Logger
+}
+
+type MountRoutingTestConfig struct {
+	NumMounts int    `hcl:"num_mounts,optional"`
+	MountType string `hcl:"mount_type,optional"`
+}
+
+func (m *MountRoutingTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *MountRoutingTestConfig `hcl:"config,block"`
+	}{
+		Config: &MountRoutingTestConfig{
+			NumMounts: 1000,
+			MountType: "kv",
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	m.config = testConfig.Config
+
+	if m.config.NumMounts < 1 {
+		return fmt.Errorf("num_mounts must be at least 1")
+	}
+	switch m.config.MountType {
+	case "kv", "kv-v2":
+	default:
+		return fmt.Errorf("mount_type must be one of kv or kv-v2")
+	}
+	return nil
+}
+
+func (m *MountRoutingTest) Target(client *api.Client) vegeta.Target {
+	return vegeta.Target{
+		Method: MountRoutingTestMethod,
+		URL:    client.Address() + m.pathPrefix + "-" + strconv.Itoa(m.rng.Intn(m.config.NumMounts)) + m.secretPath,
+		Header: m.header,
+	}
+}
+
+func (m *MountRoutingTest) Cleanup(client *api.Client) error {
+	m.logger.Trace("cleaning up mounts", "prefix", m.mountPrefix, "count", m.config.NumMounts)
+	var errs []error
+	for i := 0; i < m.config.NumMounts; i++ {
+		mountPath := m.mountPrefix + "-" + strconv.Itoa(i)
+		if err := client.Sys().Unmount(mountPath); err != nil {
+			errs = append(errs, fmt.Errorf("error cleaning up %v: %w", mountPath, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *MountRoutingTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     MountRoutingTestMethod,
+		pathPrefix: m.pathPrefix,
+	}
+}
+
+func (m *MountRoutingTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	mountPrefix := mountName
+	m.logger = targetLogger.Named(MountRoutingTestType)
+
+	if topLevelConfig.RandomMounts {
+		mountPrefix, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	secretPath := "/benchmark"
+	options := map[string]string{}
+	secval := map[string]interface{}{
+		"foo": 1,
+	}
+	if m.config.MountType == "kv-v2" {
+		secretPath = "/data/benchmark"
+		options["version"] = "2"
+		secval = map[string]interface{}{
+			"data": secval,
+		}
+	}
+
+	// Every mount holds a secret, so that reads are routed to the mount and
+	// served from its storage rather than returning not found
+	setupLogger := m.logger.Named(mountPrefix)
+	setupLogger.Info("creating mounts", "count", m.config.NumMounts, "type", m.config.MountType)
+	for i := 0; i < m.config.NumMounts; i++ {
+		mountPath := mountPrefix + "-" + strconv.Itoa(i)
+		err = retrySetup(topLevelConfig, func() error {
+			return client.Sys().Mount(mountPath, &api.MountInput{
+				Type:    "kv",
+				Options: options,
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error mounting kv secrets engine: %v", err)
+		}
+
+		if m.config.MountType == "kv-v2" {
+			if err := waitForKVV2Upgrade(client, mountPath); err != nil {
+				return nil, err
+			}
+		}
+
+		err = retrySetup(topLevelConfig, func() error {
+			_, err := client.Logical().Write(mountPath+secretPath, secval)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error writing kv secret: %v", err)
+		}
+
+		if (i+1)%mountRoutingProgress == 0 {
+			setupLogger.Debug("created mounts", "count", i+1)
+		}
+	}
+
+	return &MountRoutingTest{
+		pathPrefix:  "/v1/" + mountPrefix,
+		mountPrefix: mountPrefix,
+		secretPath:  secretPath,
+		header:      generateHeader(client),
+		config:      m.config,
+		rng:         topLevelConfig.Rand,
+		logger:      m.logger,
+	}, nil
+}
+
+func (m *MountRoutingTest) Flags(fs *flag.FlagSet) {}
@@ -0,0 +1,100 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+)
+
+func TestMountTest_NumMounts(t *testing.T) {
+	hclFile, diags := hclparse.NewParser().ParseHCL([]byte(`
+config {
+  mount_type = "auth"
+  plugin     = "userpass"
+}
+`), "mounts.hcl")
+	if diags.HasErrors() {
+		t.Fatalf("err: %v", diags)
+	}
+
+	list := &MountTest{action: "list"}
+	if err := list.ParseConfig(hclFile.Body); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if list.config.NumMounts != 1000 {
+		t.Fatalf("expected 1000 mounts by default, got: %d", list.config.NumMounts)
+	}
+
+	// auth_list defaults to seeding userpass auth mounts
+	authList := &MountTest{action: "list", mountType: "auth"}
+	if err := authList.ParseConfig(hcl.EmptyBody()); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if authList.config.MountType != "auth" || authList.config.Plugin != "userpass" {
+		t.Fatalf("expected userpass auth mounts, got: %+v", authList.config)
+	}
+
+	hclFile, diags = hclparse.NewParser().ParseHCL([]byte(`
+config {
+  num_mounts = 10
+}
+`), "mount.hcl")
+	if diags.HasErrors() {
+		t.Fatalf("err: %v", diags)
+	}
+
+	mount := &MountTest{action: "mount"}
+	if err := mount.ParseConfig(hclFile.Body); err == nil {
+		t.Fatal("expected error using num_mounts with mount")
+	}
+}
+
+func TestMountTest_MountData(t *testing.T) {
+	mount := &MountTest{action: "mount"}
+	if err := mount.ParseConfig(hcl.EmptyBody()); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	data, err := mount.mountData()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(data) != 1 || data["type"] != "kv-v2" {
+		t.Fatalf("expected only the type by default, got: %v", data)
+	}
+
+	hclFile, diags := hclparse.NewParser().ParseHCL([]byte(`
+config {
+  description = "team-a secrets"
+  options = {
+    version = "2"
+  }
+  mount_config {
+    max_lease_ttl               = "768h"
+    audit_non_hmac_request_keys = ["path"]
+  }
+}
+`), "mount.hcl")
+	if diags.HasErrors() {
+		t.Fatalf("err: %v", diags)
+	}
+
+	mount = &MountTest{action: "mount"}
+	if err := mount.ParseConfig(hclFile.Body); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	data, err = mount.mountData()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if data["description"] != "team-a secrets" || data["options"].(map[string]string)["version"] != "2" {
+		t.Fatalf("unexpected mount data: %v", data)
+	}
+	config := data["config"].(map[string]interface{})
+	if len(config) != 2 || config["max_lease_ttl"] != "768h" {
+		t.Fatalf("expected only the configured mount config, got: %v", config)
+	}
+}
@@ -5,11 +5,15 @@
 package benchmarktests
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-uuid"
@@ -20,30 +24,64 @@ import (
 )
 
 const (
-	NamespaceType   = "namespace"
-	NamespaceMethod = "POST"
+	NamespaceType         = "namespace"
+	NamespaceMethod       = "POST"
+	NamespaceDeleteType   = "namespace_delete"
+	NamespaceDeleteMethod = "DELETE"
 )
 
 func init() {
 	// "Register" this test to the main test registry
 	TestList[NamespaceType] = func() BenchmarkBuilder {
-		return &NamespaceTest{}
+		return &NamespaceTest{action: "create"}
+	}
+	TestList[NamespaceDeleteType] = func() BenchmarkBuilder {
+		return &NamespaceTest{action: "delete"}
 	}
 }
 
 type NamespaceTest struct {
+	action          string
 	pathPrefix      string
 	header          http.Header
 	config          *NamespaceTestConfig
 	namespacePrefix string
 	namespaceData   string
+	body            []byte
 	plugin          string
 	capabilities    []string
 	logger          hclog.Logger
+
+	// pool holds the namespaces created during setup for namespace_delete,
+	// the deepest level of every tree before the level above it, so that
+	// each namespace is a leaf when deleted and a parent's delete isn't in
+	// flight alongside its child's. Each request deletes the next namespace
+	// in the pool, tracked by next.
+	pool        []namespaceDelete
+	next        atomic.Int64
+	exhaustOnce sync.Once
+}
+
+// namespaceDelete is the request deleting a namespace of the pool. Nested
+// namespaces are deleted from within their parent namespace.
+type namespaceDelete struct {
+	name   string
+	header http.Header
 }
 
 type NamespaceTestConfig struct {
 	NamespacePrefix string `hcl:"namespace_prefix,optional"`
+
+	// CustomMetadata is sent with every namespace created, so that the cost
+	// reflects provisioning tools which send substantial metadata
+	CustomMetadata map[string]string `hcl:"custom_metadata,optional"`
+
+	// NumNamespaces is the number of top level namespaces namespace_delete
+	// creates during setup, each with Depth levels of namespaces and
+	// NumMounts mounts in every namespace
+	NumNamespaces int `hcl:"num_namespaces,optional"`
+	Depth         int `hcl:"depth,optional"`
+	NumMounts     int `hcl:"num_mounts,optional"`
 }
 
 func (n *NamespaceTest) ParseConfig(body hcl.Body) error {
@@ -55,15 +93,45 @@ func (n *NamespaceTest) ParseConfig(body hcl.Body) error {
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	if n.action == "delete" {
+		testConfig.Config.NumNamespaces = 1000
+		testConfig.Config.Depth = 1
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
 	n.config = testConfig.Config
+
+	if n.action != "delete" {
+		switch {
+		case n.config.NumNamespaces != 0:
+			return fmt.Errorf("num_namespaces is only supported by %v", NamespaceDeleteType)
+		case n.config.Depth != 0:
+			return fmt.Errorf("depth is only supported by %v", NamespaceDeleteType)
+		case n.config.NumMounts != 0:
+			return fmt.Errorf("num_mounts is only supported by %v", NamespaceDeleteType)
+		}
+		return nil
+	}
+
+	switch {
+	case n.config.NumNamespaces < 1:
+		return fmt.Errorf("num_namespaces must be at least 1")
+	case n.config.Depth < 1:
+		return fmt.Errorf("depth must be at least 1")
+	case n.config.NumMounts < 0:
+		return fmt.Errorf("num_mounts can't be negative")
+	}
 	return nil
 }
 
 func (n *NamespaceTest) Target(client *api.Client) vegeta.Target {
+	if n.action == "delete" {
+		return n.deleteTarget(client)
+	}
+
 	namespacePath, err := uuid.GenerateUUID()
 	if err != nil {
 		panic(err)
@@ -74,14 +142,37 @@ func (n *NamespaceTest) Target(client *api.Client) vegeta.Target {
 	return vegeta.Target{
 		Method: NamespaceMethod,
 		URL:    client.Address() + n.pathPrefix + "/" + namespacePath,
-		Body:   []byte(`{"source":"benchmark-` + n.namespaceData + `"}`),
+		Body:   n.body,
 		Header: n.header,
 	}
 }
 
+// deleteTarget deletes the next namespace of the pool
+func (n *NamespaceTest) deleteTarget(client *api.Client) vegeta.Target {
+	// Once every namespace has been deleted, requests delete namespaces
+	// which no longer exist
+	i := n.next.Add(1) - 1
+	if i >= int64(len(n.pool)) {
+		n.exhaustOnce.Do(func() {
+			n.logger.Warn("all namespaces have been deleted, increase num_namespaces to delete live namespaces for the whole test")
+		})
+	}
+	ns := n.pool[i%int64(len(n.pool))]
+
+	return vegeta.Target{
+		Method: NamespaceDeleteMethod,
+		URL:    client.Address() + n.pathPrefix + "/" + ns.name,
+		Header: ns.header,
+	}
+}
+
 func (n *NamespaceTest) GetTargetInfo() TargetInfo {
+	method := NamespaceMethod
+	if n.action == "delete" {
+		method = NamespaceDeleteMethod
+	}
 	return TargetInfo{
-		method:     NamespaceMethod,
+		method:     method,
 		pathPrefix: n.pathPrefix,
 	}
 }
@@ -93,6 +184,10 @@ func (n *NamespaceTest) Cleanup(client *api.Client) error {
 	if err != nil {
 		return fmt.Errorf("error listing namespaces: %w", err)
 	}
+	// Every namespace may have been deleted by namespace_delete
+	if resp == nil {
+		return nil
+	}
 
 	for _, pathRaw := range resp.Data["keys"].([]interface{}) {
 		path := pathRaw.(string)
@@ -109,11 +204,42 @@ func (n *NamespaceTest) Cleanup(client *api.Client) error {
 			}
 		}
 
-		if _, err := client.Logical().Delete("sys/namespaces/" + path); err != nil {
-			return fmt.Errorf("error cleaning up %v: %w", path, err)
+		if err := deleteNamespaceTree(client, strings.TrimSuffix(path, "/")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteNamespaceTree deletes a child namespace of the client's namespace
+// along with the namespaces nested in it, deepest first
+func deleteNamespaceTree(client *api.Client, path string) error {
+	children, err := client.WithNamespace(namespacePath(client, path)).Logical().List("sys/namespaces")
+	if err != nil {
+		return fmt.Errorf("error listing namespaces in %v: %w", path, err)
+	}
+	if children != nil {
+		keys, _ := children.Data["keys"].([]interface{})
+		for _, childRaw := range keys {
+			child := strings.TrimSuffix(fmt.Sprint(childRaw), "/")
+			if err := deleteNamespaceTree(client, path+"/"+child); err != nil {
+				return err
+			}
 		}
 	}
 
+	parent, name := "", path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		parent, name = path[:i], path[i+1:]
+	}
+	parentClient := client
+	if parent != "" {
+		parentClient = client.WithNamespace(namespacePath(client, parent))
+	}
+	if _, err := parentClient.Logical().Delete("sys/namespaces/" + name); err != nil {
+		return fmt.Errorf("error cleaning up %v: %w", path, err)
+	}
 	return nil
 }
 
@@ -129,14 +255,134 @@ func (n *NamespaceTest) Setup(client *api.Client, namespaceName string, topLevel
 		}
 	}
 
-	headers := http.Header{"X-Vault-Token": []string{client.Token()}, "X-Vault-Namespace": []string{client.Headers().Get("X-Vault-Namespace")}}
+	data := map[string]interface{}{
+		"source": "benchmark-" + namespaceData,
+	}
+	if len(n.config.CustomMetadata) > 0 {
+		data["custom_metadata"] = n.config.CustomMetadata
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling namespace data: %v", err)
+	}
+
+	var pool []namespaceDelete
+	if n.action == "delete" {
+		pool, err = n.createPool(client, namespaceData, data, topLevelConfig)
+		if err != nil {
+			// The namespaces created so far are returned to be cleaned up
+			// if setup is interrupted
+			if topLevelConfig.interrupted() {
+				return &NamespaceTest{
+					pathPrefix:      "/v1/sys/namespaces",
+					namespacePrefix: n.config.NamespacePrefix,
+					namespaceData:   namespaceData,
+					logger:          n.logger,
+				}, err
+			}
+			return nil, err
+		}
+	}
+
+	headers := generateHeader(client)
 	return &NamespaceTest{
+		action:          n.action,
 		pathPrefix:      "/v1/sys/namespaces",
 		header:          headers,
+		config:          n.config,
 		namespacePrefix: n.config.NamespacePrefix,
 		namespaceData:   namespaceData,
+		body:            body,
 		logger:          n.logger,
+		pool:            pool,
 	}, nil
 }
 
+// createPool creates num_namespaces top level namespaces, named like those the
+// namespace test creates so that cleanup removes any which weren't deleted,
+// each with depth levels of nested namespaces. The returned pool lists each
+// namespace after those nested in it.
+func (n *NamespaceTest) createPool(client *api.Client, namespaceData string, data map[string]interface{}, topLevelConfig *TopLevelTargetConfig) ([]namespaceDelete, error) {
+	setupLogger := n.logger.Named(namespaceData)
+	setupLogger.Info("creating namespaces", "count", n.config.NumNamespaces, "depth", n.config.Depth, "mounts", n.config.NumMounts)
+
+	if n.config.NumNamespaces <= topLevelConfig.Workers && n.config.Depth > 1 {
+		setupLogger.Warn("num_namespaces should be larger than the number of workers, or a namespace may be deleted while its child's delete is in flight", "num_namespaces", n.config.NumNamespaces, "workers", topLevelConfig.Workers)
+	}
+
+	trees := make([][]namespaceDelete, 0, n.config.NumNamespaces)
+	for i := 0; i < n.config.NumNamespaces; i++ {
+		var tree []namespaceDelete
+		parent := ""
+		for d := 0; d < n.config.Depth; d++ {
+			name := n.config.NamespacePrefix + "-" + namespaceData + "-" + strconv.Itoa(i)
+			if d > 0 {
+				name = "nested-" + strconv.Itoa(d)
+			}
+
+			parentClient := client
+			if parent != "" {
+				parentClient = client.WithNamespace(namespacePath(client, parent))
+			}
+			err := retrySetup(topLevelConfig, func() error {
+				_, err := parentClient.Logical().Write("sys/namespaces/"+name, data)
+				return err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error creating namespace %v: %v", name, err)
+			}
+			tree = append(tree, namespaceDelete{name: name, header: generateHeader(parentClient)})
+
+			path := name
+			if parent != "" {
+				path = parent + "/" + name
+			}
+			if err := seedNamespaceMounts(client.WithNamespace(namespacePath(client, path)), n.config.NumMounts, topLevelConfig); err != nil {
+				return nil, err
+			}
+			parent = path
+		}
+
+		trees = append(trees, tree)
+
+		if (i+1)%mountRoutingProgress == 0 {
+			setupLogger.Debug("created namespaces", "count", i+1)
+		}
+	}
+
+	// The deepest level of every tree is deleted first, then the level above
+	// it, so that num_namespaces requests separate a child's delete from its
+	// parent's
+	pool := make([]namespaceDelete, 0, n.config.NumNamespaces*n.config.Depth)
+	for d := n.config.Depth - 1; d >= 0; d-- {
+		for _, tree := range trees {
+			pool = append(pool, tree[d])
+		}
+	}
+	return pool, nil
+}
+
+// seedNamespaceMounts creates mounts in the client's namespace, which are
+// removed along with the namespace
+func seedNamespaceMounts(client *api.Client, numMounts int, topLevelConfig *TopLevelTargetConfig) error {
+	for i := 0; i < numMounts; i++ {
+		mountPath := "kv-" + strconv.Itoa(i)
+		err := retrySetup(topLevelConfig, func() error {
+			return client.Sys().Mount(mountPath, &api.MountInput{
+				Type: "kv",
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("error mounting kv secrets engine: %v", err)
+		}
+	}
+	return nil
+}
+
+// ConsumesSetup reports that deletes use up the namespaces created during
+// setup
+func (n *NamespaceTest) ConsumesSetup() bool {
+	return n.action == "delete"
+}
+
 func (n *NamespaceTest) Flags(fs *flag.FlagSet) {}
@@ -0,0 +1,69 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/openbao/openbao/api/v2"
+)
+
+func TestNamespaceTest_ParseConfig(t *testing.T) {
+	del := &NamespaceTest{action: "delete"}
+	if err := del.ParseConfig(hcl.EmptyBody()); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if del.config.NumNamespaces != 1000 || del.config.Depth != 1 {
+		t.Fatalf("expected 1000 namespaces without nesting by default, got: %+v", del.config)
+	}
+
+	hclFile, diags := hclparse.NewParser().ParseHCL([]byte(`
+config {
+  depth = 3
+}
+`), "namespace.hcl")
+	if diags.HasErrors() {
+		t.Fatalf("err: %v", diags)
+	}
+
+	create := &NamespaceTest{action: "create"}
+	if err := create.ParseConfig(hclFile.Body); err == nil {
+		t.Fatal("expected error using depth with namespace")
+	}
+}
+
+func TestNamespaceTest_DeleteTarget(t *testing.T) {
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Nested namespaces are deleted from within their parent
+	header := func(namespace string) http.Header {
+		return http.Header{"X-Vault-Namespace": []string{namespace}}
+	}
+	n := &NamespaceTest{
+		action:     "delete",
+		pathPrefix: "/v1/sys/namespaces",
+		logger:     hclog.NewNullLogger(),
+		pool: []namespaceDelete{
+			{name: "nested-1", header: header("benchmark-0")},
+			{name: "benchmark-0", header: header("")},
+		},
+	}
+	for _, expected := range []struct{ name, namespace string }{
+		{"nested-1", "benchmark-0"},
+		{"benchmark-0", ""},
+		{"nested-1", "benchmark-0"},
+	} {
+		tgt := n.Target(client)
+		if tgt.Method != NamespaceDeleteMethod || tgt.URL != client.Address()+"/v1/sys/namespaces/"+expected.name || tgt.Header.Get("X-Vault-Namespace") != expected.namespace {
+			t.Fatalf("expected a delete of %v in %q, got: %v %v %v", expected.name, expected.namespace, tgt.Method, tgt.URL, tgt.Header)
+		}
+	}
+}
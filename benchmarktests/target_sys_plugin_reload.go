@@ -0,0 +1,143 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	PluginReloadTestType   = "plugin_reload"
+	PluginReloadTestMethod = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[PluginReloadTestType] = func() BenchmarkBuilder { return &PluginReloadTest{} }
+}
+
+type PluginReloadTest struct {
+	pathPrefix string
+	mountPath  string
+	body       []byte
+	header     http.Header
+	config     *PluginReloadTestConfig
+	logger     hclog.Logger
+}
+
+type PluginReloadTestConfig struct {
+	Plugin    string `hcl:"plugin,optional"`
+	Scope     string `hcl:"scope,optional"`
+	ReloadAll bool   `hcl:"reload_all,optional"`
+}
+
+func (p *PluginReloadTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *PluginReloadTestConfig `hcl:"config,block"`
+	}{
+		Config: &PluginReloadTestConfig{
+			Plugin: "kv",
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	p.config = testConfig.Config
+
+	if p.config.Plugin == "" {
+		return fmt.Errorf("plugin must be set")
+	}
+	return nil
+}
+
+func (p *PluginReloadTest) Target(client *api.Client) vegeta.Target {
+	return vegeta.Target{
+		Method: PluginReloadTestMethod,
+		URL:    client.Address() + p.pathPrefix,
+		Body:   p.body,
+		Header: p.header,
+	}
+}
+
+func (p *PluginReloadTest) Cleanup(client *api.Client) error {
+	p.logger.Trace(cleanupLogMessage(p.mountPath))
+	_, err := client.Logical().Delete("/sys/mounts/" + p.mountPath)
+	if err != nil {
+		return fmt.Errorf("error cleaning up mount: %v", err)
+	}
+	return nil
+}
+
+func (p *PluginReloadTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     PluginReloadTestMethod,
+		pathPrefix: p.pathPrefix,
+	}
+}
+
+func (p *PluginReloadTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	mountPath := mountName
+	p.logger = targetLogger.Named(PluginReloadTestType)
+
+	if topLevelConfig.RandomMounts {
+		mountPath, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	p.logger.Trace(mountLogMessage("secrets", p.config.Plugin, mountPath))
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(mountPath, &api.MountInput{
+			Type: p.config.Plugin,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error mounting %v secrets engine: %v", p.config.Plugin, err)
+	}
+
+	// Only the test's mount is reloaded unless every mount of the plugin
+	// should be
+	data := map[string]interface{}{
+		"mounts": []string{mountPath},
+	}
+	if p.config.ReloadAll {
+		data = map[string]interface{}{
+			"plugin": p.config.Plugin,
+		}
+	}
+	if p.config.Scope != "" {
+		data["scope"] = p.config.Scope
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling plugin reload data: %v", err)
+	}
+
+	return &PluginReloadTest{
+		pathPrefix: "/v1/sys/plugins/reload/backend",
+		mountPath:  mountPath,
+		body:       body,
+		header:     generateHeader(client),
+		config:     p.config,
+		logger:     p.logger,
+	}, nil
+}
+
+func (p *PluginReloadTest) Flags(fs *flag.FlagSet) {}
@@ -53,6 +53,9 @@ type ACLPolicyTest struct {
 	pathLength   int
 	paths        int
 	capabilities []string
+	nameDepth    int
+	nameFanout   int
+	rng          *rand.Rand
 	logger       hclog.Logger
 }
 
@@ -61,6 +64,9 @@ type ACLPolicyTestConfig struct {
 	PathLength   int      `hcl:"path_length,optional"`
 	Paths        int      `hcl:"paths,optional"`
 	Capabilities []string `hcl:"capabilities,optional"`
+	Verify       bool     `hcl:"verify,optional"`
+	NameDepth    int      `hcl:"name_depth,optional"`
+	NameFanout   int      `hcl:"name_fanout,optional"`
 }
 
 func (a *ACLPolicyTest) ParseConfig(body hcl.Body) error {
@@ -72,22 +78,41 @@ func (a *ACLPolicyTest) ParseConfig(body hcl.Body) error {
 			PathLength:   25,
 			Paths:        1,
 			Capabilities: []string{"create", "read", "update", "delete", "list", "sudo"},
+			NameFanout:   10,
 		},
 	}
 
-	diags := gohcl.DecodeBody(body, nil, testConfig)
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
 	if diags.HasErrors() {
 		return fmt.Errorf("error decoding to struct: %v", diags)
 	}
 	a.config = testConfig.Config
+
+	switch {
+	case a.config.NameDepth < 0:
+		return fmt.Errorf("name_depth must not be negative")
+	case a.config.NameFanout < 1:
+		return fmt.Errorf("name_fanout must be at least 1")
+	}
 	return nil
 }
 
+// policyName returns the name of the numbered policy below the test's path.
+// Policies are spread across nameDepth levels of nameFanout directories, so
+// that listing them walks a tree rather than a single flat level.
+func (a *ACLPolicyTest) policyName(policyNum int) string {
+	var name strings.Builder
+	for level, n := 0, policyNum; level < a.nameDepth; level, n = level+1, n/a.nameFanout {
+		name.WriteString("dir-" + strconv.Itoa(n%a.nameFanout) + "/")
+	}
+	return name.String() + "policy-" + strconv.Itoa(policyNum)
+}
+
 func (a *ACLPolicyTest) read(client *api.Client) vegeta.Target {
-	policyNum := int(1 + rand.Int31n(int32(a.policies)))
+	policyNum := int(1 + a.rng.Int31n(int32(a.policies)))
 	return vegeta.Target{
 		Method: ACLPolicyReadMethod,
-		URL:    client.Address() + a.pathPrefix + "/policy-" + strconv.Itoa(policyNum),
+		URL:    client.Address() + a.pathPrefix + "/" + a.policyName(policyNum),
 		Header: a.header,
 	}
 }
@@ -121,7 +146,7 @@ func (a *ACLPolicyTest) draftPolicy(paths int, pathLength int, capabilities []st
 }
 
 func (a *ACLPolicyTest) write(client *api.Client) vegeta.Target {
-	policyNum := int(1 + rand.Int31n(int32(a.policies)))
+	policyNum := int(1 + a.rng.Int31n(int32(a.policies)))
 
 	policy := a.draftPolicy(a.paths, a.pathLength, a.capabilities)
 	body, err := json.Marshal(policy)
@@ -131,7 +156,7 @@ func (a *ACLPolicyTest) write(client *api.Client) vegeta.Target {
 
 	return vegeta.Target{
 		Method: ACLPolicyWriteMethod,
-		URL:    client.Address() + a.pathPrefix + "/policy-" + strconv.Itoa(policyNum),
+		URL:    client.Address() + a.pathPrefix + "/" + a.policyName(policyNum),
 		Body:   body,
 		Header: a.header,
 	}
@@ -167,7 +192,7 @@ func (a *ACLPolicyTest) GetTargetInfo() TargetInfo {
 func (a *ACLPolicyTest) Cleanup(client *api.Client) error {
 	a.logger.Trace("cleaning policies under " + a.pathPrefix)
 	for i := 1; i <= a.policies; i++ {
-		_, err := client.Logical().Delete(strings.TrimPrefix(a.pathPrefix, "/v1") + "/policy-" + strconv.Itoa(i))
+		_, err := client.Logical().Delete(strings.TrimPrefix(a.pathPrefix, "/v1") + "/" + a.policyName(i))
 		if err != nil {
 			return fmt.Errorf("failed to clean up policy (%v): %w", i, err)
 		}
@@ -189,16 +214,8 @@ func (a *ACLPolicyTest) Setup(client *api.Client, mountName string, topLevelConf
 
 	a.logger.Trace("setting up policies under " + policyPath)
 
-	for i := 1; i <= a.config.Policies; i++ {
-		policy := a.draftPolicy(a.config.Paths, a.config.PathLength, a.config.Capabilities)
-		_, err := client.Logical().Write("sys/policies/acl/"+policyPath+"/policy-"+strconv.Itoa(i), policy)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create policy (%v): %w", i, err)
-		}
-	}
-
-	headers := http.Header{"X-Vault-Token": []string{client.Token()}, "X-Vault-Namespace": []string{client.Headers().Get("X-Vault-Namespace")}}
-	return &ACLPolicyTest{
+	headers := generateHeader(client)
+	test := &ACLPolicyTest{
 		pathPrefix:   "/v1/sys/policies/acl/" + policyPath,
 		action:       a.action,
 		header:       headers,
@@ -206,8 +223,67 @@ func (a *ACLPolicyTest) Setup(client *api.Client, mountName string, topLevelConf
 		pathLength:   a.config.PathLength,
 		paths:        a.config.Paths,
 		capabilities: a.config.Capabilities,
+		nameDepth:    a.config.NameDepth,
+		nameFanout:   a.config.NameFanout,
+		rng:          topLevelConfig.Rand,
 		logger:       a.logger,
-	}, nil
+	}
+
+	for i := 1; i <= a.config.Policies; i++ {
+		policy := a.draftPolicy(a.config.Paths, a.config.PathLength, a.config.Capabilities)
+		_, err := client.Logical().Write("sys/policies/acl/"+policyPath+"/"+test.policyName(i), policy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create policy (%v): %w", i, err)
+		}
+	}
+
+	if a.action == "write" && a.config.Verify {
+		return &verifiedACLPolicyTest{ACLPolicyTest: test}, nil
+	}
+	return test, nil
+}
+
+// verifiedACLPolicyTest is an ACL policy write test which reads back every
+// policy it successfully writes
+type verifiedACLPolicyTest struct {
+	*ACLPolicyTest
+}
+
+// Verify reads the policy the result was written to and checks it matches
+// the policy the test writes
+func (v *verifiedACLPolicyTest) Verify(client *http.Client, result *vegeta.Result) (Verified, error) {
+	req, err := http.NewRequest(ACLPolicyReadMethod, result.URL, nil)
+	if err != nil {
+		return Verified{}, err
+	}
+	req.Header = v.header.Clone()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Verified{}, err
+	}
+	defer resp.Body.Close()
+
+	// A policy which can't be found wasn't written as far as the read is
+	// concerned, which is a mismatch rather than a failed check
+	if resp.StatusCode == http.StatusNotFound {
+		return Verified{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Verified{}, fmt.Errorf("unexpected status reading policy: %v", resp.Status)
+	}
+
+	var read struct {
+		Data struct {
+			Policy string `json:"policy"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&read); err != nil {
+		return Verified{}, fmt.Errorf("error decoding policy: %v", err)
+	}
+
+	expected := v.draftPolicy(v.paths, v.pathLength, v.capabilities)
+	return Verified{Matched: read.Data.Policy == expected["policy"]}, nil
 }
 
 func (a *ACLPolicyTest) Flags(fs *flag.FlagSet) {}
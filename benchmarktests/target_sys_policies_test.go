@@ -0,0 +1,18 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import "testing"
+
+func TestACLPolicyTest_PolicyName(t *testing.T) {
+	flat := &ACLPolicyTest{nameFanout: 10}
+	if name := flat.policyName(42); name != "policy-42" {
+		t.Fatalf("expected a flat policy name, got: %v", name)
+	}
+
+	nested := &ACLPolicyTest{nameDepth: 2, nameFanout: 10}
+	if name := nested.policyName(42); name != "dir-2/dir-4/policy-42" {
+		t.Fatalf("expected a nested policy name, got: %v", name)
+	}
+}
@@ -0,0 +1,348 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	"github.com/sethvargo/go-password/password"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	RateLimitQuotaTestType    = "rate_limit_quota"
+	RateLimitQuotaTestMethod  = "GET"
+	LeaseCountQuotaTestType   = "lease_count_quota"
+	LeaseCountQuotaTestMethod = "POST"
+)
+
+func init() {
+	// "Register" these tests to the main test registry
+	TestList[RateLimitQuotaTestType] = func() BenchmarkBuilder { return &RateLimitQuotaTest{} }
+	TestList[LeaseCountQuotaTestType] = func() BenchmarkBuilder { return &LeaseCountQuotaTest{} }
+}
+
+// RateLimited is implemented by tests whose requests are limited by a rate
+// limit quota, so that the rate the quota allows can be compared with the
+// rate of successful requests in the report
+type RateLimited interface {
+	// QuotaRate returns the number of requests per second the quota allows
+	QuotaRate() float64
+}
+
+// LeaseLimited is implemented by tests whose requests create leases limited
+// by a lease count quota, so that the number of leases the quota allows can
+// be compared with the number of successful requests in the report
+type LeaseLimited interface {
+	// QuotaMaxLeases returns the number of leases the quota allows
+	QuotaMaxLeases() int
+}
+
+// RateLimitQuotaTest reads a secret from a mount limited by a rate limit
+// quota, to measure how closely the quota is enforced when it is exceeded
+type RateLimitQuotaTest struct {
+	pathPrefix string
+	header     http.Header
+	config     *RateLimitQuotaTestConfig
+	logger     hclog.Logger
+
+	// quota is the name of the quota and mount is the mount it limits
+	quota string
+	mount string
+	rate  float64
+}
+
+var _ RateLimited = (*RateLimitQuotaTest)(nil)
+
+type RateLimitQuotaTestConfig struct {
+	Rate          float64 `hcl:"rate,optional"`
+	Interval      string  `hcl:"interval,optional"`
+	BlockInterval string  `hcl:"block_interval,optional"`
+}
+
+func (r *RateLimitQuotaTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *RateLimitQuotaTestConfig `hcl:"config,block"`
+	}{
+		Config: &RateLimitQuotaTestConfig{
+			Rate:     100,
+			Interval: "1s",
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	r.config = testConfig.Config
+
+	if r.config.Rate <= 0 {
+		return fmt.Errorf("rate must be greater than 0")
+	}
+	interval, err := time.ParseDuration(r.config.Interval)
+	if err != nil {
+		return fmt.Errorf("error parsing interval: %v", err)
+	}
+	if interval < time.Second {
+		return fmt.Errorf("interval must be at least 1s")
+	}
+	if r.config.BlockInterval != "" {
+		if _, err := time.ParseDuration(r.config.BlockInterval); err != nil {
+			return fmt.Errorf("error parsing block_interval: %v", err)
+		}
+	}
+	return nil
+}
+
+func (r *RateLimitQuotaTest) Target(client *api.Client) vegeta.Target {
+	return vegeta.Target{
+		Method: RateLimitQuotaTestMethod,
+		URL:    client.Address() + r.pathPrefix,
+		Header: r.header,
+	}
+}
+
+func (r *RateLimitQuotaTest) Cleanup(client *api.Client) error {
+	r.logger.Trace("deleting rate limit quota " + r.quota)
+	var errs []error
+	if _, err := client.Logical().Delete("sys/quotas/rate-limit/" + r.quota); err != nil {
+		errs = append(errs, fmt.Errorf("error deleting rate limit quota: %v", err))
+	}
+	r.logger.Trace(cleanupLogMessage("/v1/" + r.mount))
+	if _, err := client.Logical().Delete("sys/mounts/" + r.mount); err != nil {
+		errs = append(errs, fmt.Errorf("error cleaning up mount: %v", err))
+	}
+	return errors.Join(errs...)
+}
+
+func (r *RateLimitQuotaTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     RateLimitQuotaTestMethod,
+		pathPrefix: r.pathPrefix,
+	}
+}
+
+// QuotaRate returns the number of requests per second the quota allows
+func (r *RateLimitQuotaTest) QuotaRate() float64 {
+	return r.rate
+}
+
+func (r *RateLimitQuotaTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	mountPath := mountName
+	r.logger = targetLogger.Named(RateLimitQuotaTestType)
+
+	if topLevelConfig.RandomMounts {
+		mountPath, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	r.logger.Trace(mountLogMessage("secrets", "kvv1", mountPath))
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().Mount(mountPath, &api.MountInput{
+			Type: "kv",
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error mounting kv secrets engine: %v", err)
+	}
+
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write(mountPath+"/secret", map[string]interface{}{
+			"foo": "bar",
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error writing kv secret: %v", err)
+	}
+
+	quota := map[string]interface{}{
+		"path":     mountPath,
+		"rate":     r.config.Rate,
+		"interval": r.config.Interval,
+	}
+	if r.config.BlockInterval != "" {
+		quota["block_interval"] = r.config.BlockInterval
+	}
+	r.logger.Trace("creating rate limit quota " + mountPath)
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write("sys/quotas/rate-limit/"+mountPath, quota)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating rate limit quota: %v", err)
+	}
+
+	// Both were validated while parsing the config
+	interval, _ := time.ParseDuration(r.config.Interval)
+
+	return &RateLimitQuotaTest{
+		pathPrefix: "/v1/" + mountPath + "/secret",
+		header:     generateHeader(client),
+		config:     r.config,
+		logger:     r.logger,
+		quota:      mountPath,
+		mount:      mountPath,
+		rate:       r.config.Rate / interval.Seconds(),
+	}, nil
+}
+
+func (r *RateLimitQuotaTest) Flags(fs *flag.FlagSet) {}
+
+// LeaseCountQuotaTest logs in to a userpass mount limited by a lease count
+// quota. Every login creates a token which counts towards the quota until the
+// mount is removed during cleanup, so the quota is exhausted after max_leases
+// successful logins.
+type LeaseCountQuotaTest struct {
+	pathPrefix string
+	body       []byte
+	header     http.Header
+	config     *LeaseCountQuotaTestConfig
+	logger     hclog.Logger
+
+	// quota is the name of the quota and mount is the auth mount it limits
+	quota string
+	mount string
+}
+
+var _ LeaseLimited = (*LeaseCountQuotaTest)(nil)
+
+type LeaseCountQuotaTestConfig struct {
+	MaxLeases int `hcl:"max_leases,optional"`
+}
+
+func (l *LeaseCountQuotaTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *LeaseCountQuotaTestConfig `hcl:"config,block"`
+	}{
+		Config: &LeaseCountQuotaTestConfig{
+			MaxLeases: 1000,
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	l.config = testConfig.Config
+
+	if l.config.MaxLeases < 1 {
+		return fmt.Errorf("max_leases must be at least 1")
+	}
+	return nil
+}
+
+func (l *LeaseCountQuotaTest) Target(client *api.Client) vegeta.Target {
+	return vegeta.Target{
+		Method: LeaseCountQuotaTestMethod,
+		URL:    client.Address() + l.pathPrefix,
+		Body:   l.body,
+		Header: l.header,
+	}
+}
+
+// Cleanup deletes the quota and the auth mount, which revokes every token
+// created by the test
+func (l *LeaseCountQuotaTest) Cleanup(client *api.Client) error {
+	l.logger.Trace("deleting lease count quota " + l.quota)
+	var errs []error
+	if _, err := client.Logical().Delete("sys/quotas/lease-count/" + l.quota); err != nil {
+		errs = append(errs, fmt.Errorf("error deleting lease count quota: %v", err))
+	}
+	l.logger.Trace(cleanupLogMessage("/v1/auth/" + l.mount))
+	if err := client.Sys().DisableAuth(l.mount); err != nil {
+		errs = append(errs, fmt.Errorf("error cleaning up mount: %v", err))
+	}
+	return errors.Join(errs...)
+}
+
+func (l *LeaseCountQuotaTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     LeaseCountQuotaTestMethod,
+		pathPrefix: l.pathPrefix,
+	}
+}
+
+// QuotaMaxLeases returns the number of leases the quota allows
+func (l *LeaseCountQuotaTest) QuotaMaxLeases() int {
+	return l.config.MaxLeases
+}
+
+func (l *LeaseCountQuotaTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	authPath := mountName
+	l.logger = targetLogger.Named(LeaseCountQuotaTestType)
+
+	if topLevelConfig.RandomMounts {
+		authPath, err = uuid.GenerateUUID()
+		if err != nil {
+			log.Fatalf("can't create UUID")
+		}
+	}
+
+	l.logger.Trace(mountLogMessage("auth", "userpass", authPath))
+	err = retrySetup(topLevelConfig, func() error {
+		return client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{
+			Type: "userpass",
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error enabling userpass auth: %v", err)
+	}
+
+	pass := password.MustGenerate(64, 10, 0, false, true)
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write(filepath.Join("auth", authPath, "users", "benchmark-user"), map[string]interface{}{
+			"password": pass,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating userpass user: %v", err)
+	}
+
+	l.logger.Trace("creating lease count quota " + authPath)
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.Logical().Write("sys/quotas/lease-count/"+authPath, map[string]interface{}{
+			"path":       "auth/" + authPath,
+			"max_leases": l.config.MaxLeases,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating lease count quota: %v", err)
+	}
+
+	return &LeaseCountQuotaTest{
+		pathPrefix: "/v1/" + filepath.Join("auth", authPath, "login", "benchmark-user"),
+		body:       []byte(fmt.Sprintf(`{"password": "%s"}`, pass)),
+		header:     generateHeader(client),
+		config:     l.config,
+		logger:     l.logger,
+		quota:      authPath,
+		mount:      authPath,
+	}, nil
+}
+
+// ConsumesSetup reports that the leases created count against the quota set
+// up for the test until the mount is removed during cleanup
+func (l *LeaseCountQuotaTest) ConsumesSetup() bool {
+	return true
+}
+
+func (l *LeaseCountQuotaTest) Flags(fs *flag.FlagSet) {}
@@ -0,0 +1,172 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	RaftSnapshotTestType   = "raft_snapshot"
+	RaftSnapshotTestMethod = "GET"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[RaftSnapshotTestType] = func() BenchmarkBuilder { return &RaftSnapshotTest{} }
+}
+
+// RaftSnapshotTest downloads snapshots of integrated storage. The size of the
+// snapshot can be inflated by seeding secrets during setup.
+type RaftSnapshotTest struct {
+	pathPrefix string
+	header     http.Header
+	config     *RaftSnapshotTestConfig
+	logger     hclog.Logger
+
+	// seedMount is the mount secrets were seeded into, if any
+	seedMount string
+}
+
+var _ BodyDiscarder = (*RaftSnapshotTest)(nil)
+
+type RaftSnapshotTestConfig struct {
+	SeedKVs    int `hcl:"seed_kvs,optional"`
+	SeedKVSize int `hcl:"seed_kv_size,optional"`
+}
+
+func (r *RaftSnapshotTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *RaftSnapshotTestConfig `hcl:"config,block"`
+	}{
+		Config: &RaftSnapshotTestConfig{
+			SeedKVs:    0,
+			SeedKVSize: 1024,
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	r.config = testConfig.Config
+
+	if r.config.SeedKVs < 0 {
+		return fmt.Errorf("seed_kvs must not be negative")
+	}
+	if r.config.SeedKVSize < 1 {
+		return fmt.Errorf("seed_kv_size must be at least 1")
+	}
+	return nil
+}
+
+func (r *RaftSnapshotTest) Target(client *api.Client) vegeta.Target {
+	return vegeta.Target{
+		Method: RaftSnapshotTestMethod,
+		URL:    client.Address() + r.pathPrefix,
+		Header: r.header,
+	}
+}
+
+func (r *RaftSnapshotTest) Cleanup(client *api.Client) error {
+	if r.seedMount == "" {
+		return nil
+	}
+	r.logger.Trace(cleanupLogMessage("/v1/" + r.seedMount))
+	_, err := client.Logical().Delete("/sys/mounts/" + r.seedMount)
+	if err != nil {
+		return fmt.Errorf("error cleaning up mount: %v", err)
+	}
+	return nil
+}
+
+func (r *RaftSnapshotTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     RaftSnapshotTestMethod,
+		pathPrefix: r.pathPrefix,
+	}
+}
+
+func (r *RaftSnapshotTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var err error
+	mountPath := mountName
+	r.logger = targetLogger.Named(RaftSnapshotTestType)
+
+	// Snapshots are only taken of integrated storage, which can only be
+	// managed from the root namespace
+	err = retrySetup(topLevelConfig, func() error {
+		_, err := client.WithNamespace("").Logical().Read("sys/storage/raft/configuration")
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading raft configuration, is integrated storage in use: %v", err)
+	}
+
+	var seedMount string
+	if r.config.SeedKVs > 0 {
+		if topLevelConfig.RandomMounts {
+			mountPath, err = uuid.GenerateUUID()
+			if err != nil {
+				log.Fatalf("can't create UUID")
+			}
+		}
+
+		r.logger.Trace(mountLogMessage("secrets", "kvv1", mountPath))
+		err = retrySetup(topLevelConfig, func() error {
+			return client.Sys().Mount(mountPath, &api.MountInput{
+				Type: "kv",
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error mounting kv secrets engine: %v", err)
+		}
+		seedMount = mountPath
+
+		secval := map[string]interface{}{
+			"foo": strings.Repeat("a", r.config.SeedKVSize),
+		}
+		r.logger.Named(mountPath).Trace("seeding secrets", "count", r.config.SeedKVs, "size", r.config.SeedKVSize)
+		for i := 1; i <= r.config.SeedKVs; i++ {
+			err = retrySetup(topLevelConfig, func() error {
+				_, err := client.Logical().Write(mountPath+"/secret-"+strconv.Itoa(i), secval)
+				return err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error writing kv secret: %v", err)
+			}
+		}
+	}
+
+	// Integrated storage can only be managed from the root namespace
+	header := generateHeader(client)
+	header.Set("X-Vault-Namespace", "root")
+
+	return &RaftSnapshotTest{
+		pathPrefix: "/v1/sys/storage/raft/snapshot",
+		header:     header,
+		config:     r.config,
+		logger:     r.logger,
+		seedMount:  seedMount,
+	}, nil
+}
+
+// DiscardsBody reports that snapshots are discarded as they are downloaded,
+// rather than held in memory with every result
+func (r *RaftSnapshotTest) DiscardsBody() bool {
+	return true
+}
+
+func (r *RaftSnapshotTest) Flags(fs *flag.FlagSet) {}
@@ -0,0 +1,119 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	BarrierRotateTestType   = "barrier_rotate"
+	BarrierRotateTestMethod = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[BarrierRotateTestType] = func() BenchmarkBuilder { return &BarrierRotateTest{} }
+}
+
+var _ AttackFinisher = (*BarrierRotateTest)(nil)
+
+// BarrierRotateTest rotates the barrier encryption key with sys/rotate. Each
+// request is a whole rotation, so the latency of a request is the time taken
+// to complete one.
+type BarrierRotateTest struct {
+	pathPrefix string
+	header     http.Header
+	logger     hclog.Logger
+
+	// client is the client the test was set up with, in the root namespace,
+	// which reads the key status once an attack has finished
+	client *api.Client
+
+	// startTerm is the key term before the test, so that the number of
+	// rotations made can be logged
+	startTerm int
+}
+
+// ParseConfig is a no-op as this test has no configuration
+func (b *BarrierRotateTest) ParseConfig(body hcl.Body) error {
+	return nil
+}
+
+func (b *BarrierRotateTest) Target(client *api.Client) vegeta.Target {
+	return vegeta.Target{
+		Method: BarrierRotateTestMethod,
+		URL:    client.Address() + b.pathPrefix,
+		Header: b.header,
+	}
+}
+
+// AttackFinished logs the number of rotations made so far, as each one adds
+// a key term to the keyring, whether or not the test is cleaned up
+func (b *BarrierRotateTest) AttackFinished() {
+	b.logRotations(b.client)
+}
+
+// Cleanup is a no-op for this test, as rotations can't be undone. It logs the
+// number of rotations made.
+func (b *BarrierRotateTest) Cleanup(client *api.Client) error {
+	b.logRotations(client.WithNamespace(""))
+	return nil
+}
+
+// logRotations logs the number of rotations made since the test was set up
+func (b *BarrierRotateTest) logRotations(client *api.Client) {
+	status, err := client.Sys().KeyStatus()
+	if err != nil {
+		b.logger.Warn("error reading key status", "error", err)
+		return
+	}
+	b.logger.Info("rotated barrier key", "rotations", status.Term-b.startTerm, "term", status.Term)
+}
+
+func (b *BarrierRotateTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     BarrierRotateTestMethod,
+		pathPrefix: b.pathPrefix,
+	}
+}
+
+func (b *BarrierRotateTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	b.logger = targetLogger.Named(BarrierRotateTestType)
+
+	// The key status is read both to check that the token may manage the
+	// barrier and to count the rotations made during the test
+	rootClient := client.WithNamespace("")
+	var status *api.KeyStatus
+	err := retrySetup(topLevelConfig, func() error {
+		var err error
+		status, err = rootClient.Sys().KeyStatus()
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading key status: %v", err)
+	}
+	b.logger.Trace("barrier key status", "term", status.Term, "install_time", status.InstallTime)
+
+	// The barrier key can only be rotated from the root namespace
+	header := generateHeader(client)
+	header.Set("X-Vault-Namespace", "root")
+
+	return &BarrierRotateTest{
+		pathPrefix: "/v1/sys/rotate",
+		header:     header,
+		logger:     b.logger,
+		client:     rootClient,
+		startTerm:  status.Term,
+	}, nil
+}
+
+func (b *BarrierRotateTest) Flags(fs *flag.FlagSet) {}
@@ -5,18 +5,23 @@ package benchmarktests
 
 import (
 	"flag"
+	"fmt"
 	"net/http"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/openbao/openbao/api/v2"
 	vegeta "github.com/tsenart/vegeta/v12/lib"
 )
 
 const (
-	HAStatusTestType   = "ha_status"
-	SealStatusTestType = "seal_status"
-	MetricsTestType    = "metrics"
-	StatusTestMethod   = "GET"
+	HAStatusTestType          = "ha_status"
+	SealStatusTestType        = "seal_status"
+	MetricsTestType           = "metrics"
+	RaftConfigurationTestType = "raft_configuration"
+	PluginCatalogTestType     = "plugin_catalog"
+	VersionHistoryTestType    = "version_history"
+	StatusTestMethod          = "GET"
 )
 
 func init() {
@@ -24,35 +29,92 @@ func init() {
 	TestList[HAStatusTestType] = func() BenchmarkBuilder { return &StatusCheck{pathPrefix: "ha-status"} }
 	TestList[SealStatusTestType] = func() BenchmarkBuilder { return &StatusCheck{pathPrefix: "seal-status"} }
 	TestList[MetricsTestType] = func() BenchmarkBuilder { return &StatusCheck{pathPrefix: "metrics"} }
+	TestList[RaftConfigurationTestType] = func() BenchmarkBuilder { return &StatusCheck{pathPrefix: "storage/raft/configuration"} }
+	TestList[PluginCatalogTestType] = func() BenchmarkBuilder { return &StatusCheck{pathPrefix: "plugins/catalog"} }
+	TestList[VersionHistoryTestType] = func() BenchmarkBuilder { return &StatusCheck{pathPrefix: "version-history"} }
 }
 
 type StatusCheck struct {
 	pathPrefix string
 	header     http.Header
+	config     *MetricsTestConfig
+
+	// query is appended to the request URL, and is not part of the path
+	query string
+}
+
+// MetricsTestConfig is the configuration of the metrics test. The other
+// status tests have no configuration.
+type MetricsTestConfig struct {
+	Format string `hcl:"format,optional"`
 }
 
 func (s *StatusCheck) ParseConfig(body hcl.Body) error {
+	if s.pathPrefix != "metrics" {
+		return nil
+	}
+
+	testConfig := &struct {
+		Config *MetricsTestConfig `hcl:"config,block"`
+	}{
+		Config: &MetricsTestConfig{
+			Format: "json",
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	s.config = testConfig.Config
+
+	switch s.config.Format {
+	case "json", "prometheus":
+	default:
+		return fmt.Errorf("format must be one of json or prometheus")
+	}
 	return nil
 }
 
 func (s *StatusCheck) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
 	var h http.Header
+	var query string
 	switch s.pathPrefix {
 	case "metrics":
-		h = http.Header{"X-Vault-Token": []string{client.Token()}, "X-Vault-Namespace": []string{"root"}}
+		h = generateHeader(client)
+		h.Set("X-Vault-Namespace", "root")
+		if s.config != nil && s.config.Format != "json" {
+			query = "?format=" + s.config.Format
+		}
+	case "storage/raft/configuration":
+		// Integrated storage can only be managed from the root namespace
+		h = generateHeader(client)
+		h.Set("X-Vault-Namespace", "root")
+	case "plugins/catalog":
+		// The plugin catalog is only served from the root namespace
+		h = generateHeader(client)
+		h.Set("X-Vault-Namespace", "root")
+	case "version-history":
+		// The version history is listed, and only served from the root
+		// namespace
+		h = generateHeader(client)
+		h.Set("X-Vault-Namespace", "root")
+		query = "?list=true"
 	default:
 		h = generateHeader(client)
 	}
 	return &StatusCheck{
 		header:     h,
 		pathPrefix: "/v1/sys/" + s.pathPrefix,
+		config:     s.config,
+		query:      query,
 	}, nil
 }
 
 func (s *StatusCheck) Target(client *api.Client) vegeta.Target {
 	return vegeta.Target{
 		Method: StatusTestMethod,
-		URL:    client.Address() + s.pathPrefix,
+		URL:    client.Address() + s.pathPrefix + s.query,
 		Header: s.header,
 	}
 }
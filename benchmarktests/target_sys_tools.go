@@ -0,0 +1,145 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	SysToolsHashTestType   = "sys_tools_hash"
+	SysToolsRandomTestType = "sys_tools_random"
+	SysToolsTestMethod     = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[SysToolsHashTestType] = func() BenchmarkBuilder { return &SysToolsTest{action: "hash"} }
+	TestList[SysToolsRandomTestType] = func() BenchmarkBuilder { return &SysToolsTest{action: "random"} }
+}
+
+type SysToolsTest struct {
+	action     string
+	pathPrefix string
+	body       []byte
+	header     http.Header
+	config     *SysToolsTestConfig
+	logger     hclog.Logger
+}
+
+type SysToolsTestConfig struct {
+	Algorithm string `hcl:"algorithm,optional"`
+	InputSize int    `hcl:"input_size,optional"`
+	NumBytes  int    `hcl:"num_bytes,optional"`
+	Format    string `hcl:"format,optional"`
+}
+
+func (s *SysToolsTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *SysToolsTestConfig `hcl:"config,block"`
+	}{
+		Config: &SysToolsTestConfig{
+			Algorithm: "sha2-256",
+			InputSize: 128,
+			NumBytes:  32,
+		},
+	}
+
+	// The endpoints encode their output differently by default
+	switch s.action {
+	case "hash":
+		testConfig.Config.Format = "hex"
+	default:
+		testConfig.Config.Format = "base64"
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	s.config = testConfig.Config
+
+	switch {
+	case s.config.InputSize < 1:
+		return fmt.Errorf("input_size must be at least 1")
+	case s.config.NumBytes < 1:
+		return fmt.Errorf("num_bytes must be at least 1")
+	}
+	switch s.config.Format {
+	case "base64", "hex":
+	default:
+		return fmt.Errorf("format must be one of base64 or hex")
+	}
+	return nil
+}
+
+func (s *SysToolsTest) Target(client *api.Client) vegeta.Target {
+	return vegeta.Target{
+		Method: SysToolsTestMethod,
+		URL:    client.Address() + s.pathPrefix,
+		Body:   s.body,
+		Header: s.header,
+	}
+}
+
+// Cleanup is a no-op for this test
+func (s *SysToolsTest) Cleanup(client *api.Client) error {
+	return nil
+}
+
+func (s *SysToolsTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     SysToolsTestMethod,
+		pathPrefix: s.pathPrefix,
+	}
+}
+
+func (s *SysToolsTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	var path string
+	data := map[string]interface{}{
+		"format": s.config.Format,
+	}
+
+	switch s.action {
+	case "hash":
+		s.logger = targetLogger.Named(SysToolsHashTestType)
+		s.logger.Trace("generating test input", "size", s.config.InputSize)
+		input, err := uuid.GenerateRandomBytes(s.config.InputSize)
+		if err != nil {
+			return nil, fmt.Errorf("error generating random input: %v", err)
+		}
+		data["input"] = base64.StdEncoding.EncodeToString(input)
+		path = "/v1/sys/tools/hash/" + s.config.Algorithm
+	default:
+		s.logger = targetLogger.Named(SysToolsRandomTestType)
+		path = "/v1/sys/tools/random/" + strconv.Itoa(s.config.NumBytes)
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling sys tools data: %v", err)
+	}
+
+	return &SysToolsTest{
+		action:     s.action,
+		pathPrefix: path,
+		header:     generateHeader(client),
+		body:       body,
+		logger:     s.logger,
+	}, nil
+}
+
+func (s *SysToolsTest) Flags(fs *flag.FlagSet) {}
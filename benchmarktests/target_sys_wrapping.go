@@ -0,0 +1,194 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+const (
+	WrappingWrapTestType   = "wrapping_wrap"
+	WrappingUnwrapTestType = "wrapping_unwrap"
+	WrappingTestMethod     = "POST"
+)
+
+func init() {
+	// "Register" this test to the main test registry
+	TestList[WrappingWrapTestType] = func() BenchmarkBuilder { return &WrappingTest{action: "wrap"} }
+	TestList[WrappingUnwrapTestType] = func() BenchmarkBuilder { return &WrappingTest{action: "unwrap"} }
+}
+
+type WrappingTest struct {
+	action     string
+	pathPrefix string
+	body       []byte
+	header     http.Header
+	config     *WrappingTestConfig
+	logger     hclog.Logger
+
+	// tokens is the pool of wrapping tokens created during setup. Tokens
+	// can only be unwrapped once, so they are consumed in order, tracked by
+	// next.
+	tokens      []string
+	next        atomic.Int64
+	exhaustOnce sync.Once
+}
+
+type WrappingTestConfig struct {
+	Payload   map[string]string `hcl:"payload,optional"`
+	WrapTTL   string            `hcl:"wrap_ttl,optional"`
+	NumTokens int               `hcl:"num_tokens,optional"`
+}
+
+func (w *WrappingTest) ParseConfig(body hcl.Body) error {
+	testConfig := &struct {
+		Config *WrappingTestConfig `hcl:"config,block"`
+	}{
+		Config: &WrappingTestConfig{
+			Payload:   map[string]string{"foo": "bar"},
+			WrapTTL:   "1h",
+			NumTokens: 1000,
+		},
+	}
+
+	diags := gohcl.DecodeBody(body, EvalContext(), testConfig)
+	if diags.HasErrors() {
+		return fmt.Errorf("error decoding to struct: %v", diags)
+	}
+	w.config = testConfig.Config
+
+	if _, err := time.ParseDuration(w.config.WrapTTL); err != nil {
+		return fmt.Errorf("error parsing wrap_ttl: %v", err)
+	}
+	if w.config.NumTokens < 1 {
+		return fmt.Errorf("num_tokens must be at least 1")
+	}
+	return nil
+}
+
+func (w *WrappingTest) Target(client *api.Client) vegeta.Target {
+	body := w.body
+	if w.action == "unwrap" {
+		// Each request unwraps the next token in the pool. Once all have
+		// been unwrapped, requests unwrap tokens which were already used.
+		i := w.next.Add(1) - 1
+		if i >= int64(len(w.tokens)) {
+			w.exhaustOnce.Do(func() {
+				w.logger.Warn("all wrapping tokens have been unwrapped, increase num_tokens to unwrap valid tokens for the whole test")
+			})
+		}
+
+		var err error
+		body, err = json.Marshal(map[string]string{
+			"token": w.tokens[i%int64(len(w.tokens))],
+		})
+		if err != nil {
+			w.logger.Error("error marshaling unwrap data", "error", err)
+		}
+	}
+
+	return vegeta.Target{
+		Method: WrappingTestMethod,
+		URL:    client.Address() + w.pathPrefix,
+		Body:   body,
+		Header: w.header,
+	}
+}
+
+// Cleanup is a no-op for this test, as wrapping tokens expire on their own
+func (w *WrappingTest) Cleanup(client *api.Client) error {
+	return nil
+}
+
+func (w *WrappingTest) GetTargetInfo() TargetInfo {
+	return TargetInfo{
+		method:     WrappingTestMethod,
+		pathPrefix: w.pathPrefix,
+	}
+}
+
+func (w *WrappingTest) Setup(client *api.Client, mountName string, topLevelConfig *TopLevelTargetConfig) (BenchmarkBuilder, error) {
+	w.logger = targetLogger.Named("wrapping_" + w.action)
+
+	body, err := json.Marshal(w.config.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling wrapping payload: %v", err)
+	}
+
+	header := generateHeader(client)
+	var tokens []string
+	switch w.action {
+	case "wrap":
+		header.Set("X-Vault-Wrap-TTL", w.config.WrapTTL)
+	case "unwrap":
+		w.logger.Trace("wrapping tokens", "count", w.config.NumTokens)
+		tokens = make([]string, 0, w.config.NumTokens)
+		for i := 0; i < w.config.NumTokens; i++ {
+			var token string
+			err = retrySetup(topLevelConfig, func() error {
+				var err error
+				token, err = w.wrap(client)
+				return err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error wrapping payload: %v", err)
+			}
+			tokens = append(tokens, token)
+		}
+	}
+
+	return &WrappingTest{
+		action:     w.action,
+		pathPrefix: "/v1/sys/wrapping/" + w.action,
+		body:       body,
+		header:     header,
+		config:     w.config,
+		logger:     w.logger,
+		tokens:     tokens,
+	}, nil
+}
+
+// wrap wraps the configured payload, returning the wrapping token
+func (w *WrappingTest) wrap(client *api.Client) (string, error) {
+	wrapClient, err := client.CloneWithHeaders()
+	if err != nil {
+		return "", err
+	}
+	wrapClient.SetToken(client.Token())
+	wrapClient.SetWrappingLookupFunc(func(operation, path string) string {
+		return w.config.WrapTTL
+	})
+
+	payload := make(map[string]interface{}, len(w.config.Payload))
+	for k, v := range w.config.Payload {
+		payload[k] = v
+	}
+	secret, err := wrapClient.Logical().Write("sys/wrapping/wrap", payload)
+	if err != nil {
+		return "", err
+	}
+	if secret == nil || secret.WrapInfo == nil {
+		return "", fmt.Errorf("no wrapping token returned")
+	}
+	return secret.WrapInfo.Token, nil
+}
+
+// ConsumesSetup reports that unwraps use up the tokens wrapped during setup
+func (w *WrappingTest) ConsumesSetup() bool {
+	return w.action == "unwrap"
+}
+
+func (w *WrappingTest) Flags(fs *flag.FlagSet) {}
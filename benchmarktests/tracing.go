@@ -0,0 +1,84 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer emits spans for the setup, attack and cleanup phases of each test.
+// Spans are discarded unless a tracer provider has been registered with otel.
+var tracer = otel.Tracer("github.com/openbao/benchmark-openbao/benchmarktests")
+
+// spanStringFields are the string configuration fields whose values are
+// exported with spans. Strings may hold credentials, such as bind passwords,
+// connection URLs or key material, so any string field not listed here is
+// redacted, including fields added to tests later.
+var spanStringFields = map[string]bool{
+	"by":                  true,
+	"common_name":         true,
+	"delete_mode":         true,
+	"format":              true,
+	"hash_algorithm":      true,
+	"key_type":            true,
+	"lookup":              true,
+	"max_ttl":             true,
+	"mount_type":          true,
+	"plugin":              true,
+	"setup_delay":         true,
+	"signature_algorithm": true,
+	"token_ttl":           true,
+	"token_type":          true,
+	"ttl":                 true,
+	"type":                true,
+}
+
+// spanFieldValue returns the value of a configuration field to export with
+// spans. Numbers and booleans can't hold credentials and are exported as
+// they are, as are the strings of spanStringFields. Every other value is
+// redacted.
+func spanFieldValue(field ConfigField) string {
+	switch field.Type {
+	case "bool", "int", "int64", "uint", "uint64", "float64":
+		return field.Default
+	case "string":
+		name := field.Name[strings.LastIndex(field.Name, ".")+1:]
+		if spanStringFields[name] {
+			return field.Default
+		}
+	}
+	return "(redacted)"
+}
+
+// targetAttributes returns the span attributes describing a target, including
+// its parsed configuration
+func targetAttributes(bt *BenchmarkTarget) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("test.name", bt.Name),
+		attribute.String("test.type", bt.Type),
+		attribute.Int("test.weight", bt.Weight),
+	}
+	if bt.Builder != nil {
+		for _, field := range builderConfigFields(bt.Builder) {
+			if field.Default != "" {
+				attrs = append(attrs, attribute.String("test."+field.Name, spanFieldValue(field)))
+			}
+		}
+	}
+	return attrs
+}
+
+// endSpan records err on span, if set, and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
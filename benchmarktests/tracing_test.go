@@ -0,0 +1,23 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import "testing"
+
+func TestSpanFieldValue(t *testing.T) {
+	for _, tc := range []struct {
+		field    ConfigField
+		expected string
+	}{
+		{ConfigField{Name: "config.num_keys", Type: "int", Default: "10"}, "10"},
+		{ConfigField{Name: "config.keys.key_type", Type: "string", Default: "rsa-2048"}, "rsa-2048"},
+		{ConfigField{Name: "config.bindpass", Type: "string", Default: "hunter2"}, "(redacted)"},
+		{ConfigField{Name: "config.db.connection_url", Type: "string", Default: "postgres://u:p@db"}, "(redacted)"},
+		{ConfigField{Name: "config.pem_keys", Type: "[]string", Default: "[key]"}, "(redacted)"},
+	} {
+		if value := spanFieldValue(tc.field); value != tc.expected {
+			t.Fatalf("expected %v to be exported as %q, got %q", tc.field.Name, tc.expected, value)
+		}
+	}
+}
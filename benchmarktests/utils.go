@@ -5,6 +5,7 @@ package benchmarktests
 
 import (
 	"bytes"
+	"context"
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
@@ -15,10 +16,12 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	mathrand "math/rand"
 	"net"
 	"net/http"
 	"os"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/openbao/openbao/api/v2"
@@ -28,6 +31,10 @@ import (
 
 var (
 	ErrIsDirectory = errors.New("location is a directory, not a file")
+
+	// errUnsupported is wrapped by setup errors of features which setup
+	// checks found the server doesn't support
+	errUnsupported = errors.New("server does not support this feature")
 )
 
 func omitEmpty(in interface{}) {
@@ -201,11 +208,111 @@ func serialNumber() (*big.Int, error) {
 	return rand.Int(rand.Reader, (&big.Int{}).Exp(big.NewInt(2), big.NewInt(159), nil))
 }
 
+// generateHeader returns the headers sent with a test's requests: the
+// client's token and namespace, along with any other headers set on the
+// client, such as those configured with the test's headers option
 func generateHeader(client *api.Client) http.Header {
-	return http.Header{
-		"X-Vault-Token":     []string{client.Token()},
-		"X-Vault-Namespace": []string{client.Headers().Get("X-Vault-Namespace")},
+	header := client.Headers()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Set("X-Vault-Token", client.Token())
+	header.Set("X-Vault-Namespace", header.Get("X-Vault-Namespace"))
+	return header
+}
+
+// retrySetup runs fn, retrying errors which are likely to be transient, such
+// as those returned by a busy or freshly unsealed server, with exponential
+// backoff and jitter. fn is retried at most config.SetupRetries times, so a
+// value of 0 or less disables retries. Once setup is interrupted fn isn't run
+// again and an error is returned instead.
+func retrySetup(config *TopLevelTargetConfig, fn func() error) error {
+	ctx := config.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		if ctx.Err() != nil {
+			return fmt.Errorf("setup interrupted: %w", ctx.Err())
+		}
+		err = fn()
+		if err == nil || attempt >= config.SetupRetries || !isTransientError(err) {
+			return err
+		}
+
+		delay := setupRetryDelay(attempt)
+		targetLogger.Debug("retrying setup request", "attempt", attempt+1, "delay", delay.String(), "error", err.Error())
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+	}
+}
+
+// setupRetryDelay returns the backoff delay before retrying the given attempt,
+// somewhere between half and all of an exponentially growing delay. The delay
+// stops doubling once it reaches its maximum, so large attempt numbers can't
+// overflow it.
+func setupRetryDelay(attempt int) time.Duration {
+	const (
+		baseDelay = 100 * time.Millisecond
+		maxDelay  = 5 * time.Second
+	)
+
+	delay := baseDelay
+	for i := 0; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxDelay)
+	return delay/2 + time.Duration(mathrand.Int63n(int64(delay/2)+1))
+}
+
+// isTransientError returns true if err is a response error whose status code
+// indicates the request may succeed if retried
+func isTransientError(err error) bool {
+	var respErr *api.ResponseError
+	if !errors.As(err, &respErr) {
+		return false
+	}
+	switch respErr.StatusCode {
+	case http.StatusPreconditionFailed, http.StatusTooManyRequests:
+		return true
+	default:
+		return respErr.StatusCode >= 500
+	}
+}
+
+// unsupportedMessages are returned by the server for paths which don't exist
+// in its build, such as enterprise only features
+var unsupportedMessages = []string{
+	"unsupported path",
+	"no handler for route",
+}
+
+// isUnsupported returns true if err is from a setup check which found the
+// server doesn't support the test. Other errors, such as those of requests to
+// a mistyped path, aren't treated as unsupported even if the server responds
+// the same way, so that they still fail the run.
+func isUnsupported(err error) bool {
+	return errors.Is(err, errUnsupported)
+}
+
+// probeError returns the error of a request made to a path every server
+// supporting feature serves, to check for the feature while setting up a test
+// which needs it. The error wraps errUnsupported if the server doesn't serve
+// the path.
+func probeError(feature string, err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, unsupported := range unsupportedMessages {
+		if strings.Contains(err.Error(), unsupported) {
+			return fmt.Errorf("error checking for %v: %w: %v", feature, errUnsupported, err)
+		}
 	}
+	return fmt.Errorf("error checking for %v: %v", feature, err)
 }
 
 func IsFile(path string) (bool, error) {
@@ -0,0 +1,155 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/openbao/openbao/api/v2"
+)
+
+func TestRetrySetup(t *testing.T) {
+	config := &TopLevelTargetConfig{SetupRetries: 2}
+
+	// Transient errors are retried until the call succeeds
+	var calls int
+	err := retrySetup(config, func() error {
+		calls++
+		if calls < 3 {
+			return &api.ResponseError{StatusCode: http.StatusServiceUnavailable}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got: %d", calls)
+	}
+
+	// Retries stop once the limit is reached
+	calls = 0
+	err = retrySetup(config, func() error {
+		calls++
+		return &api.ResponseError{StatusCode: http.StatusPreconditionFailed}
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got: %d", calls)
+	}
+
+	// Other errors are returned immediately
+	calls = 0
+	err = retrySetup(config, func() error {
+		calls++
+		return errors.New("permission denied")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got: %d", calls)
+	}
+}
+
+func TestRetrySetup_Disabled(t *testing.T) {
+	config := &TopLevelTargetConfig{SetupRetries: 0}
+
+	var calls int
+	err := retrySetup(config, func() error {
+		calls++
+		return &api.ResponseError{StatusCode: http.StatusServiceUnavailable}
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got: %d", calls)
+	}
+}
+
+func TestRetrySetup_Interrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	config := &TopLevelTargetConfig{SetupRetries: 2, ctx: ctx}
+
+	var calls int
+	err := retrySetup(config, func() error {
+		calls++
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected setup to be interrupted, got: %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("expected no calls, got: %d", calls)
+	}
+}
+
+func TestSetupRetryDelay(t *testing.T) {
+	// Delays grow with each attempt but are capped, however many attempts
+	// are made
+	for _, attempt := range []int{0, 1, 10, 63, 64, 1000} {
+		delay := setupRetryDelay(attempt)
+		if delay <= 0 || delay > 5*time.Second {
+			t.Fatalf("attempt %d: unexpected delay: %v", attempt, delay)
+		}
+	}
+	if delay := setupRetryDelay(1000); delay < 2500*time.Millisecond {
+		t.Fatalf("expected capped delay, got: %v", delay)
+	}
+}
+
+func TestGenerateHeader_ClientHeaders(t *testing.T) {
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	client.SetToken("root")
+
+	client, err = clientWithHeaders(client, map[string]string{"X-Forwarded-For": "10.0.0.1"})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	header := generateHeader(client)
+	if got := header.Get("X-Vault-Token"); got != "root" {
+		t.Fatalf("expected token root, got: %q", got)
+	}
+	if got := header.Get("X-Forwarded-For"); got != "10.0.0.1" {
+		t.Fatalf("expected configured header, got: %q", got)
+	}
+	if _, ok := header["X-Vault-Namespace"]; !ok {
+		t.Fatal("expected namespace header")
+	}
+}
+
+func TestIsUnsupported(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"nil":       {nil, false},
+		"other":     {errors.New("permission denied"), false},
+		"path":      {fmt.Errorf("error reading config: %v", errors.New("Code: 404. Errors:\n\n* 1 error occurred:\n\t* unsupported path\n")), false},
+		"plugin":    {errors.New("error mounting: * plugin not found in the catalog: kmip"), false},
+		"probe":     {probeError("control groups", errors.New("Code: 404. Errors:\n\n* 1 error occurred:\n\t* unsupported path\n")), true},
+		"probed":    {probeError("control groups", errors.New("permission denied")), false},
+		"transient": {&api.ResponseError{StatusCode: http.StatusServiceUnavailable}, false},
+		"sentinel":  {fmt.Errorf("error writing key: %w", fmt.Errorf("%w: unknown key type", errUnsupported)), true},
+		"message":   {errors.New("error writing key: server does not support this feature"), false},
+	}
+	for name, tc := range tests {
+		if actual := isUnsupported(tc.err); actual != tc.expected {
+			t.Fatalf("%s: expected %v, got: %v", name, tc.expected, actual)
+		}
+	}
+}
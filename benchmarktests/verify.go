@@ -0,0 +1,172 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+// Verifier is implemented by tests which check that their successful
+// requests took effect by reading back what they wrote. Verification adds a
+// request for every successful one, so tests should only implement it when
+// it was asked for in their configuration.
+type Verifier interface {
+	// Verify reads back the effect of the successful request which produced
+	// result
+	Verify(client *http.Client, result *vegeta.Result) (Verified, error)
+}
+
+// Verified is the outcome of reading back the effect of a request
+type Verified struct {
+	// Matched is false if what was read doesn't match what was written
+	Matched bool
+
+	// Lag is how long after the request completed its effect became
+	// visible, for tests which wait for it. It is 0 for tests which only
+	// read it back once.
+	Lag time.Duration
+}
+
+// Verification counts the read-back checks made for a target
+type Verification struct {
+	Checked    int64 `json:"checked"`
+	Mismatched int64 `json:"mismatched"`
+	Errors     int64 `json:"errors"`
+
+	// Skipped counts the successful requests which weren't checked because
+	// the backlog of checks was full
+	Skipped int64 `json:"skipped,omitempty"`
+
+	// Lag summarizes how long the effects of requests took to become
+	// visible, for tests which wait for them
+	Lag *vegeta.LatencyMetrics `json:"lag,omitempty"`
+
+	lagSamples int64
+}
+
+// verifyBacklog is the number of checks queued for each of the runner's
+// workers before further checks are skipped
+const verifyBacklog = 100
+
+// verifyJob is a check of a successful request queued for the runner's
+// workers
+type verifyJob struct {
+	target   *BenchmarkTarget
+	verifier Verifier
+	result   *vegeta.Result
+}
+
+// verifyRunner runs the read-back checks of an attack alongside it, on as
+// many workers as the attack has. Checks which wait for their effect to
+// become visible can take much longer than the attack's requests, so they
+// are queued rather than holding up the attack's results, and skipped once
+// the queue is full.
+type verifyRunner struct {
+	client *http.Client
+	jobs   chan verifyJob
+	wg     sync.WaitGroup
+
+	mu            sync.Mutex
+	verifications map[string]*Verification
+}
+
+func newVerifyRunner(client *http.Client, workers int) *verifyRunner {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	v := &verifyRunner{
+		client: client,
+		jobs:   make(chan verifyJob, workers*verifyBacklog),
+	}
+	v.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go v.work()
+	}
+	return v
+}
+
+// run queues a check of the result if it is a successful response from a
+// target which verifies its requests
+func (v *verifyRunner) run(target *BenchmarkTarget, result *vegeta.Result) {
+	if target == nil || result.Code < 200 || result.Code >= 300 {
+		return
+	}
+	verifier, ok := target.Builder.(Verifier)
+	if !ok {
+		return
+	}
+
+	select {
+	case v.jobs <- verifyJob{target: target, verifier: verifier, result: result}:
+	default:
+		v.mu.Lock()
+		defer v.mu.Unlock()
+		v.counts(target.Name).Skipped++
+	}
+}
+
+// work runs queued checks until the runner is waited for
+func (v *verifyRunner) work() {
+	defer v.wg.Done()
+	for job := range v.jobs {
+		verified, err := job.verifier.Verify(v.client, job.result)
+
+		v.mu.Lock()
+		counts := v.counts(job.target.Name)
+		counts.Checked++
+		switch {
+		case err != nil:
+			counts.Errors++
+		case !verified.Matched:
+			counts.Mismatched++
+		}
+		if err == nil && verified.Lag > 0 {
+			if counts.Lag == nil {
+				counts.Lag = &vegeta.LatencyMetrics{}
+			}
+			counts.Lag.Add(verified.Lag)
+			counts.lagSamples++
+		}
+		v.mu.Unlock()
+	}
+}
+
+// counts returns the counts of the target, which the caller must hold mu for
+func (v *verifyRunner) counts(name string) *Verification {
+	if v.verifications == nil {
+		v.verifications = make(map[string]*Verification)
+	}
+	counts, ok := v.verifications[name]
+	if !ok {
+		counts = &Verification{}
+		v.verifications[name] = counts
+	}
+	return counts
+}
+
+// wait waits for the queued checks and returns the counts of each target
+func (v *verifyRunner) wait() map[string]*Verification {
+	close(v.jobs)
+	v.wg.Wait()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, counts := range v.verifications {
+		if counts.Lag == nil {
+			continue
+		}
+		counts.Lag.Mean = counts.Lag.Total / time.Duration(counts.lagSamples)
+		counts.Lag.P50 = counts.Lag.Quantile(0.50)
+		counts.Lag.P90 = counts.Lag.Quantile(0.90)
+		counts.Lag.P95 = counts.Lag.Quantile(0.95)
+		counts.Lag.P99 = counts.Lag.Quantile(0.99)
+	}
+	return v.verifications
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package benchmarktests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+func TestVerifiedACLPolicyTest(t *testing.T) {
+	test := &ACLPolicyTest{paths: 2, pathLength: 10, capabilities: []string{"read"}, header: http.Header{}}
+	policy := test.draftPolicy(test.paths, test.pathLength, test.capabilities)["policy"]
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/sys/policies/acl/policy-1":
+			fmt.Fprintf(w, `{"data":{"policy":%q}}`, policy)
+		case "/v1/sys/policies/acl/policy-2":
+			fmt.Fprint(w, `{"data":{"policy":"path \"other\" {}"}}`)
+		case "/v1/sys/policies/acl/policy-3":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	target := &BenchmarkTarget{Name: "acl_write", Builder: &verifiedACLPolicyTest{ACLPolicyTest: test}}
+	runner := newVerifyRunner(server.Client(), 2)
+	for _, policy := range []string{"policy-1", "policy-2", "policy-3", "policy-4"} {
+		runner.run(target, &vegeta.Result{Code: 204, URL: server.URL + "/v1/sys/policies/acl/" + policy})
+	}
+	// Failed writes aren't verified
+	runner.run(target, &vegeta.Result{Code: 500, URL: server.URL + "/v1/sys/policies/acl/policy-1"})
+
+	got := runner.wait()["acl_write"]
+	expected := Verification{Checked: 4, Mismatched: 2, Errors: 1}
+	if got == nil || *got != expected {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+}
+
+// blockingVerifier blocks every check until it is released
+type blockingVerifier struct {
+	fakeBuilder
+	release chan struct{}
+}
+
+func (b *blockingVerifier) Verify(client *http.Client, result *vegeta.Result) (Verified, error) {
+	<-b.release
+	return Verified{Matched: true}, nil
+}
+
+func TestVerifyRunner_Backlog(t *testing.T) {
+	verifier := &blockingVerifier{release: make(chan struct{})}
+	target := &BenchmarkTarget{Name: "slow", Builder: verifier}
+	runner := newVerifyRunner(nil, 1)
+
+	// Results aren't held up by slow checks. One check is taken by the
+	// worker, and once the backlog is full the rest are skipped.
+	requests := verifyBacklog + 10
+	for i := 0; i < requests; i++ {
+		runner.run(target, &vegeta.Result{Code: 200})
+	}
+	close(verifier.release)
+
+	got := runner.wait()["slow"]
+	if got == nil || got.Checked+got.Skipped != int64(requests) || got.Skipped < 9 || got.Skipped > 10 {
+		t.Fatalf("unexpected counts: %+v", got)
+	}
+}
@@ -0,0 +1,527 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	vbConfig "github.com/openbao/benchmark-openbao/config"
+)
+
+// Distributed runs spread the load of a benchmark across several processes,
+// so that clusters too large for one machine to saturate can be benchmarked.
+// One runner leads, serving the endpoints below: followers join it to be
+// assigned the run's parameters, wait for it to pick a common start time once
+// every runner has set up its tests, and report their latency histograms back
+// to it once they're done so that it can report on the run as a whole.
+const (
+	coordinateJoinPath    = "/v1/join"
+	coordinateReadyPath   = "/v1/ready"
+	coordinateResultsPath = "/v1/results"
+
+	// coordinateStartDelay is how long after the last runner is ready the
+	// attacks start, giving every runner time to receive the start time
+	coordinateStartDelay = 2 * time.Second
+
+	// coordinateJoinTimeout is how long followers keep trying to join a
+	// leader which isn't listening yet
+	coordinateJoinTimeout   = time.Minute
+	coordinateRetryInterval = time.Second
+
+	// coordinateReadyTimeout is how long the leader waits for its followers
+	// to join and set up their tests once its own are set up
+	coordinateReadyTimeout = 10 * time.Minute
+
+	// coordinateResultsTimeout is how long the leader waits for the results
+	// of its followers once its own run has completed
+	coordinateResultsTimeout = 5 * time.Minute
+)
+
+// runParameters are the settings the leader assigns to each follower, which
+// override the follower's own
+type runParameters struct {
+	Runner   int    `json:"runner"`
+	Duration string `json:"duration"`
+	Requests int    `json:"requests"`
+	RPS      int    `json:"rps"`
+	Workers  int    `json:"workers"`
+	Seed     int    `json:"seed"`
+}
+
+func runParametersFromConfig(conf *vbConfig.VaultBenchmarkCoreConfig) runParameters {
+	return runParameters{
+		Duration: conf.Duration,
+		Requests: conf.Requests,
+		RPS:      conf.RPS,
+		Workers:  conf.Workers,
+		Seed:     conf.Seed,
+	}
+}
+
+func (p *runParameters) apply(conf *vbConfig.VaultBenchmarkCoreConfig) {
+	conf.Duration = p.Duration
+	conf.Requests = p.Requests
+	conf.RPS = p.RPS
+	conf.Workers = p.Workers
+	conf.Seed = p.Seed
+}
+
+// coordinateJoined is the leader's response to a follower joining
+type coordinateJoined struct {
+	runParameters
+	Token string `json:"token"`
+}
+
+// coordinateIdentity identifies a follower to the leader once it has joined,
+// with the token the leader assigned it
+type coordinateIdentity struct {
+	Runner int    `json:"runner"`
+	Token  string `json:"token"`
+}
+
+type coordinateStart struct {
+	StartAt time.Time `json:"start_at"`
+}
+
+type coordinateResults struct {
+	coordinateIdentity
+	Histograms map[string]*latencyHistogram `json:"histograms"`
+}
+
+// leader coordinates a distributed run. The leader is runner 0 and its
+// followers are numbered from 1 in the order they join.
+type leader struct {
+	params    runParameters
+	followers int
+	logger    hclog.Logger
+	server    *http.Server
+
+	mu      sync.Mutex
+	joined  int
+	tokens  map[int]string
+	ready   map[int]bool
+	startAt time.Time
+	results map[int]map[string]*latencyHistogram
+
+	// started is closed once every runner is ready, and reported once every
+	// follower has sent its results
+	started  chan struct{}
+	reported chan struct{}
+}
+
+func newLeader(params runParameters, followers int, logger hclog.Logger) *leader {
+	return &leader{
+		params:    params,
+		followers: followers,
+		logger:    logger,
+		tokens:    make(map[int]string),
+		ready:     make(map[int]bool),
+		results:   make(map[int]map[string]*latencyHistogram),
+		started:   make(chan struct{}),
+		reported:  make(chan struct{}),
+	}
+}
+
+func (l *leader) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(coordinateJoinPath, l.handleJoin)
+	mux.HandleFunc(coordinateReadyPath, l.handleReady)
+	mux.HandleFunc(coordinateResultsPath, l.handleResults)
+	return mux
+}
+
+// Start serves followers on addr until Close is called
+func (l *leader) Start(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("error listening on %v: %v", addr, err)
+	}
+	l.server = &http.Server{Handler: l.Handler()}
+	go func() {
+		_ = l.server.Serve(ln)
+	}()
+	return nil
+}
+
+func (l *leader) Close() error {
+	if l.server == nil {
+		return nil
+	}
+	return l.server.Close()
+}
+
+func (l *leader) handleJoin(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token, err := uuid.GenerateUUID()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error generating token: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	l.mu.Lock()
+	if l.joined >= l.followers {
+		l.mu.Unlock()
+		http.Error(w, "all followers have already joined", http.StatusConflict)
+		return
+	}
+	l.joined++
+	l.tokens[l.joined] = token
+	joined := coordinateJoined{runParameters: l.params, Token: token}
+	joined.Runner = l.joined
+	l.mu.Unlock()
+
+	// Every runner generates a different sequence of requests from the
+	// leader's seed
+	joined.Seed += joined.Runner
+	l.logger.Info("follower joined", "runner", joined.Runner, "remote", req.RemoteAddr)
+	writeCoordinateJSON(w, &joined)
+}
+
+// identify checks that a request was sent by a follower which has joined.
+// The leader's lock must be held.
+func (l *leader) identify(id coordinateIdentity) error {
+	token, ok := l.tokens[id.Runner]
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(id.Token)) != 1 {
+		return fmt.Errorf("unknown runner %d", id.Runner)
+	}
+	return nil
+}
+
+func (l *leader) handleReady(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var id coordinateIdentity
+	if err := json.NewDecoder(req.Body).Decode(&id); err != nil {
+		http.Error(w, fmt.Sprintf("error decoding runner: %v", err), http.StatusBadRequest)
+		return
+	}
+	l.mu.Lock()
+	if err := l.identify(id); err != nil {
+		l.mu.Unlock()
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if l.ready[id.Runner] {
+		l.mu.Unlock()
+		http.Error(w, fmt.Sprintf("runner %d is already ready", id.Runner), http.StatusConflict)
+		return
+	}
+	l.markReady(id.Runner)
+	l.mu.Unlock()
+
+	startAt, err := l.waitStart(req.Context())
+	if err != nil {
+		return
+	}
+	writeCoordinateJSON(w, &coordinateStart{StartAt: startAt})
+}
+
+func (l *leader) handleResults(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var results coordinateResults
+	if err := json.NewDecoder(req.Body).Decode(&results); err != nil {
+		http.Error(w, fmt.Sprintf("error decoding results: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.identify(results.coordinateIdentity); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if _, ok := l.results[results.Runner]; ok {
+		http.Error(w, fmt.Sprintf("runner %d already sent its results", results.Runner), http.StatusConflict)
+		return
+	}
+	l.results[results.Runner] = results.Histograms
+	l.logger.Info("follower sent results", "runner", results.Runner)
+	if len(l.results) == l.followers {
+		close(l.reported)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Ready counts the leader as ready to start and waits for every follower to
+// join and be ready, returning the time they all start at. It gives up once
+// the followers have had coordinateReadyTimeout to get ready.
+func (l *leader) Ready(ctx context.Context) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, coordinateReadyTimeout)
+	defer cancel()
+
+	l.mu.Lock()
+	l.markReady(0)
+	l.mu.Unlock()
+
+	startAt, err := l.waitStart(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return time.Time{}, fmt.Errorf("timed out waiting for followers, %d of %d joined and %d ready", l.joined, l.followers, len(l.ready)-1)
+	}
+	return startAt, err
+}
+
+// markReady counts a runner as ready, picking the start time once every
+// runner is. The leader's lock must be held.
+func (l *leader) markReady(runner int) {
+	l.ready[runner] = true
+	if len(l.ready) == l.followers+1 {
+		l.startAt = time.Now().Add(coordinateStartDelay)
+		close(l.started)
+	}
+}
+
+// waitStart waits for every runner to be ready and returns the time they all
+// start at
+func (l *leader) waitStart(ctx context.Context) (time.Time, error) {
+	select {
+	case <-l.started:
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.startAt, nil
+	case <-ctx.Done():
+		return time.Time{}, ctx.Err()
+	}
+}
+
+// Results waits for every follower to send its histograms and returns them.
+// If the context is done first, the results received so far are returned
+// along with the context's error.
+func (l *leader) Results(ctx context.Context) (map[int]map[string]*latencyHistogram, error) {
+	var err error
+	select {
+	case <-l.reported:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	results := make(map[int]map[string]*latencyHistogram, len(l.results))
+	for runner, histograms := range l.results {
+		results[runner] = histograms
+	}
+	return results, err
+}
+
+func writeCoordinateJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// follower takes part in a distributed run led by the runner at addr
+type follower struct {
+	addr   string
+	client *http.Client
+
+	// id identifies the follower once it has joined
+	id coordinateIdentity
+}
+
+// leaderError is returned when the leader rejects a request
+type leaderError struct {
+	status string
+	body   string
+}
+
+func (e *leaderError) Error() string {
+	return fmt.Sprintf("leader responded with %v: %v", e.status, e.body)
+}
+
+func newFollower(addr string) *follower {
+	return &follower{
+		addr:   strings.TrimSuffix(addr, "/"),
+		client: &http.Client{},
+	}
+}
+
+// Join joins the leader, retrying while it can't be reached, and returns the
+// parameters it assigned
+func (f *follower) Join(ctx context.Context) (*runParameters, error) {
+	ctx, cancel := context.WithTimeout(ctx, coordinateJoinTimeout)
+	defer cancel()
+
+	for {
+		var joined coordinateJoined
+		err := f.post(ctx, coordinateJoinPath, struct{}{}, &joined)
+		if err == nil {
+			f.id = coordinateIdentity{Runner: joined.Runner, Token: joined.Token}
+			return &joined.runParameters, nil
+		}
+
+		var leaderErr *leaderError
+		if errors.As(err, &leaderErr) {
+			return nil, fmt.Errorf("error joining leader: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("error joining leader: %v", err)
+		case <-time.After(coordinateRetryInterval):
+		}
+	}
+}
+
+// Ready tells the leader the follower is ready to start, and waits for the
+// leader to return the time every runner starts at
+func (f *follower) Ready(ctx context.Context) (time.Time, error) {
+	var start coordinateStart
+	if err := f.post(ctx, coordinateReadyPath, &f.id, &start); err != nil {
+		return time.Time{}, fmt.Errorf("error waiting for leader to start: %v", err)
+	}
+	return start.StartAt, nil
+}
+
+// SendResults sends the follower's latency histograms to the leader
+func (f *follower) SendResults(ctx context.Context, histograms map[string]*latencyHistogram) error {
+	err := f.post(ctx, coordinateResultsPath, &coordinateResults{
+		coordinateIdentity: f.id,
+		Histograms:         histograms,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("error sending results to leader: %v", err)
+	}
+	return nil
+}
+
+func (f *follower) post(ctx context.Context, path string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.addr+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &leaderError{status: resp.Status, body: strings.TrimSpace(string(msg))}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// waitUntil blocks until t or until the context is done
+func waitUntil(ctx context.Context, t time.Time) error {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// mergeHistograms merges the histograms of several runners by name
+func mergeHistograms(runners ...map[string]*latencyHistogram) map[string]*latencyHistogram {
+	merged := make(map[string]*latencyHistogram)
+	for _, histograms := range runners {
+		for name, histogram := range histograms {
+			if _, ok := merged[name]; !ok {
+				merged[name] = newLatencyHistogram()
+			}
+			merged[name].merge(histogram)
+		}
+	}
+	return merged
+}
+
+// distributedLatencies summarizes a merged histogram in the report of a
+// distributed run
+type distributedLatencies struct {
+	Count uint64        `json:"count"`
+	Min   time.Duration `json:"min"`
+	P50   time.Duration `json:"50th"`
+	P90   time.Duration `json:"90th"`
+	P95   time.Duration `json:"95th"`
+	P99   time.Duration `json:"99th"`
+	Max   time.Duration `json:"max"`
+}
+
+func summarizeHistogram(h *latencyHistogram) *distributedLatencies {
+	return &distributedLatencies{
+		Count: h.count,
+		Min:   time.Duration(h.min) * time.Microsecond,
+		P50:   h.quantile(0.50),
+		P90:   h.quantile(0.90),
+		P95:   h.quantile(0.95),
+		P99:   h.quantile(0.99),
+		Max:   time.Duration(h.max) * time.Microsecond,
+	}
+}
+
+// reportDistributed reports the latencies of every runner's requests
+// together, in the given report mode
+func reportDistributed(w io.Writer, mode string, runners int, histograms map[string]*latencyHistogram) error {
+	latencies := make(map[string]*distributedLatencies, len(histograms))
+	names := make([]string, 0, len(histograms))
+	for name, histogram := range histograms {
+		latencies[name] = summarizeHistogram(histogram)
+		if name != histogramTotal {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	if mode == "json" {
+		return json.NewEncoder(w).Encode(&struct {
+			Type      string                           `json:"type"`
+			Runners   int                              `json:"runners"`
+			Latencies map[string]*distributedLatencies `json:"latencies"`
+		}{
+			Type:      "distributed",
+			Runners:   runners,
+			Latencies: latencies,
+		})
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.StripEscape)
+	fmt.Fprintf(tw, "Distributed results from %d runners:\n", runners)
+	fmt.Fprintf(tw, "op\tcount\tmin\t50th%%\t90th%%\t95th%%\t99th%%\tmax\n")
+	if _, ok := latencies[histogramTotal]; ok {
+		names = append(names, histogramTotal)
+	}
+	for _, name := range names {
+		l := latencies[name]
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n", name, l.Count, l.Min, l.P50, l.P90, l.P95, l.P99, l.Max)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestCoordinate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	l := newLeader(runParameters{Duration: "30s", Workers: 5, Seed: 10}, 1, hclog.NewNullLogger())
+	server := httptest.NewServer(l.Handler())
+	defer server.Close()
+
+	f := newFollower(server.URL)
+	params, err := f.Join(ctx)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if params.Runner != 1 || params.Duration != "30s" || params.Workers != 5 || params.Seed != 11 {
+		t.Fatalf("unexpected parameters: %+v", params)
+	}
+	if _, err := newFollower(server.URL).Join(ctx); err == nil {
+		t.Fatal("expected an error joining once every follower has joined")
+	}
+
+	// Followers are identified by the token they were assigned on joining
+	impostor := newFollower(server.URL)
+	impostor.id = coordinateIdentity{Runner: 1, Token: "guessed"}
+	if _, err := impostor.Ready(ctx); err == nil {
+		t.Fatal("expected an error getting ready with another runner's number")
+	}
+
+	followerStart := make(chan time.Time)
+	go func() {
+		startAt, err := f.Ready(ctx)
+		if err != nil {
+			t.Errorf("err: %v", err)
+		}
+		followerStart <- startAt
+	}()
+	leaderStart, err := l.Ready(ctx)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if startAt := <-followerStart; !startAt.Equal(leaderStart) {
+		t.Fatalf("expected the follower to start at %v, got %v", leaderStart, startAt)
+	}
+
+	followerHistogram := newLatencyHistogram()
+	followerHistogram.add(3 * time.Millisecond)
+	if err := f.SendResults(ctx, map[string]*latencyHistogram{"read": followerHistogram}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	results, err := l.Results(ctx)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	leaderHistogram := newLatencyHistogram()
+	leaderHistogram.add(time.Millisecond)
+	merged := mergeHistograms(map[string]*latencyHistogram{"read": leaderHistogram}, results[1])
+	if merged["read"].count != 2 || merged["read"].min != 1000 || merged["read"].max != 3000 {
+		t.Fatalf("unexpected merged histogram: %+v", merged["read"])
+	}
+}
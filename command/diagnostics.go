@@ -0,0 +1,108 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ephemeralPortRangePath holds the range of local ports Linux connects
+	// from
+	ephemeralPortRangePath = "/proc/sys/net/ipv4/ip_local_port_range"
+
+	// timeWaitDuration is how long Linux keeps the local port of a closed
+	// connection in TIME_WAIT before it can be reused
+	timeWaitDuration = 60 * time.Second
+
+	// fdHeadroom is the number of file descriptors left for anything other
+	// than connections to Vault, such as log and config files
+	fdHeadroom = 64
+)
+
+// resourceLimits are the limits of the machine running the benchmark on the
+// connections it can open. Limits which couldn't be read are 0.
+type resourceLimits struct {
+	openFiles      uint64
+	ephemeralPorts int
+}
+
+func readResourceLimits() resourceLimits {
+	var limits resourceLimits
+	limits.openFiles, _ = openFileLimit()
+	limits.ephemeralPorts, _ = ephemeralPortCount(ephemeralPortRangePath)
+	return limits
+}
+
+// ephemeralPortCount returns the number of ports in the range read from path
+func ephemeralPortCount(path string) (int, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(b))
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("unexpected port range %q", strings.TrimSpace(string(b)))
+	}
+	low, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, fmt.Errorf("error parsing port range: %v", err)
+	}
+	high, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, fmt.Errorf("error parsing port range: %v", err)
+	}
+	return high - low + 1, nil
+}
+
+// connectionPlan describes the connections a run makes to each address
+type connectionPlan struct {
+	addrs     int
+	workers   int
+	maxIdle   int
+	keepAlive bool
+
+	// rate is the peak request rate sent to each address, or 0 if requests
+	// are sent as fast as possible
+	rate       int
+	closedLoop bool
+
+	// unixSocket is true if the run connects to a unix socket, which doesn't
+	// use ephemeral ports
+	unixSocket bool
+}
+
+// diagnoseResources returns warnings about limits the run is likely to hit on
+// the machine running it. Requests failing because of them would otherwise
+// be mistaken for errors returned by Vault.
+func diagnoseResources(limits resourceLimits, plan connectionPlan) []string {
+	var warnings []string
+
+	// Every worker may hold a connection to every address, along with the
+	// connections used to verify requests
+	connections := uint64(plan.workers * plan.addrs * 2)
+	if limits.openFiles != 0 && connections+fdHeadroom > limits.openFiles {
+		warnings = append(warnings, fmt.Sprintf("open file limit of %d may be exhausted by up to %d connections, raise it with ulimit -n", limits.openFiles, connections))
+	}
+
+	if plan.keepAlive && plan.maxIdle != 0 && plan.maxIdle < plan.workers {
+		warnings = append(warnings, fmt.Sprintf("max_idle_conns_per_host of %d is less than the %d workers, so connections will be closed and reopened during the run", plan.maxIdle, plan.workers))
+	}
+
+	// Without keep-alive every request uses a new local port, which stays
+	// in TIME_WAIT after the connection is closed
+	if !plan.keepAlive && !plan.unixSocket && limits.ephemeralPorts != 0 {
+		switch {
+		case plan.rate == 0 && !plan.closedLoop:
+			warnings = append(warnings, fmt.Sprintf("keep-alive is disabled and requests are unthrottled, so the %d ephemeral ports may be exhausted; set rps to at most %d", limits.ephemeralPorts, limits.ephemeralPorts/int(timeWaitDuration.Seconds())))
+		case plan.rate != 0 && plan.rate*int(timeWaitDuration.Seconds()) > limits.ephemeralPorts:
+			warnings = append(warnings, fmt.Sprintf("keep-alive is disabled, so %d rps needs about %d ephemeral ports but only %d are available", plan.rate, plan.rate*int(timeWaitDuration.Seconds()), limits.ephemeralPorts))
+		}
+	}
+	return warnings
+}
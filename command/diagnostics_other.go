@@ -0,0 +1,13 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !unix
+
+package command
+
+import "errors"
+
+// openFileLimit isn't supported outside of unix
+func openFileLimit() (uint64, error) {
+	return 0, errors.New("open file limit not supported on this platform")
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEphemeralPortCount(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ip_local_port_range")
+	if err := os.WriteFile(path, []byte("32768\t60999\n"), 0o644); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	count, err := ephemeralPortCount(path)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if count != 28232 {
+		t.Fatalf("expected 28232 ports, got %d", count)
+	}
+}
+
+func TestDiagnoseResources(t *testing.T) {
+	limits := resourceLimits{openFiles: 1024, ephemeralPorts: 28232}
+
+	cases := map[string]struct {
+		plan     connectionPlan
+		expected []string
+	}{
+		"within limits": {
+			plan: connectionPlan{addrs: 1, workers: 10, keepAlive: true},
+		},
+		"open files": {
+			plan:     connectionPlan{addrs: 3, workers: 200, keepAlive: true},
+			expected: []string{"open file limit of 1024"},
+		},
+		"idle connections": {
+			plan:     connectionPlan{addrs: 1, workers: 10, maxIdle: 2, keepAlive: true},
+			expected: []string{"max_idle_conns_per_host of 2"},
+		},
+		"unthrottled without keep-alive": {
+			plan:     connectionPlan{addrs: 1, workers: 10},
+			expected: []string{"set rps to at most 470"},
+		},
+		"rate without keep-alive": {
+			plan:     connectionPlan{addrs: 1, workers: 10, rate: 1000},
+			expected: []string{"1000 rps needs about 60000 ephemeral ports"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			warnings := diagnoseResources(limits, tc.plan)
+			if len(warnings) != len(tc.expected) {
+				t.Fatalf("expected %d warnings, got: %v", len(tc.expected), warnings)
+			}
+			for i, expected := range tc.expected {
+				if !strings.Contains(warnings[i], expected) {
+					t.Fatalf("expected warning containing %q, got: %q", expected, warnings[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build unix
+
+package command
+
+import "syscall"
+
+// openFileLimit returns the soft limit on the number of open files, which Go
+// raises to the hard limit at startup
+func openFileLimit() (uint64, error) {
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		return 0, err
+	}
+	return uint64(limit.Cur), nil
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openbao/benchmark-openbao/benchmarktests"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+// histogramSignificantBits is the number of leading bits of a latency in
+// microseconds kept by its bucket. Latencies below 2^8us are counted exactly
+// and larger ones with a relative error below 1%.
+const histogramSignificantBits = 8
+
+// histogramTotal is the name of the histogram covering every target
+const histogramTotal = "total"
+
+var _ benchmarktests.ResultConsumer = (*histogramConsumer)(nil)
+
+// latencyHistogram counts latencies in buckets which grow with the latency,
+// so that the whole distribution is kept in bounded space. Histograms
+// recorded by separate runs can be merged to compute percentiles across all
+// of them, which averaging the percentiles of each run can't do.
+type latencyHistogram struct {
+	count uint64
+	min   int64
+	max   int64
+
+	// buckets counts the latencies in each bucket, keyed by the smallest
+	// latency in microseconds the bucket holds
+	buckets map[int64]uint64
+}
+
+func newLatencyHistogram() *latencyHistogram {
+	return &latencyHistogram{buckets: make(map[int64]uint64)}
+}
+
+// histogramBucket returns the bucket of a latency in microseconds
+func histogramBucket(us int64) int64 {
+	shift := bits.Len64(uint64(us)) - histogramSignificantBits
+	if shift <= 0 {
+		return us
+	}
+	return us >> shift << shift
+}
+
+func (h *latencyHistogram) add(latency time.Duration) {
+	us := max(latency.Microseconds(), 0)
+	if h.count == 0 || us < h.min {
+		h.min = us
+	}
+	if us > h.max {
+		h.max = us
+	}
+	h.count++
+	h.buckets[histogramBucket(us)]++
+}
+
+// merge adds the latencies counted by o
+func (h *latencyHistogram) merge(o *latencyHistogram) {
+	if o.count == 0 {
+		return
+	}
+	if h.count == 0 || o.min < h.min {
+		h.min = o.min
+	}
+	if o.max > h.max {
+		h.max = o.max
+	}
+	h.count += o.count
+	for bucket, count := range o.buckets {
+		h.buckets[bucket] += count
+	}
+}
+
+// quantile returns the latency below which the fraction q of latencies fall,
+// to within the precision of the buckets
+func (h *latencyHistogram) quantile(q float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(h.count)))
+	var seen uint64
+	for _, bucket := range h.sortedBuckets() {
+		seen += h.buckets[bucket]
+		if seen >= rank {
+			return time.Duration(min(max(bucket, h.min), h.max)) * time.Microsecond
+		}
+	}
+	return time.Duration(h.max) * time.Microsecond
+}
+
+func (h *latencyHistogram) sortedBuckets() []int64 {
+	buckets := make([]int64, 0, len(h.buckets))
+	for bucket := range h.buckets {
+		buckets = append(buckets, bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i] < buckets[j] })
+	return buckets
+}
+
+// histogramJSON is the format histograms are written to files in, with the
+// buckets in increasing order
+type histogramJSON struct {
+	Count   uint64                `json:"count"`
+	Min     int64                 `json:"min_us"`
+	Max     int64                 `json:"max_us"`
+	Buckets []histogramBucketJSON `json:"buckets"`
+}
+
+type histogramBucketJSON struct {
+	Value int64  `json:"value_us"`
+	Count uint64 `json:"count"`
+}
+
+func (h *latencyHistogram) MarshalJSON() ([]byte, error) {
+	j := histogramJSON{Count: h.count, Min: h.min, Max: h.max, Buckets: []histogramBucketJSON{}}
+	for _, bucket := range h.sortedBuckets() {
+		j.Buckets = append(j.Buckets, histogramBucketJSON{Value: bucket, Count: h.buckets[bucket]})
+	}
+	return json.Marshal(j)
+}
+
+func (h *latencyHistogram) UnmarshalJSON(data []byte) error {
+	var j histogramJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	h.count, h.min, h.max = j.Count, j.Min, j.Max
+	h.buckets = make(map[int64]uint64, len(j.Buckets))
+	for _, b := range j.Buckets {
+		h.buckets[b.Value] += b.Count
+	}
+	return nil
+}
+
+// histogramFile is the contents of a histogram file
+type histogramFile struct {
+	SignificantBits int                          `json:"significant_bits"`
+	Histograms      map[string]*latencyHistogram `json:"histograms"`
+}
+
+// readHistogramFile reads histograms written by writeHistogramFile
+func readHistogramFile(r io.Reader) (map[string]*latencyHistogram, error) {
+	var f histogramFile
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, fmt.Errorf("error decoding histograms: %v", err)
+	}
+	if f.SignificantBits != histogramSignificantBits {
+		return nil, fmt.Errorf("histograms recorded with %d significant bits can't be merged with %d", f.SignificantBits, histogramSignificantBits)
+	}
+	return f.Histograms, nil
+}
+
+// writeHistogramFile writes histograms in a format readHistogramFile reads
+func writeHistogramFile(w io.Writer, histograms map[string]*latencyHistogram) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(&histogramFile{
+		SignificantBits: histogramSignificantBits,
+		Histograms:      histograms,
+	})
+}
+
+// histogramConsumer records the latency distribution of each target, and of
+// every target together, across all of the attacks
+type histogramConsumer struct {
+	mu         sync.Mutex
+	histograms map[string]*latencyHistogram
+}
+
+func newHistogramConsumer() *histogramConsumer {
+	return &histogramConsumer{
+		histograms: map[string]*latencyHistogram{
+			histogramTotal: newLatencyHistogram(),
+		},
+	}
+}
+
+func (h *histogramConsumer) Consume(target string, result *vegeta.Result, failed bool) {
+	if target == "" {
+		target = "unknown"
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	histogram, ok := h.histograms[target]
+	if !ok {
+		histogram = newLatencyHistogram()
+		h.histograms[target] = histogram
+	}
+	histogram.add(result.Latency)
+	h.histograms[histogramTotal].add(result.Latency)
+}
+
+// Histograms returns the recorded histograms
+func (h *histogramConsumer) Histograms() map[string]*latencyHistogram {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	histograms := make(map[string]*latencyHistogram, len(h.histograms))
+	for name, histogram := range h.histograms {
+		histograms[name] = histogram
+	}
+	return histograms
+}
+
+// WriteFile writes the recorded histograms to the file at path
+func (h *histogramConsumer) WriteFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating histogram file: %v", err)
+	}
+	defer f.Close()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := writeHistogramFile(f, h.histograms); err != nil {
+		return fmt.Errorf("error writing histogram file: %v", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	// Two runs with different latencies, whose merged percentiles can't be
+	// computed from the percentiles of each
+	fast := newHistogramConsumer()
+	slow := newHistogramConsumer()
+	for i := 1; i <= 100; i++ {
+		fast.Consume("kvv2_read", &vegeta.Result{Latency: time.Duration(i) * time.Millisecond}, false)
+		slow.Consume("kvv2_read", &vegeta.Result{Latency: time.Duration(100+i) * time.Millisecond}, false)
+	}
+
+	var buf bytes.Buffer
+	if err := writeHistogramFile(&buf, slow.histograms); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	read, err := readHistogramFile(&buf)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	merged := fast.histograms["kvv2_read"]
+	merged.merge(read["kvv2_read"])
+	if merged.count != 200 || merged.min != 1000 || merged.max != 200000 {
+		t.Fatalf("expected 200 latencies from 1ms to 200ms, got: %d from %d to %d", merged.count, merged.min, merged.max)
+	}
+
+	for q, expected := range map[float64]time.Duration{0.5: 100 * time.Millisecond, 0.99: 198 * time.Millisecond} {
+		actual := merged.quantile(q)
+		if diff := actual - expected; diff > expected/100 || -diff > expected/100 {
+			t.Fatalf("expected quantile %v within 1%% of %v, got: %v", q, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mitchellh/cli"
+	"github.com/openbao/benchmark-openbao/benchmarktests"
+	"github.com/posener/complete"
+)
+
+var (
+	_ cli.Command             = (*ListTestsCommand)(nil)
+	_ cli.CommandAutocomplete = (*ListTestsCommand)(nil)
+)
+
+// ListTestsCommand is a Command implementation that prints the available
+// test types and their configuration options.
+type ListTestsCommand struct {
+	*BaseCommand
+}
+
+func (c *ListTestsCommand) Synopsis() string {
+	return "List available tests and their configuration options"
+}
+
+func (c *ListTestsCommand) Help() string {
+	helpText := `
+Usage: vault-benchmark list-tests [TYPE...]
+
+  Prints every available test type along with the fields that can be set in
+  its config block and their default values.
+
+  List all tests:
+
+      $ vault-benchmark list-tests
+
+  Show the configuration options of specific tests:
+
+      $ vault-benchmark list-tests kvv2_read approle_auth
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ListTestsCommand) Flags() *FlagSets {
+	return nil
+}
+
+func (c *ListTestsCommand) AutocompleteArgs() complete.Predictor {
+	testTypes := make([]string, 0, len(benchmarktests.TestList))
+	for testType := range benchmarktests.TestList {
+		testTypes = append(testTypes, testType)
+	}
+	return complete.PredictSet(testTypes...)
+}
+
+func (c *ListTestsCommand) AutocompleteFlags() complete.Flags {
+	return nil
+}
+
+func (c *ListTestsCommand) Run(args []string) int {
+	testTypes := args
+	if len(testTypes) == 0 {
+		for testType := range benchmarktests.TestList {
+			testTypes = append(testTypes, testType)
+		}
+		sort.Strings(testTypes)
+	}
+
+	var b bytes.Buffer
+	tw := tabwriter.NewWriter(&b, 0, 8, 2, ' ', 0)
+	for _, testType := range testTypes {
+		if _, ok := benchmarktests.TestList[testType]; !ok {
+			c.UI.Error(invalidTestTypeError(testType).Error())
+			return 1
+		}
+		fields, err := benchmarktests.ConfigFields(testType)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+
+		fmt.Fprintln(tw, testType)
+		if len(fields) == 0 {
+			fmt.Fprintln(tw, "  (no configuration options)")
+		}
+		for _, field := range fields {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", field.Name, field.Type, field.Default)
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+
+	c.UI.Output(strings.TrimSpace(b.String()))
+	return 0
+}
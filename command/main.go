@@ -22,6 +22,7 @@ import (
 var commonCommands = []string{
 	"run",
 	"review",
+	"list-tests",
 }
 
 type VaultUI struct {
@@ -110,6 +111,13 @@ func RunCustom(args []string, runOpts *RunOptions) int {
 				},
 			}, nil
 		},
+		"list-tests": func() (cli.Command, error) {
+			return &ListTestsCommand{
+				BaseCommand: &BaseCommand{
+					UI: ui,
+				},
+			}, nil
+		},
 		"version": func() (cli.Command, error) {
 			return &VersionCommand{
 				BaseCommand: &BaseCommand{
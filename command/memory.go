@@ -0,0 +1,234 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	vaultapi "github.com/openbao/openbao/api/v2"
+)
+
+// memorySampleTimeout bounds each read of a server's metrics, so that a slow
+// server doesn't hold up the next sample
+const memorySampleTimeout = 10 * time.Second
+
+// memorySample is the memory use of a server at a point in the run, read from
+// the runtime gauges of sys/metrics
+type memorySample struct {
+	Elapsed     time.Duration `json:"elapsed"`
+	AllocBytes  float64       `json:"alloc_bytes"`
+	SysBytes    float64       `json:"sys_bytes"`
+	HeapObjects float64       `json:"heap_objects"`
+	Goroutines  float64       `json:"goroutines"`
+}
+
+// memorySampler periodically samples the memory use of each server during
+// the run, so that long soak runs show whether it grows over time
+type memorySampler struct {
+	clients  []*vaultapi.Client
+	logger   hclog.Logger
+	interval time.Duration
+	start    time.Time
+
+	mu      sync.Mutex
+	samples map[string][]memorySample
+
+	stopch chan struct{}
+	done   chan struct{}
+}
+
+// newMemorySampler returns a sampler which samples the memory use of each
+// client's server right away and then every interval until it is closed
+func newMemorySampler(clients []*vaultapi.Client, logger hclog.Logger, interval time.Duration) *memorySampler {
+	m := &memorySampler{
+		clients:  clients,
+		logger:   logger,
+		interval: interval,
+		start:    time.Now(),
+		samples:  make(map[string][]memorySample),
+		stopch:   make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go m.run()
+	return m
+}
+
+func (m *memorySampler) run() {
+	defer close(m.done)
+	m.sample()
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stopch:
+			return
+		case <-ticker.C:
+			m.sample()
+		}
+	}
+}
+
+// sample records the memory use of every server. Servers which can't be
+// sampled are skipped with a warning rather than ending the run.
+func (m *memorySampler) sample() {
+	elapsed := time.Since(m.start)
+	for _, client := range m.clients {
+		ctx, cancel := context.WithTimeout(context.Background(), memorySampleTimeout)
+		sample, err := readMemory(ctx, client)
+		cancel()
+		if err != nil {
+			m.logger.Warn("error sampling memory", "address", client.Address(), "error", err.Error())
+			continue
+		}
+		sample.Elapsed = elapsed
+
+		m.logger.Info("memory", "address", client.Address(),
+			"alloc", formatBytes(sample.AllocBytes),
+			"sys", formatBytes(sample.SysBytes),
+			"goroutines", sample.Goroutines)
+		m.mu.Lock()
+		m.samples[client.Address()] = append(m.samples[client.Address()], sample)
+		m.mu.Unlock()
+	}
+}
+
+// Close takes a last sample, so that the trend covers the whole run, and
+// stops sampling
+func (m *memorySampler) Close() {
+	close(m.stopch)
+	<-m.done
+	m.sample()
+}
+
+// Samples returns the samples of the given address
+func (m *memorySampler) Samples(addr string) []memorySample {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.samples[addr]
+}
+
+// readMemory reads the runtime gauges of the client's server. The gauges are
+// prefixed with the server's telemetry prefix and, unless disabled, its
+// hostname, so they are matched by suffix.
+func readMemory(ctx context.Context, client *vaultapi.Client) (memorySample, error) {
+	// sys/metrics is only served from the root namespace
+	resp, err := client.WithNamespace("").Logical().ReadRawWithDataWithContext(ctx, "sys/metrics", map[string][]string{"format": {"json"}})
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		return memorySample{}, fmt.Errorf("error reading metrics: %v", err)
+	}
+	return parseMemory(resp.Body)
+}
+
+// parseMemory parses the runtime gauges of a JSON sys/metrics response
+func parseMemory(r io.Reader) (memorySample, error) {
+	var metrics struct {
+		Gauges []struct {
+			Name  string  `json:"Name"`
+			Value float64 `json:"Value"`
+		} `json:"Gauges"`
+	}
+	if err := json.NewDecoder(r).Decode(&metrics); err != nil {
+		return memorySample{}, fmt.Errorf("error decoding metrics: %v", err)
+	}
+
+	var sample memorySample
+	var found bool
+	for _, gauge := range metrics.Gauges {
+		switch {
+		case strings.HasSuffix(gauge.Name, ".runtime.alloc_bytes"):
+			sample.AllocBytes = gauge.Value
+			found = true
+		case strings.HasSuffix(gauge.Name, ".runtime.sys_bytes"):
+			sample.SysBytes = gauge.Value
+		case strings.HasSuffix(gauge.Name, ".runtime.heap_objects"):
+			sample.HeapObjects = gauge.Value
+		case strings.HasSuffix(gauge.Name, ".runtime.num_goroutines"):
+			sample.Goroutines = gauge.Value
+		}
+	}
+	if !found {
+		return memorySample{}, fmt.Errorf("no runtime metrics found, check that telemetry is enabled")
+	}
+	return sample, nil
+}
+
+// allocGrowth returns the rate allocated memory grew at per hour, fitted by
+// least squares across the samples so that a single spike or collection
+// doesn't dominate the trend
+func allocGrowth(samples []memorySample) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+
+	var meanT, meanA float64
+	for _, s := range samples {
+		meanT += s.Elapsed.Hours()
+		meanA += s.AllocBytes
+	}
+	meanT /= float64(len(samples))
+	meanA /= float64(len(samples))
+
+	var cov, variance float64
+	for _, s := range samples {
+		dt := s.Elapsed.Hours() - meanT
+		cov += dt * (s.AllocBytes - meanA)
+		variance += dt * dt
+	}
+	if variance == 0 {
+		return 0
+	}
+	return cov / variance
+}
+
+// formatBytes formats a number of bytes in MiB
+func formatBytes(b float64) string {
+	return fmt.Sprintf("%.1fMiB", b/(1<<20))
+}
+
+// reportMemory reports the memory use of the address over the run in the
+// given report mode. Verbose and JSON reports include every sample.
+func reportMemory(w io.Writer, mode, addr string, samples []memorySample) error {
+	growth := allocGrowth(samples)
+	if mode == "json" {
+		return json.NewEncoder(w).Encode(&struct {
+			Type            string         `json:"type"`
+			TargetAddr      string         `json:"target_addr"`
+			AllocGrowthHour float64        `json:"alloc_growth_per_hour"`
+			Samples         []memorySample `json:"memory_samples"`
+		}{
+			Type:            "memory",
+			TargetAddr:      addr,
+			AllocGrowthHour: growth,
+			Samples:         samples,
+		})
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.StripEscape)
+	fmt.Fprintf(tw, "Memory trend: %v\n", addr)
+	if len(samples) == 0 {
+		fmt.Fprintf(tw, "no samples\n")
+		return tw.Flush()
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	fmt.Fprintf(tw, "samples\tfirst alloc\tlast alloc\tgrowth/hour\tfirst goroutines\tlast goroutines\n")
+	fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%.0f\t%.0f\n", len(samples), formatBytes(first.AllocBytes), formatBytes(last.AllocBytes), formatBytes(growth), first.Goroutines, last.Goroutines)
+	if mode == "verbose" {
+		fmt.Fprintf(tw, "\nelapsed\talloc\tsys\theap objects\tgoroutines\n")
+		for _, s := range samples {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%.0f\t%.0f\n", s.Elapsed.Round(time.Second), formatBytes(s.AllocBytes), formatBytes(s.SysBytes), s.HeapObjects, s.Goroutines)
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMemory(t *testing.T) {
+	sample, err := parseMemory(strings.NewReader(`{"Gauges": [
+		{"Name": "vault.node-1.runtime.alloc_bytes", "Value": 1048576},
+		{"Name": "vault.node-1.runtime.num_goroutines", "Value": 42},
+		{"Name": "vault.core.unsealed", "Value": 1}
+	]}`))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if sample.AllocBytes != 1<<20 || sample.Goroutines != 42 {
+		t.Fatalf("unexpected sample: %+v", sample)
+	}
+
+	if _, err := parseMemory(strings.NewReader(`{"Gauges": []}`)); err == nil {
+		t.Fatal("expected an error without runtime metrics")
+	}
+}
+
+func TestAllocGrowth(t *testing.T) {
+	// Memory grows by 30MiB over three hours, with a collection in the
+	// middle of the run which the fitted trend smooths over
+	samples := []memorySample{
+		{Elapsed: 0, AllocBytes: 100 << 20},
+		{Elapsed: time.Hour, AllocBytes: 110 << 20},
+		{Elapsed: 2 * time.Hour, AllocBytes: 100 << 20},
+		{Elapsed: 3 * time.Hour, AllocBytes: 130 << 20},
+	}
+	if growth := allocGrowth(samples); math.Abs(growth-8<<20) > 1 {
+		t.Fatalf("expected 8MiB an hour, got %v", formatBytes(growth))
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/openbao/benchmark-openbao/benchmarktests"
+	vbConfig "github.com/openbao/benchmark-openbao/config"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+// newPacer builds the vegeta pacer used by the attacker from the global
+// configuration. A constant rate of conf.RPS is used unless a ramp or a
+// sine wave is configured. If conf.Requests is set the returned pacer stops
+// the attack once that many requests have been sent to the address, in total
+// across every test. When conf.ThinkTime is set the attack is closed-loop,
+// with each worker pausing between requests instead of requests being sent
+// at a rate.
+func newPacer(conf *vbConfig.VaultBenchmarkCoreConfig, duration time.Duration) (vegeta.Pacer, error) {
+	ramp := conf.RampStart != 0 || conf.RampEnd != 0
+	sine := conf.MeanRate != 0 || conf.Amplitude != 0 || conf.Period != ""
+
+	var pacer vegeta.Pacer
+	var err error
+	switch {
+	case ramp && sine:
+		return nil, fmt.Errorf("ramp_start/ramp_end cannot be combined with mean_rate/amplitude/period")
+	case (ramp || sine) && conf.RPS != 0:
+		return nil, fmt.Errorf("rps cannot be combined with a ramp or sine request rate")
+	case conf.ThinkTime != "" && (ramp || sine || conf.RPS != 0):
+		return nil, fmt.Errorf("think_time cannot be combined with rps or a ramp or sine request rate")
+	case ramp:
+		pacer, err = newRampPacerFromConfig(conf, duration)
+	case sine:
+		pacer, err = newSinePacerFromConfig(conf)
+	default:
+		pacer = vegeta.Rate{Freq: conf.RPS, Per: time.Second}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case conf.Requests < 0:
+		return nil, fmt.Errorf("requests must be greater than 0")
+	case conf.Requests > 0:
+		pacer = countPacer{Pacer: pacer, limit: uint64(conf.Requests)}
+	}
+
+	if conf.ThinkTime != "" {
+		thinkTime, err := time.ParseDuration(conf.ThinkTime)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing think_time: %v", err)
+		}
+		if thinkTime < 0 {
+			return nil, fmt.Errorf("think_time must not be negative")
+		}
+		pacer = benchmarktests.ClosedLoop{Pacer: pacer, ThinkTime: thinkTime}
+	}
+	return pacer, nil
+}
+
+func newRampPacerFromConfig(conf *vbConfig.VaultBenchmarkCoreConfig, duration time.Duration) (vegeta.Pacer, error) {
+	if conf.RampStart <= 0 || conf.RampEnd <= 0 {
+		return nil, fmt.Errorf("ramp_start and ramp_end must both be greater than 0")
+	}
+
+	rampDuration := duration
+	if conf.RampDuration != "" {
+		var err error
+		rampDuration, err = time.ParseDuration(conf.RampDuration)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing ramp_duration: %v", err)
+		}
+	}
+	if rampDuration <= 0 {
+		return nil, fmt.Errorf("ramp_duration must be greater than 0")
+	}
+
+	return newRampPacer(conf.RampStart, conf.RampEnd, rampDuration), nil
+}
+
+func newSinePacerFromConfig(conf *vbConfig.VaultBenchmarkCoreConfig) (vegeta.Pacer, error) {
+	if conf.MeanRate <= 0 {
+		return nil, fmt.Errorf("mean_rate must be greater than 0")
+	}
+	if conf.Amplitude < 0 || conf.Amplitude >= conf.MeanRate {
+		return nil, fmt.Errorf("amplitude must be between 0 and mean_rate")
+	}
+	if conf.Period == "" {
+		return nil, fmt.Errorf("period is required when using mean_rate")
+	}
+	period, err := time.ParseDuration(conf.Period)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing period: %v", err)
+	}
+	if period <= 0 {
+		return nil, fmt.Errorf("period must be greater than 0")
+	}
+
+	return vegeta.SinePacer{
+		Period:  period,
+		Mean:    vegeta.Rate{Freq: conf.MeanRate, Per: time.Second},
+		Amp:     vegeta.Rate{Freq: conf.Amplitude, Per: time.Second},
+		StartAt: vegeta.MeanUp,
+	}, nil
+}
+
+// rampPacer linearly changes the request rate from start to end over the
+// ramp duration and then holds the end rate for the rest of the attack.
+type rampPacer struct {
+	ramp     vegeta.LinearPacer
+	hold     vegeta.ConstantPacer
+	duration time.Duration
+	rampHits uint64
+}
+
+var _ vegeta.Pacer = rampPacer{}
+
+func newRampPacer(start, end int, duration time.Duration) rampPacer {
+	return rampPacer{
+		ramp: vegeta.LinearPacer{
+			StartAt: vegeta.Rate{Freq: start, Per: time.Second},
+			Slope:   float64(end-start) / duration.Seconds(),
+		},
+		hold:     vegeta.Rate{Freq: end, Per: time.Second},
+		duration: duration,
+		rampHits: uint64(float64(start+end) / 2 * duration.Seconds()),
+	}
+}
+
+func (p rampPacer) String() string {
+	return fmt.Sprintf("Ramp{%s -> %s over %s}", p.ramp.StartAt, p.hold, p.duration)
+}
+
+// Pace determines the length of time to sleep until the next hit is sent.
+func (p rampPacer) Pace(elapsed time.Duration, hits uint64) (time.Duration, bool) {
+	if elapsed < p.duration {
+		return p.ramp.Pace(elapsed, hits)
+	}
+	if hits < p.rampHits {
+		// Running behind, send next hit immediately.
+		return 0, false
+	}
+	return p.hold.Pace(elapsed-p.duration, hits-p.rampHits)
+}
+
+// Rate returns the instantaneous hit rate at the given elapsed duration.
+func (p rampPacer) Rate(elapsed time.Duration) float64 {
+	if elapsed < p.duration {
+		return p.ramp.Rate(elapsed)
+	}
+	return p.hold.Rate(elapsed - p.duration)
+}
+
+// countPacer wraps another pacer and stops the attack once limit hits have
+// been sent. Every test shares the attack, so the limit is the total for the
+// address rather than for each test.
+type countPacer struct {
+	vegeta.Pacer
+	limit uint64
+}
+
+// Pace determines the length of time to sleep until the next hit is sent.
+func (p countPacer) Pace(elapsed time.Duration, hits uint64) (time.Duration, bool) {
+	if hits >= p.limit {
+		return 0, true
+	}
+	return p.Pacer.Pace(elapsed, hits)
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openbao/benchmark-openbao/benchmarktests"
+	vbConfig "github.com/openbao/benchmark-openbao/config"
+)
+
+func TestNewPacer_Ramp(t *testing.T) {
+	conf := vbConfig.NewVaultBenchmarkCoreConfig()
+	conf.RampStart = 10
+	conf.RampEnd = 110
+	conf.RampDuration = "10s"
+
+	pacer, err := newPacer(conf, 30*time.Second)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if rate := pacer.Rate(0); rate != 10 {
+		t.Fatalf("expected starting rate of 10, got: %f", rate)
+	}
+	if rate := pacer.Rate(5 * time.Second); rate != 60 {
+		t.Fatalf("expected rate of 60 halfway through the ramp, got: %f", rate)
+	}
+	if rate := pacer.Rate(20 * time.Second); rate != 110 {
+		t.Fatalf("expected rate to hold at 110 after the ramp, got: %f", rate)
+	}
+
+	// 600 hits are expected over the ramp, so being behind that should
+	// not introduce any wait.
+	if wait, stop := pacer.Pace(11*time.Second, 500); wait != 0 || stop {
+		t.Fatalf("expected no wait when behind, got: %v, %v", wait, stop)
+	}
+}
+
+func TestNewPacer_RampWithRPS(t *testing.T) {
+	conf := vbConfig.NewVaultBenchmarkCoreConfig()
+	conf.RPS = 100
+	conf.RampStart = 10
+	conf.RampEnd = 110
+
+	if _, err := newPacer(conf, 30*time.Second); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestNewPacer_Sine(t *testing.T) {
+	conf := vbConfig.NewVaultBenchmarkCoreConfig()
+	conf.MeanRate = 100
+	conf.Amplitude = 50
+	conf.Period = "1m"
+
+	pacer, err := newPacer(conf, 30*time.Second)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if rate := pacer.Rate(0); rate != 100 {
+		t.Fatalf("expected starting rate of 100, got: %f", rate)
+	}
+
+	conf.Amplitude = 100
+	if _, err := newPacer(conf, 30*time.Second); err == nil {
+		t.Fatal("expected error when amplitude is not less than mean_rate")
+	}
+}
+
+func TestNewPacer_Requests(t *testing.T) {
+	conf := vbConfig.NewVaultBenchmarkCoreConfig()
+	conf.Requests = 5
+
+	pacer, err := newPacer(conf, 0)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if _, stop := pacer.Pace(time.Second, 4); stop {
+		t.Fatal("expected attack to continue before the request count is reached")
+	}
+	if _, stop := pacer.Pace(time.Second, 5); !stop {
+		t.Fatal("expected attack to stop once the request count is reached")
+	}
+}
+
+func TestNewPacer_ThinkTime(t *testing.T) {
+	conf := vbConfig.NewVaultBenchmarkCoreConfig()
+	conf.ThinkTime = "100ms"
+	conf.Requests = 5
+
+	pacer, err := newPacer(conf, 0)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	closedLoop, ok := pacer.(benchmarktests.ClosedLoop)
+	if !ok {
+		t.Fatalf("expected a closed-loop pacer, got: %T", pacer)
+	}
+	if closedLoop.ThinkTime != 100*time.Millisecond {
+		t.Fatalf("expected think time of 100ms, got: %v", closedLoop.ThinkTime)
+	}
+	if _, stop := pacer.Pace(time.Second, 5); !stop {
+		t.Fatal("expected attack to stop once the request count is reached")
+	}
+
+	conf.RPS = 10
+	if _, err := newPacer(conf, 0); err == nil {
+		t.Fatal("expected error when think_time is combined with rps")
+	}
+}
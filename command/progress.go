@@ -0,0 +1,120 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/openbao/benchmark-openbao/benchmarktests"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+var _ benchmarktests.ResultConsumer = (*progressConsumer)(nil)
+
+// progressConsumer periodically logs how far along the attack is for each
+// target, so that long runs show signs of life before the final report
+type progressConsumer struct {
+	logger   hclog.Logger
+	interval time.Duration
+
+	mu      sync.Mutex
+	targets map[string]*progressCounts
+	last    time.Time
+
+	stopch chan struct{}
+	done   chan struct{}
+}
+
+// progressCounts are the requests made to a target
+type progressCounts struct {
+	requests uint64
+	errors   uint64
+
+	// successes are counted since progress was last logged
+	successes uint64
+}
+
+// newProgressConsumer returns a consumer which logs the progress of each
+// target every interval until it is closed
+func newProgressConsumer(logger hclog.Logger, interval time.Duration) *progressConsumer {
+	p := &progressConsumer{
+		logger:   logger,
+		interval: interval,
+		targets:  make(map[string]*progressCounts),
+		last:     time.Now(),
+		stopch:   make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+func (p *progressConsumer) Consume(target string, result *vegeta.Result, failed bool) {
+	if target == "" {
+		target = "unknown"
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	counts, ok := p.targets[target]
+	if !ok {
+		counts = &progressCounts{}
+		p.targets[target] = counts
+	}
+	counts.requests++
+	if failed {
+		counts.errors++
+	} else {
+		counts.successes++
+	}
+}
+
+func (p *progressConsumer) run() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stopch:
+			return
+		case <-ticker.C:
+			p.log()
+		}
+	}
+}
+
+// log logs the requests made to each target so far, and the throughput of
+// successful requests since progress was last logged
+func (p *progressConsumer) log() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(p.last).Seconds()
+	p.last = now
+
+	names := make([]string, 0, len(p.targets))
+	for name := range p.targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		counts := p.targets[name]
+		p.logger.Info("progress", "target", name,
+			"requests", counts.requests,
+			"throughput", hclog.Fmt("%.2f/s", float64(counts.successes)/elapsed),
+			"errors", counts.errors)
+		counts.successes = 0
+	}
+}
+
+// Close stops logging progress
+func (p *progressConsumer) Close() {
+	close(p.stopch)
+	<-p.done
+}
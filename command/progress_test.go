@@ -0,0 +1,41 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+func TestProgressConsumer(t *testing.T) {
+	var buf bytes.Buffer
+	logger := hclog.New(&hclog.LoggerOptions{Output: &buf})
+
+	// The interval is long enough that progress is only logged explicitly
+	consumer := newProgressConsumer(logger, time.Hour)
+	defer consumer.Close()
+	consumer.Consume("kvv2_read", &vegeta.Result{Code: 200}, false)
+	consumer.Consume("kvv2_read", &vegeta.Result{Code: 500}, true)
+	consumer.Consume("", &vegeta.Result{Error: "connection refused"}, true)
+	consumer.log()
+
+	out := buf.String()
+	for _, expected := range []string{
+		"target=kvv2_read requests=2",
+		"errors=1",
+		"target=unknown requests=1",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Fatalf("expected %q in output:\n%s", expected, out)
+		}
+	}
+	if strings.Index(out, "kvv2_read") > strings.Index(out, "unknown") {
+		t.Fatalf("expected targets in order:\n%s", out)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	vaultapi "github.com/openbao/openbao/api/v2"
+)
+
+// readyPollInterval is how often the health of the Vault addresses is checked
+// while waiting for them to become ready
+const readyPollInterval = time.Second
+
+// waitForReady polls each client's address until it is initialized and
+// unsealed and the cluster has an active node, or until timeout has elapsed.
+// The last reason an address wasn't ready is returned on timeout.
+func waitForReady(ctx context.Context, clients []*vaultapi.Client, timeout time.Duration, logger hclog.Logger) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for _, client := range clients {
+		// sys/health and sys/leader are only served from the root namespace
+		client = client.WithNamespace("")
+		for {
+			err := checkReady(ctx, client)
+			if err == nil {
+				logger.Debug("vault is ready", "address", client.Address())
+				break
+			}
+			logger.Debug("waiting for vault to become ready", "address", client.Address(), "reason", err.Error())
+
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("timed out waiting for %v to become ready: %w", client.Address(), err)
+			case <-time.After(readyPollInterval):
+			}
+		}
+	}
+	return nil
+}
+
+// checkReady returns an error describing why the client's address isn't ready
+// to be benchmarked, or nil if it is
+func checkReady(ctx context.Context, client *vaultapi.Client) error {
+	health, err := client.Sys().HealthWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("error checking health: %v", err)
+	}
+	switch {
+	case !health.Initialized:
+		return errors.New("not initialized")
+	case health.Sealed:
+		return errors.New("sealed")
+	case !health.Standby:
+		return nil
+	}
+
+	// Standbys forward requests to the active node, so they are ready as
+	// soon as the cluster has one
+	leader, err := client.Sys().LeaderWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("error checking leader: %v", err)
+	}
+	if leader.HAEnabled && leader.LeaderAddress == "" {
+		return errors.New("no active node")
+	}
+	return nil
+}
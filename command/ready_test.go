@@ -0,0 +1,53 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	vaultapi "github.com/openbao/openbao/api/v2"
+)
+
+func TestWaitForReady(t *testing.T) {
+	// The server reports being sealed for the first two health checks
+	var checks atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sealed := checks.Add(1) <= 2
+		w.Header().Set("Content-Type", "application/json")
+		if sealed {
+			w.Write([]byte(`{"initialized": true, "sealed": true, "standby": true}`))
+			return
+		}
+		w.Write([]byte(`{"initialized": true, "sealed": false, "standby": false}`))
+	}))
+	defer srv.Close()
+
+	config := vaultapi.DefaultConfig()
+	config.Address = srv.URL
+	client, err := vaultapi.NewClient(config)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	err = waitForReady(context.Background(), []*vaultapi.Client{client}, 10*time.Second, hclog.NewNullLogger())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if n := checks.Load(); n != 3 {
+		t.Fatalf("expected 3 health checks, got: %d", n)
+	}
+
+	// Never becoming ready times out
+	checks.Store(-100)
+	err = waitForReady(context.Background(), []*vaultapi.Client{client}, 1500*time.Millisecond, hclog.NewNullLogger())
+	if err == nil {
+		t.Fatal("expected timeout error")
+	}
+}
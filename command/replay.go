@@ -0,0 +1,269 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/openbao/benchmark-openbao/benchmarktests"
+	vaultapi "github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+// The formats captured traffic can be replayed from
+const (
+	replayFormatHAR  = "har"
+	replayFormatHTTP = "http"
+	replayFormatJSON = "json"
+)
+
+// replayGroupSegments is the number of path segments requests are grouped by
+// in reports, such as /v1/secret/data, so that requests to the same mount and
+// endpoint are reported together rather than per secret
+const replayGroupSegments = 3
+
+// replayDroppedHeaders are captured headers which aren't replayed, either
+// because they are set for the connection the request is replayed on or
+// because the benchmark's token is used in place of the captured one
+var replayDroppedHeaders = []string{"Authorization", "X-Vault-Token", "Host", "Content-Length", "Connection", "Cookie"}
+
+// replayRequest is a captured request, with its URL reduced to the path and
+// query so that it can be sent to any address
+type replayRequest struct {
+	method string
+	path   string
+	body   []byte
+	header http.Header
+}
+
+// replaySource replays captured requests in the order they were captured,
+// starting over once every request has been sent
+type replaySource struct {
+	requests []replayRequest
+	targets  []benchmarktests.BenchmarkTarget
+
+	// sent counts the requests sent so far across every address
+	sent atomic.Uint64
+}
+
+// readReplay reads the captured requests of the file in the given format.
+// The format is inferred from the file's extension when it is empty.
+func readReplay(path, format string) (*replaySource, error) {
+	if format == "" {
+		format = replayFormatHTTP
+		if strings.EqualFold(filepath.Ext(path), ".har") {
+			format = replayFormatHAR
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening replay file: %v", err)
+	}
+	defer f.Close()
+
+	var requests []replayRequest
+	switch format {
+	case replayFormatHAR:
+		requests, err = parseHAR(f)
+	case replayFormatHTTP:
+		requests, err = parseVegetaTargets(vegeta.NewHTTPTargeter(f, nil, nil))
+	case replayFormatJSON:
+		requests, err = parseVegetaTargets(vegeta.NewJSONTargeter(f, nil, nil))
+	default:
+		return nil, fmt.Errorf("replay_format must be one of har, http or json")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("replay file has no requests")
+	}
+	return newReplaySource(requests), nil
+}
+
+// parseHAR parses the requests of a HAR file, as exported by browsers and
+// proxies
+func parseHAR(r io.Reader) ([]replayRequest, error) {
+	var har struct {
+		Log struct {
+			Entries []struct {
+				Request struct {
+					Method  string `json:"method"`
+					URL     string `json:"url"`
+					Headers []struct {
+						Name  string `json:"name"`
+						Value string `json:"value"`
+					} `json:"headers"`
+					PostData *struct {
+						Text     string `json:"text"`
+						Encoding string `json:"encoding"`
+					} `json:"postData"`
+				} `json:"request"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return nil, fmt.Errorf("error decoding HAR file: %v", err)
+	}
+
+	requests := make([]replayRequest, 0, len(har.Log.Entries))
+	for i, entry := range har.Log.Entries {
+		header := make(http.Header, len(entry.Request.Headers))
+		for _, h := range entry.Request.Headers {
+			// HTTP/2 pseudo-headers such as :authority are part of the
+			// request line rather than headers
+			if strings.HasPrefix(h.Name, ":") {
+				continue
+			}
+			header.Add(h.Name, h.Value)
+		}
+
+		var body []byte
+		if data := entry.Request.PostData; data != nil {
+			body = []byte(data.Text)
+			if data.Encoding == "base64" {
+				var err error
+				body, err = base64.StdEncoding.DecodeString(data.Text)
+				if err != nil {
+					return nil, fmt.Errorf("error decoding body of HAR entry %d: %v", i, err)
+				}
+			}
+		}
+
+		req, err := newReplayRequest(entry.Request.Method, entry.Request.URL, body, header)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing HAR entry %d: %v", i, err)
+		}
+		requests = append(requests, req)
+	}
+	return requests, nil
+}
+
+// parseVegetaTargets reads every target of a vegeta targeter
+func parseVegetaTargets(targeter vegeta.Targeter) ([]replayRequest, error) {
+	var requests []replayRequest
+	for {
+		var tgt vegeta.Target
+		err := targeter(&tgt)
+		if errors.Is(err, vegeta.ErrNoTargets) {
+			return requests, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing target %d: %v", len(requests)+1, err)
+		}
+
+		req, err := newReplayRequest(tgt.Method, tgt.URL, tgt.Body, tgt.Header)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing target %d: %v", len(requests)+1, err)
+		}
+		requests = append(requests, req)
+	}
+}
+
+func newReplayRequest(method, rawURL string, body []byte, header http.Header) (replayRequest, error) {
+	if method == "" {
+		return replayRequest{}, fmt.Errorf("request has no method")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return replayRequest{}, fmt.Errorf("error parsing URL: %v", err)
+	}
+
+	header = header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	for _, name := range replayDroppedHeaders {
+		header.Del(name)
+	}
+	return replayRequest{
+		method: strings.ToUpper(method),
+		path:   u.RequestURI(),
+		body:   body,
+		header: header,
+	}, nil
+}
+
+// replayGroup returns the path prefix a request is reported under
+func replayGroup(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(segments) > replayGroupSegments {
+		segments = segments[:replayGroupSegments]
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// newReplaySource groups the requests into targets by method and path prefix
+func newReplaySource(requests []replayRequest) *replaySource {
+	r := &replaySource{requests: requests}
+
+	seen := make(map[string]bool)
+	for _, req := range requests {
+		prefix := replayGroup(req.path)
+		name := req.method + " " + prefix
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		r.targets = append(r.targets, benchmarktests.BenchmarkTarget{
+			Name:          name,
+			Method:        req.method,
+			PathPrefix:    prefix,
+			SegmentPrefix: true,
+			Target: func(client *vaultapi.Client) vegeta.Target {
+				return r.target(client, req)
+			},
+		})
+	}
+
+	// Results are matched to the first target whose prefix they start with
+	// up to a segment boundary, so longer prefixes must be matched first
+	sort.SliceStable(r.targets, func(i, j int) bool {
+		return len(r.targets[i].PathPrefix) > len(r.targets[j].PathPrefix)
+	})
+	return r
+}
+
+// target returns the request to send to the client, with the client's token
+func (r *replaySource) target(client *vaultapi.Client, req replayRequest) vegeta.Target {
+	header := client.Headers()
+	if header == nil {
+		header = make(http.Header)
+	}
+	for name, values := range req.header {
+		header[name] = values
+	}
+	header.Set("X-Vault-Token", client.Token())
+
+	return vegeta.Target{
+		Method: req.method,
+		URL:    client.Address() + req.path,
+		Body:   req.body,
+		Header: header,
+	}
+}
+
+func (r *replaySource) Targets() []benchmarktests.BenchmarkTarget {
+	return r.targets
+}
+
+func (r *replaySource) Next(client *vaultapi.Client, tgt *vegeta.Target) error {
+	n := r.sent.Add(1) - 1
+	*tgt = r.target(client, r.requests[n%uint64(len(r.requests))])
+	return nil
+}
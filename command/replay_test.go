@@ -0,0 +1,74 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	vaultapi "github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+func TestParseHAR(t *testing.T) {
+	requests, err := parseHAR(strings.NewReader(`{"log": {"entries": [
+		{"request": {"method": "GET", "url": "https://vault.example.com:8200/v1/secret/data/app/db?version=2", "headers": [
+			{"name": ":authority", "value": "vault.example.com:8200"},
+			{"name": "X-Vault-Token", "value": "hvs.captured"},
+			{"name": "X-Vault-Namespace", "value": "team-a"}
+		]}},
+		{"request": {"method": "POST", "url": "https://vault.example.com:8200/v1/transit/encrypt/key", "headers": [],
+			"postData": {"text": "{\"plaintext\":\"Zm9v\"}"}}}
+	]}}`))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got: %d", len(requests))
+	}
+	if req := requests[0]; req.path != "/v1/secret/data/app/db?version=2" || req.header.Get("X-Vault-Token") != "" || req.header.Get(":authority") != "" {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+	if req := requests[1]; req.method != "POST" || string(req.body) != `{"plaintext":"Zm9v"}` {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+}
+
+func TestReplaySource(t *testing.T) {
+	requests, err := parseVegetaTargets(vegeta.NewHTTPTargeter(strings.NewReader(`GET http://10.0.0.1:8200/v1/secret/data/app/db
+GET http://10.0.0.1:8200/v1/secret/data/app/cache
+GET http://10.0.0.1:8200/v1/sys/health
+`), nil, nil))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	source := newReplaySource(requests)
+
+	// Requests are grouped by mount and endpoint
+	var names []string
+	for _, target := range source.Targets() {
+		names = append(names, target.Name)
+	}
+	if strings.Join(names, ", ") != "GET /v1/secret/data, GET /v1/sys/health" {
+		t.Fatalf("unexpected targets: %v", names)
+	}
+
+	client, err := vaultapi.NewClient(vaultapi.DefaultConfig())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	client.SetToken("benchmark")
+
+	// Requests are sent to the client in the order they were captured,
+	// starting over at the end
+	for _, path := range []string{"/v1/secret/data/app/db", "/v1/secret/data/app/cache", "/v1/sys/health", "/v1/secret/data/app/db"} {
+		var tgt vegeta.Target
+		if err := source.Next(client, &tgt); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if tgt.URL != client.Address()+path || tgt.Header.Get("X-Vault-Token") != "benchmark" {
+			t.Fatalf("expected %v with the client's token, got %v", path, tgt.URL)
+		}
+	}
+}
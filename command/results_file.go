@@ -0,0 +1,77 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/openbao/benchmark-openbao/benchmarktests"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+var _ benchmarktests.ResultConsumer = (*resultsFileConsumer)(nil)
+
+// resultsFileConsumer streams the raw result of every request to a file, so
+// that runs can be analyzed with the vegeta CLI or other tooling. Results are
+// written as they arrive rather than held until the run completes.
+type resultsFileConsumer struct {
+	mu     sync.Mutex
+	file   *os.File
+	buf    *bufio.Writer
+	encode vegeta.Encoder
+
+	// err is the first error writing a result, after which results are
+	// dropped
+	err error
+}
+
+// newResultsFileConsumer returns a consumer which writes results to path as
+// JSON lines if it has a .json or .jsonl extension, and in vegeta's gob
+// format otherwise
+func newResultsFileConsumer(path string) (*resultsFileConsumer, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("error creating results file: %v", err)
+	}
+
+	r := &resultsFileConsumer{file: f, buf: bufio.NewWriter(f)}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".jsonl":
+		r.encode = vegeta.NewJSONEncoder(r.buf)
+	default:
+		r.encode = vegeta.NewEncoder(r.buf)
+	}
+	return r, nil
+}
+
+func (r *resultsFileConsumer) Consume(target string, result *vegeta.Result, failed bool) {
+	// The result is shared with the other consumers, so a copy is written.
+	// Response bodies are left out, as they can hold secrets.
+	written := *result
+	written.Attack = target
+	written.Body = nil
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	if err := r.encode(&written); err != nil {
+		r.err = fmt.Errorf("error writing result: %v", err)
+	}
+}
+
+// Close flushes the buffered results and closes the file, returning the first
+// error writing a result if any were dropped
+func (r *resultsFileConsumer) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return errors.Join(r.err, r.buf.Flush(), r.file.Close())
+}
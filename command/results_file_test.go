@@ -0,0 +1,55 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+func TestResultsFileConsumer(t *testing.T) {
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		name    string
+		decoder func(*os.File) vegeta.Decoder
+	}{
+		{"results.json", func(f *os.File) vegeta.Decoder { return vegeta.NewJSONDecoder(f) }},
+		{"results.bin", func(f *os.File) vegeta.Decoder { return vegeta.NewDecoder(f) }},
+	} {
+		path := filepath.Join(dir, tc.name)
+		consumer, err := newResultsFileConsumer(path)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		result := &vegeta.Result{Code: 200, Latency: time.Millisecond, Method: "GET", URL: "http://127.0.0.1:8200/v1/secret/data/secret-1", Body: []byte("secret")}
+		consumer.Consume("kvv2_read_test", result, false)
+		consumer.Consume("kvv2_read_test", result, false)
+		if err := consumer.Close(); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if string(result.Body) != "secret" {
+			t.Fatalf("expected the consumed result to be left as it was")
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		dec := tc.decoder(f)
+		for i := 0; i < 2; i++ {
+			var got vegeta.Result
+			if err := dec.Decode(&got); err != nil {
+				t.Fatalf("%v: err: %v", tc.name, err)
+			}
+			if got.Attack != "kvv2_read_test" || got.Latency != time.Millisecond || len(got.Body) != 0 {
+				t.Fatalf("%v: unexpected result: %+v", tc.name, got)
+			}
+		}
+		f.Close()
+	}
+}
@@ -4,6 +4,7 @@
 package command
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"flag"
@@ -11,9 +12,12 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
@@ -24,6 +28,7 @@ import (
 	"github.com/posener/complete"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
 )
 
 const (
@@ -40,23 +45,66 @@ type RunCommand struct {
 	*BaseCommand
 	flagDuration         time.Duration
 	flagPPROFInterval    time.Duration
+	flagPPROFAddr        string
+	flagWaitForReady     time.Duration
+	flagRampDuration     time.Duration
+	flagPeriod           time.Duration
+	flagThinkTime        time.Duration
+	flagRequestTimeout   time.Duration
+	flagProgressInterval time.Duration
+	flagMemoryInterval   time.Duration
 	flagVaultAddr        string
+	flagVaultAddrs       string
+	flagAgentAddr        string
+	flagLoadBalance      string
 	flagVaultToken       string
 	flagAuditPath        string
-	flagVBCoreConfigPath string
+	flagOTLPEndpoint     string
+	flagStatsdAddr       string
+	flagHistogramFile    string
+	flagReplayFile       string
+	flagReplayFormat     string
+	flagResultsFile      string
+	flagLeaderListen     string
+	flagLeaderAddr       string
+	flagStatsdPrefix     string
+	flagVBCoreConfigs    []string
 	flagCAPEMFile        string
 	flagVaultNamespace   string
 	flagReportMode       string
 	flagAnnotate         string
 	flagClusterJson      string
 	flagLogLevel         string
+	flagSLO              []string
+	flagFilterTags       []string
 	flagWorkers          int
+	flagMaxWorkers       int
+	flagGOMAXPROCS       int
 	flagRPS              int
+	flagRequests         int
+	flagRuns             int
+	flagMaxIdleConns     int
+	flagRampStart        int
+	flagRampEnd          int
+	flagMeanRate         int
+	flagAmplitude        int
+	flagSetupRetries     int
+	flagSetupMaxConns    int
+	flagErrorBodies      int
+	flagSeed             int
+	flagFollowers        int
 	flagRandomMounts     bool
 	flagCleanup          bool
+	flagSkipSetup        bool
+	flagSkipCleanup      bool
 	flagDebug            bool
+	flagDryRun           bool
 	flagDisableHTTP2     bool
 	flagDisableKeepAlive bool
+	flagDisableCompress  bool
+	flagForceHTTP2       bool
+	flagStatsdTags       bool
+	flagCompareTLS       bool
 }
 
 func (r *RunCommand) Synopsis() string {
@@ -73,6 +121,10 @@ Usage: vault-benchmark run [options]
 
 	$ vault-benchmark run -config=/etc/vault-benchmark/test.hcl
 
+ Merge the tests of several configuration files, one read from stdin:
+
+	$ generate-kv-tests | vault-benchmark run -config=base.hcl -config=-
+
  For a full list of examples, please see the documentation.
 
 ` + r.Flags().Help()
@@ -99,6 +151,27 @@ func (r *RunCommand) Flags() *FlagSets {
 		Usage:   "Target Vault API Address.",
 	})
 
+	f.StringVar(&StringVar{
+		Name:    "vault_addrs",
+		Target:  &r.flagVaultAddrs,
+		Default: "",
+		Usage:   "Comma-separated list of Vault API addresses to benchmark. Takes precedence over vault_addr.",
+	})
+
+	f.StringVar(&StringVar{
+		Name:    "agent_addr",
+		Target:  &r.flagAgentAddr,
+		Default: "",
+		Usage:   "Address of an OpenBao Agent or Proxy to send benchmark requests through. Tests are still set up directly against Vault.",
+	})
+
+	f.StringVar(&StringVar{
+		Name:    "load_balance",
+		Target:  &r.flagLoadBalance,
+		Default: "",
+		Usage:   "Spread requests across all Vault addresses instead of benchmarking each separately. Options are: round_robin, random.",
+	})
+
 	f.StringVar(&StringVar{
 		Name:    "vault_token",
 		EnvVar:  "VAULT_TOKEN",
@@ -115,13 +188,13 @@ func (r *RunCommand) Flags() *FlagSets {
 		Default: "",
 	})
 
-	f.StringVar(&StringVar{
+	f.StringSliceVar(&StringSliceVar{
 		Name:   "config",
-		Target: &r.flagVBCoreConfigPath,
+		Target: &r.flagVBCoreConfigs,
 		Completion: complete.PredictOr(
 			complete.PredictFiles("*.hcl"),
 		),
-		Usage: "Path to a vault-benchmark test configuration file.",
+		Usage: "Path to a vault-benchmark test configuration file, or - to read it from stdin. Can be specified multiple times to merge the tests of several files.",
 	})
 
 	f.IntVar(&IntVar{
@@ -131,6 +204,20 @@ func (r *RunCommand) Flags() *FlagSets {
 		Usage:   "Number of workers",
 	})
 
+	f.IntVar(&IntVar{
+		Name:    "max_workers",
+		Target:  &r.flagMaxWorkers,
+		Default: 0,
+		Usage:   "Maximum number of workers to start while every worker is busy and requests are due. Defaults to the number of workers.",
+	})
+
+	f.IntVar(&IntVar{
+		Name:    "gomaxprocs",
+		Target:  &r.flagGOMAXPROCS,
+		Default: 0,
+		Usage:   "Number of CPUs the benchmark tool may use at once. Defaults to the GOMAXPROCS environment variable or the number of CPUs available.",
+	})
+
 	f.IntVar(&IntVar{
 		Name:    "rps",
 		Target:  &r.flagRPS,
@@ -145,6 +232,102 @@ func (r *RunCommand) Flags() *FlagSets {
 		Usage:   "Test Duration.",
 	})
 
+	f.IntVar(&IntVar{
+		Name:    "requests",
+		Target:  &r.flagRequests,
+		Default: 0,
+		Usage:   "Total number of requests to send to each Vault address, shared by every test according to its weight. Cannot be combined with duration.",
+	})
+
+	f.IntVar(&IntVar{
+		Name:    "runs",
+		Target:  &r.flagRuns,
+		Default: 1,
+		Usage:   "Number of times to run the benchmark, reporting the mean and standard deviation of each test's metrics across runs.",
+	})
+
+	f.IntVar(&IntVar{
+		Name:    "ramp_start",
+		Target:  &r.flagRampStart,
+		Default: 0,
+		Usage:   "Requests per second at the start of a linear ramp. Requires ramp_end.",
+	})
+
+	f.IntVar(&IntVar{
+		Name:    "ramp_end",
+		Target:  &r.flagRampEnd,
+		Default: 0,
+		Usage:   "Requests per second at the end of a linear ramp. Requires ramp_start.",
+	})
+
+	f.DurationVar(&DurationVar{
+		Name:    "ramp_duration",
+		Target:  &r.flagRampDuration,
+		Default: 0,
+		Usage:   "Time taken to ramp from ramp_start to ramp_end. Defaults to the test duration.",
+	})
+
+	f.IntVar(&IntVar{
+		Name:    "mean_rate",
+		Target:  &r.flagMeanRate,
+		Default: 0,
+		Usage:   "Mean requests per second of a sine wave request rate. Requires period.",
+	})
+
+	f.IntVar(&IntVar{
+		Name:    "amplitude",
+		Target:  &r.flagAmplitude,
+		Default: 0,
+		Usage:   "Amplitude in requests per second of a sine wave request rate.",
+	})
+
+	f.DurationVar(&DurationVar{
+		Name:    "period",
+		Target:  &r.flagPeriod,
+		Default: 0,
+		Usage:   "Period of a sine wave request rate.",
+	})
+
+	f.StringSliceVar(&StringSliceVar{
+		Name:   "slo",
+		Target: &r.flagSLO,
+		Usage:  "Assertion about a test's metrics, e.g. \"kvv2_read_test: p99 < 50ms\", which fails the run if it doesn't hold. Can be specified multiple times.",
+	})
+
+	f.StringSliceVar(&StringSliceVar{
+		Name:   "filter_tags",
+		Target: &r.flagFilterTags,
+		Usage:  "Only run the tests with this tag, or any of several comma-separated tags. Can be specified multiple times.",
+	})
+
+	f.DurationVar(&DurationVar{
+		Name:    "request_timeout",
+		Target:  &r.flagRequestTimeout,
+		Default: 0,
+		Usage:   "Cut off benchmark requests which take longer than this and count them as timeouts. Defaults to the Vault client's timeout.",
+	})
+
+	f.DurationVar(&DurationVar{
+		Name:    "progress_interval",
+		Target:  &r.flagProgressInterval,
+		Default: 0,
+		Usage:   "Log the progress of each test at this interval during the run. Disabled by default.",
+	})
+
+	f.DurationVar(&DurationVar{
+		Name:    "memory_sample_interval",
+		Target:  &r.flagMemoryInterval,
+		Default: 0,
+		Usage:   "Sample the memory use of each Vault server from sys/metrics at this interval during the run and report its trend. Disabled by default.",
+	})
+
+	f.DurationVar(&DurationVar{
+		Name:    "think_time",
+		Target:  &r.flagThinkTime,
+		Default: 0,
+		Usage:   "Run closed-loop, with each worker waiting this long after a response before sending its next request.",
+	})
+
 	f.StringVar(&StringVar{
 		Name:    "report_mode",
 		Target:  &r.flagReportMode,
@@ -152,6 +335,20 @@ func (r *RunCommand) Flags() *FlagSets {
 		Usage:   "Reporting Mode. Options are: terse, verbose, json.",
 	})
 
+	f.IntVar(&IntVar{
+		Name:    "error_bodies",
+		Target:  &r.flagErrorBodies,
+		Default: 0,
+		Usage:   "Number of distinct error response bodies to include in the report for each test.",
+	})
+
+	f.IntVar(&IntVar{
+		Name:    "seed",
+		Target:  &r.flagSeed,
+		Default: 0,
+		Usage:   "Seed for the random choices made while generating requests, so that runs are reproducible. A random seed is used when unset.",
+	})
+
 	f.DurationVar(&DurationVar{
 		Name:    "pprof_interval",
 		Target:  &r.flagPPROFInterval,
@@ -159,6 +356,20 @@ func (r *RunCommand) Flags() *FlagSets {
 		Usage:   "Collection interval for vault debug pprof profiling.",
 	})
 
+	f.StringVar(&StringVar{
+		Name:    "pprof_addr",
+		Target:  &r.flagPPROFAddr,
+		Default: "",
+		Usage:   "Address to serve pprof profiles of the benchmark tool itself on, such as localhost:6060.",
+	})
+
+	f.DurationVar(&DurationVar{
+		Name:    "wait_for_ready",
+		Target:  &r.flagWaitForReady,
+		Default: 0,
+		Usage:   "Wait up to this long for Vault to be initialized, unsealed and active before setting up tests.",
+	})
+
 	f.StringVar(&StringVar{
 		Name:    "annotate",
 		Target:  &r.flagAnnotate,
@@ -173,6 +384,83 @@ func (r *RunCommand) Flags() *FlagSets {
 		Usage:   "Path to file for audit log.",
 	})
 
+	f.StringVar(&StringVar{
+		Name:    "otlp_endpoint",
+		Target:  &r.flagOTLPEndpoint,
+		Default: "",
+		Usage:   "OTLP HTTP endpoint to export traces of the setup, attack and cleanup phases to.",
+	})
+
+	f.StringVar(&StringVar{
+		Name:    "histogram_file",
+		Target:  &r.flagHistogramFile,
+		Default: "",
+		Usage:   "Path to a file to write the latency distribution of each test to once the run completes, so that runs can be merged.",
+	})
+
+	f.StringVar(&StringVar{
+		Name:    "replay_file",
+		Target:  &r.flagReplayFile,
+		Default: "",
+		Usage:   "Path to a file of captured requests to replay in place of the tests, in HAR or vegeta target format.",
+	})
+
+	f.StringVar(&StringVar{
+		Name:    "replay_format",
+		Target:  &r.flagReplayFormat,
+		Default: "",
+		Usage:   "Format of replay_file, one of har, http or json. Inferred from the file's extension by default.",
+	})
+
+	f.StringVar(&StringVar{
+		Name:    "results_file",
+		Target:  &r.flagResultsFile,
+		Default: "",
+		Usage:   "Path to a file to stream the raw result of every request to, as JSON lines for .json and .jsonl files and in vegeta's gob format otherwise.",
+	})
+
+	f.StringVar(&StringVar{
+		Name:    "statsd_addr",
+		Target:  &r.flagStatsdAddr,
+		Default: "",
+		Usage:   "Address (host:port) of a statsd server to send per-request metrics to during the run.",
+	})
+
+	f.StringVar(&StringVar{
+		Name:    "statsd_prefix",
+		Target:  &r.flagStatsdPrefix,
+		Default: "vault_benchmark",
+		Usage:   "Prefix of the names of metrics sent to statsd.",
+	})
+
+	f.BoolVar(&BoolVar{
+		Name:    "statsd_tags",
+		Target:  &r.flagStatsdTags,
+		Default: false,
+		Usage:   "Send the test name and status code as DogStatsD tags instead of in the metric name.",
+	})
+
+	f.StringVar(&StringVar{
+		Name:    "leader_listen",
+		Target:  &r.flagLeaderListen,
+		Default: "",
+		Usage:   "Address to listen on for followers, leading a distributed run across several benchmark processes. Requires followers.",
+	})
+
+	f.IntVar(&IntVar{
+		Name:    "followers",
+		Target:  &r.flagFollowers,
+		Default: 0,
+		Usage:   "Number of followers which must join a distributed run before it starts.",
+	})
+
+	f.StringVar(&StringVar{
+		Name:    "leader_addr",
+		Target:  &r.flagLeaderAddr,
+		Default: "",
+		Usage:   "Address of the leader of a distributed run to follow, e.g. http://10.0.0.1:8300.",
+	})
+
 	f.StringVar(&StringVar{
 		Name:    "ca_pem_file",
 		Target:  &r.flagCAPEMFile,
@@ -202,6 +490,34 @@ func (r *RunCommand) Flags() *FlagSets {
 		Usage:   "Cleanup benchmark artifacts after run.",
 	})
 
+	f.BoolVar(&BoolVar{
+		Name:    "skip_cleanup",
+		Target:  &r.flagSkipCleanup,
+		Default: false,
+		Usage:   "Skip cleanup after the run, even if cleanup is enabled, so that the resources created by tests can be inspected.",
+	})
+
+	f.BoolVar(&BoolVar{
+		Name:    "skip_setup",
+		Target:  &r.flagSkipSetup,
+		Default: false,
+		Usage:   "Reuse the mounts and data set up by a previous run instead of setting them up again. Requires random_mounts to be disabled.",
+	})
+
+	f.IntVar(&IntVar{
+		Name:    "setup_retries",
+		Target:  &r.flagSetupRetries,
+		Default: 3,
+		Usage:   "Number of times transient errors are retried while setting up tests. Set to 0 to disable retries.",
+	})
+
+	f.IntVar(&IntVar{
+		Name:    "setup_max_conns",
+		Target:  &r.flagSetupMaxConns,
+		Default: 0,
+		Usage:   "Maximum connections to Vault used to set up and clean up tests. Defaults to sharing the attack's connections.",
+	})
+
 	f.StringVar(&StringVar{
 		Name:    "log_level",
 		Target:  &r.flagLogLevel,
@@ -217,6 +533,13 @@ func (r *RunCommand) Flags() *FlagSets {
 		Usage:   "Run vault-benchmark in Debug mode.",
 	})
 
+	f.BoolVar(&BoolVar{
+		Name:    "dry_run",
+		Target:  &r.flagDryRun,
+		Default: false,
+		Usage:   "Validate the configuration and print the targets without contacting Vault.",
+	})
+
 	f.BoolVar(&BoolVar{
 		Name:    "disable_http2",
 		Target:  &r.flagDisableHTTP2,
@@ -231,6 +554,34 @@ func (r *RunCommand) Flags() *FlagSets {
 		Usage:   "Disable TCP connection reuse",
 	})
 
+	f.BoolVar(&BoolVar{
+		Name:    "disable_compression",
+		Target:  &r.flagDisableCompress,
+		Default: false,
+		Usage:   "Don't ask Vault to gzip responses",
+	})
+
+	f.BoolVar(&BoolVar{
+		Name:    "force_http2",
+		Target:  &r.flagForceHTTP2,
+		Default: false,
+		Usage:   "Force HTTP/2, including over plain text connections",
+	})
+
+	f.BoolVar(&BoolVar{
+		Name:    "compare_tls_handshake",
+		Target:  &r.flagCompareTLS,
+		Default: false,
+		Usage:   "Repeat the benchmark with a new connection for every request and report the latency added by the TLS handshake.",
+	})
+
+	f.IntVar(&IntVar{
+		Name:    "max_idle_conns_per_host",
+		Target:  &r.flagMaxIdleConns,
+		Default: 0,
+		Usage:   "Maximum idle connections kept per Vault address. Defaults to the number of workers.",
+	})
+
 	// Add any additional flags from tests
 	for _, vbTest := range benchmarktests.TestList {
 		vbTest().Flags(f.mainSet)
@@ -254,106 +605,478 @@ func (r *RunCommand) Run(args []string) int {
 	}
 
 	// Load config from File
-	if r.flagVBCoreConfigPath == "" {
+	if len(r.flagVBCoreConfigs) == 0 {
 		benchmarkLogger.Error("no config file location passed")
 		return 1
 	}
 
 	conf := vbConfig.NewVaultBenchmarkCoreConfig()
-	err := conf.LoadConfig(r.flagVBCoreConfigPath)
+	err := conf.LoadConfigs(r.flagVBCoreConfigs, os.Stdin)
 	if err != nil {
+		err = describeConfigError(err)
 		benchmarkLogger.Error("error loading config", "error", hclog.Fmt("%v", err))
 		return 1
 	}
 
+	durationSet := conf.Duration != "" || r.isFlagSet(f, "duration")
 	r.applyConfigOverrides(f, conf)
 	benchmarkLogger.SetLevel(hclog.LevelFromString(conf.LogLevel))
 
-	// Parse Duration from configuration string
-	parsedDuration, err := time.ParseDuration(conf.Duration)
+	if len(conf.FilterTags) > 0 {
+		conf.Tests, err = filterTests(conf.Tests, conf.FilterTags)
+		if err != nil {
+			benchmarkLogger.Error("error filtering tests by tag", "error", hclog.Fmt("%v", err))
+			return 1
+		}
+		names := make([]string, len(conf.Tests))
+		for i, test := range conf.Tests {
+			names[i] = test.Name
+		}
+		benchmarkLogger.Info("running tests matching filter_tags", "tests", strings.Join(names, ", "))
+	}
+
+	conf.Tests, err = benchmarktests.OrderTargets(conf.Tests)
 	if err != nil {
-		benchmarkLogger.Error("error parsing test duration from configuration", "error", hclog.Fmt("%v", err))
+		benchmarkLogger.Error("error ordering tests by depends_on", "error", hclog.Fmt("%v", err))
+		return 1
 	}
 
-	// Parse pprof Interval from configuration string
-	var parsedPPROFinterval time.Duration
-	if conf.PPROFInterval != "" {
-		parsedPPROFinterval, err = time.ParseDuration(conf.PPROFInterval)
+	switch {
+	case conf.LeaderAddr != "" && (conf.LeaderListen != "" || conf.Followers != 0):
+		benchmarkLogger.Error("leader_addr cannot be combined with leader_listen or followers")
+		return 1
+	case conf.Followers < 0:
+		benchmarkLogger.Error("followers must not be negative")
+		return 1
+	case (conf.LeaderListen != "") != (conf.Followers > 0):
+		benchmarkLogger.Error("leader_listen and followers must be set together")
+		return 1
+	}
+
+	// Followers of a distributed run use the parameters assigned by the
+	// leader in place of their own
+	var runFollower *follower
+	if conf.LeaderAddr != "" && !r.flagDryRun {
+		runFollower = newFollower(conf.LeaderAddr)
+		benchmarkLogger.Info("joining leader", "address", conf.LeaderAddr)
+		params, err := runFollower.Join(context.Background())
 		if err != nil {
-			benchmarkLogger.Error("error parsing pprof interval from configuration", "error", hclog.Fmt("%v", err))
+			benchmarkLogger.Error("error joining distributed run", "error", hclog.Fmt("%v", err))
 			return 1
 		}
+		params.apply(conf)
+		durationSet = false
+		benchmarkLogger.Info("joined distributed run", "runner", params.Runner)
 	}
 
-	if (!conf.RandomMounts) && (conf.Cleanup) {
-		benchmarkLogger.Error("cleanup can only be enabled when random mounts is enabled")
-		return 1
+	// Parse Duration from configuration string
+	parsedDuration, err := time.ParseDuration(conf.Duration)
+	if err != nil {
+		benchmarkLogger.Error("error parsing test duration from configuration", "error", hclog.Fmt("%v", err))
 	}
 
-	switch conf.ReportMode {
-	case "terse", "verbose", "json":
-	default:
-		benchmarkLogger.Error("report_mode must be one of terse, verbose, or json")
-	}
+	// A request count bounds the run instead of a duration
+	if conf.Requests != 0 {
+		if durationSet {
+			benchmarkLogger.Error("duration and requests are mutually exclusive; set only one of them")
+			return 1
+		}
+		parsedDuration = 0
 
-	var cluster struct {
-		Token      string   `json:"token"`
-		VaultAddrs []string `json:"vault_addrs"`
+		for _, test := range conf.Tests {
+			if test.Duration != "" || test.RPS != 0 {
+				benchmarkLogger.Error("per-test duration and rps cannot be combined with requests", "test", test.Name)
+				return 1
+			}
+		}
 	}
 
-	switch {
-	case conf.ClusterJSON != "":
-		b, err := os.ReadFile(conf.ClusterJSON)
+	var parsedRequestTimeout time.Duration
+	if conf.RequestTimeout != "" {
+		parsedRequestTimeout, err = time.ParseDuration(conf.RequestTimeout)
 		if err != nil {
-			benchmarkLogger.Error(fmt.Sprintf("error reading cluster_json file: %q", conf.ClusterJSON), "error", hclog.Fmt("%v", err))
+			benchmarkLogger.Error("error parsing request_timeout from configuration", "error", hclog.Fmt("%v", err))
 			return 1
 		}
-		err = json.Unmarshal(b, &cluster)
+		if parsedRequestTimeout < 0 {
+			benchmarkLogger.Error("request_timeout must not be negative")
+			return 1
+		}
+	}
+
+	var parsedProgressInterval time.Duration
+	if conf.ProgressInterval != "" {
+		parsedProgressInterval, err = time.ParseDuration(conf.ProgressInterval)
 		if err != nil {
-			benchmarkLogger.Error(fmt.Sprintf("error decoding cluster_json file: %q", conf.ClusterJSON), "error", hclog.Fmt("%v", err))
+			benchmarkLogger.Error("error parsing progress_interval from configuration", "error", hclog.Fmt("%v", err))
+			return 1
+		}
+		if parsedProgressInterval < 0 {
+			benchmarkLogger.Error("progress_interval must not be negative")
 			return 1
 		}
-	case conf.VaultAddr != "":
-		cluster.VaultAddrs = []string{conf.VaultAddr}
-	default:
-		benchmarkLogger.Error("must specify one of cluster_json, vault_addr, or $VAULT_ADDR")
 	}
 
-	if conf.VaultToken != "" {
-		cluster.Token = conf.VaultToken
+	var parsedMemoryInterval time.Duration
+	if conf.MemoryInterval != "" {
+		parsedMemoryInterval, err = time.ParseDuration(conf.MemoryInterval)
+		if err != nil {
+			benchmarkLogger.Error("error parsing memory_sample_interval from configuration", "error", hclog.Fmt("%v", err))
+			return 1
+		}
+		if parsedMemoryInterval < 0 {
+			benchmarkLogger.Error("memory_sample_interval must not be negative")
+			return 1
+		}
 	}
-	if conf.VaultToken == "" && cluster.Token == "" {
-		benchmarkLogger.Error("must specify one of the following: cluster_json, vault_token, or $VAULT_TOKEN")
+
+	slos, err := parseSLOs(conf)
+	if err != nil {
+		benchmarkLogger.Error("error parsing slo", "error", hclog.Fmt("%v", err))
 		return 1
 	}
 
-	// Setup annotations and testRunning metric
-	var annoLabels []string
-	var annoValues []string
-	if conf.Annotate != "" {
-		for _, kv := range strings.Split(conf.Annotate, ",") {
-			kvPair := strings.SplitN(kv, "=", 2)
-			if len(kvPair) != 2 || kvPair[0] == "" {
-				benchmarkLogger.Error("annotate should contain comma-separated list of name=value pairs", "got", conf.Annotate)
+	// Closed-loop attacks have no request rate to override
+	if conf.ThinkTime != "" {
+		for _, test := range conf.Tests {
+			if test.RPS != 0 {
+				benchmarkLogger.Error("per-test rps cannot be combined with think_time", "test", test.Name)
 				return 1
 			}
-			annoLabels = append(annoLabels, kvPair[0])
-			annoValues = append(annoValues, kvPair[1])
 		}
 	}
 
-	testRunning := prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Name: "bench_running",
-		Help: "is the benchmark attack executing",
-	}, annoLabels)
-	prometheus.MustRegister(testRunning)
-	testRunning.WithLabelValues(annoValues...).Set(0)
-
-	// Setup our prometheus listener
-	http.Handle("/metrics", promhttp.Handler())
-	go func() {
-		_ = http.ListenAndServe(":2112", nil)
-	}()
+	switch {
+	case conf.MaxWorkers < 0:
+		benchmarkLogger.Error("max_workers must not be negative")
+		return 1
+	case conf.MaxWorkers != 0 && conf.MaxWorkers < conf.Workers:
+		benchmarkLogger.Error("max_workers must be at least workers")
+		return 1
+	case conf.MaxWorkers != 0 && conf.ThinkTime != "":
+		// Each worker is a virtual user, so their number is fixed
+		benchmarkLogger.Error("max_workers cannot be combined with think_time")
+		return 1
+	case conf.GOMAXPROCS < 0:
+		benchmarkLogger.Error("gomaxprocs must not be negative")
+		return 1
+	case conf.Runs < 1:
+		benchmarkLogger.Error("runs must be at least 1")
+		return 1
+	case conf.Runs > 1 && (conf.LeaderListen != "" || conf.LeaderAddr != ""):
+		// Runners of a distributed run only start attacking together once
+		benchmarkLogger.Error("runs cannot be combined with leader_listen or leader_addr")
+		return 1
+	}
+
+	if err := checkRepeatedAttacks(conf); err != nil {
+		benchmarkLogger.Error(err.Error())
+		return 1
+	}
+	maxWorkers := max(conf.Workers, conf.MaxWorkers)
+	if conf.GOMAXPROCS > 0 {
+		runtime.GOMAXPROCS(conf.GOMAXPROCS)
+	}
+
+	// Parse pprof Interval from configuration string
+	var parsedPPROFinterval time.Duration
+	if conf.PPROFInterval != "" {
+		parsedPPROFinterval, err = time.ParseDuration(conf.PPROFInterval)
+		if err != nil {
+			benchmarkLogger.Error("error parsing pprof interval from configuration", "error", hclog.Fmt("%v", err))
+			return 1
+		}
+	}
+
+	var parsedWaitForReady time.Duration
+	if conf.WaitForReady != "" {
+		parsedWaitForReady, err = time.ParseDuration(conf.WaitForReady)
+		if err != nil {
+			benchmarkLogger.Error("error parsing wait_for_ready from configuration", "error", hclog.Fmt("%v", err))
+			return 1
+		}
+	}
+
+	pacer, err := newPacer(conf, parsedDuration)
+	if err != nil {
+		benchmarkLogger.Error("error configuring request rate", "error", hclog.Fmt("%v", err))
+		return 1
+	}
+
+	if parsedPPROFinterval != 0 && parsedDuration == 0 {
+		benchmarkLogger.Error("pprof_interval cannot be used with requests")
+		return 1
+	}
+
+	if conf.TLS != nil && (conf.TLS.ClientCert == "") != (conf.TLS.ClientKey == "") {
+		benchmarkLogger.Error("tls client_cert and client_key must be set together")
+		return 1
+	}
+
+	if conf.ForceHTTP2 && (conf.DisableHTTP2 || conf.DisableKeepAlive) {
+		benchmarkLogger.Error("force_http2 cannot be combined with disable_http2 or disable_keep_alive")
+		return 1
+	}
+
+	if conf.AgentAddr != "" && (conf.LoadBalance != "" || conf.ForceHTTP2) {
+		benchmarkLogger.Error("agent_addr cannot be combined with load_balance or force_http2")
+		return 1
+	}
+
+	if conf.CompareTLS && (conf.DisableKeepAlive || conf.ForceHTTP2) {
+		benchmarkLogger.Error("compare_tls_handshake cannot be combined with disable_keep_alive or force_http2")
+		return 1
+	}
+
+	// audit_path is shorthand for a file audit device
+	var audit *benchmarktests.AuditDevice
+	switch {
+	case conf.Audit != nil && conf.AuditPath != "":
+		benchmarkLogger.Error("audit_path cannot be combined with an audit block")
+		return 1
+	case conf.Audit != nil:
+		audit, err = conf.Audit.Device()
+		if err != nil {
+			benchmarkLogger.Error("invalid audit configuration", "error", hclog.Fmt("%v", err))
+			return 1
+		}
+	case conf.AuditPath != "":
+		audit = &benchmarktests.AuditDevice{
+			Type:    "file",
+			Options: map[string]string{"file_path": conf.AuditPath},
+		}
+	}
+
+	var namespaces []string
+	if conf.Namespaces != nil {
+		namespaces, err = conf.Namespaces.List()
+		if err != nil {
+			benchmarkLogger.Error("invalid namespaces configuration", "error", hclog.Fmt("%v", err))
+			return 1
+		}
+	}
+
+	if conf.ErrorBodies < 0 {
+		benchmarkLogger.Error("error_bodies must not be negative")
+		return 1
+	}
+
+	if conf.MaxIdleConns < 0 {
+		benchmarkLogger.Error("max_idle_conns_per_host must not be negative")
+		return 1
+	}
+
+	if conf.SetupMaxConns < 0 {
+		benchmarkLogger.Error("setup_max_conns must not be negative")
+		return 1
+	}
+
+	if conf.SetupMaxConns > 0 && conf.ForceHTTP2 {
+		benchmarkLogger.Error("setup_max_conns can't be combined with force_http2")
+		return 1
+	}
+
+	if (!conf.RandomMounts) && (conf.Cleanup) {
+		benchmarkLogger.Error("cleanup can only be enabled when random mounts is enabled")
+		return 1
+	}
+
+	if conf.SkipSetup && conf.RandomMounts {
+		benchmarkLogger.Error("skip_setup can only be enabled when random mounts is disabled")
+		return 1
+	}
+
+	switch conf.LoadBalance {
+	case "", benchmarktests.LoadBalanceRoundRobin, benchmarktests.LoadBalanceRandom:
+	default:
+		benchmarkLogger.Error("load_balance must be one of round_robin or random")
+		return 1
+	}
+
+	switch conf.ReportMode {
+	case "terse", "verbose", "json":
+	default:
+		benchmarkLogger.Error("report_mode must be one of terse, verbose, or json")
+	}
+
+	// Captured requests are replayed in place of the tests, so nothing is
+	// set up for them
+	var replay *replaySource
+	if conf.ReplayFile != "" {
+		switch {
+		case len(conf.Tests) > 0:
+			benchmarkLogger.Error("replay_file cannot be combined with test blocks")
+			return 1
+		case conf.Namespaces != nil || audit != nil:
+			benchmarkLogger.Error("replay_file cannot be combined with namespaces or an audit device")
+			return 1
+		}
+		replay, err = readReplay(conf.ReplayFile, conf.ReplayFormat)
+		if err != nil {
+			benchmarkLogger.Error("error reading replay_file", "error", hclog.Fmt("%v", err))
+			return 1
+		}
+		benchmarkLogger.Info("replaying captured requests", "file", conf.ReplayFile, "requests", len(replay.requests), "targets", len(replay.targets))
+	} else if conf.ReplayFormat != "" {
+		benchmarkLogger.Error("replay_format can only be set with replay_file")
+		return 1
+	}
+
+	if r.flagDryRun && replay != nil {
+		benchmarkLogger.Info("dry run complete, replay file is valid")
+		return 0
+	}
+	if r.flagDryRun {
+		if err := benchmarktests.DescribeTargets(os.Stdout, conf.Tests); err != nil {
+			benchmarkLogger.Error("invalid test configuration", "error", hclog.Fmt("%v", err))
+			return 1
+		}
+		benchmarkLogger.Info("dry run complete, configuration is valid")
+		return 0
+	}
+
+	// The leader of a distributed run listens for followers while it sets up
+	// its own tests. Its seed is shared with followers, so it is fixed first.
+	var runLeader *leader
+	if conf.LeaderListen != "" {
+		if conf.Seed == 0 {
+			conf.Seed = int(time.Now().UnixNano())
+		}
+		runLeader = newLeader(runParametersFromConfig(conf), conf.Followers, benchmarkLogger.Named("leader"))
+		if err := runLeader.Start(conf.LeaderListen); err != nil {
+			benchmarkLogger.Error("error starting distributed run", "error", hclog.Fmt("%v", err))
+			return 1
+		}
+		defer runLeader.Close()
+		benchmarkLogger.Info("leading distributed run", "address", conf.LeaderListen, "followers", conf.Followers)
+	}
+
+	// Spans are only exported when an endpoint is configured, otherwise the
+	// default no-op tracer provider discards them
+	if conf.OTLPEndpoint != "" {
+		shutdownTracing, err := setupTracing(context.Background(), conf.OTLPEndpoint)
+		if err != nil {
+			benchmarkLogger.Error("error configuring tracing", "error", hclog.Fmt("%v", err))
+			return 1
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				benchmarkLogger.Error("error exporting traces", "error", hclog.Fmt("%v", err))
+			}
+		}()
+	}
+	runCtx, runSpan := otel.Tracer("github.com/openbao/benchmark-openbao/command").Start(context.Background(), "benchmark")
+	defer runSpan.End()
+
+	var cluster struct {
+		Token      string   `json:"token"`
+		VaultAddrs []string `json:"vault_addrs"`
+	}
+
+	switch {
+	case conf.ClusterJSON != "":
+		b, err := os.ReadFile(conf.ClusterJSON)
+		if err != nil {
+			benchmarkLogger.Error(fmt.Sprintf("error reading cluster_json file: %q", conf.ClusterJSON), "error", hclog.Fmt("%v", err))
+			return 1
+		}
+		err = json.Unmarshal(b, &cluster)
+		if err != nil {
+			benchmarkLogger.Error(fmt.Sprintf("error decoding cluster_json file: %q", conf.ClusterJSON), "error", hclog.Fmt("%v", err))
+			return 1
+		}
+	case conf.VaultAddrs != "":
+		for _, addr := range strings.Split(conf.VaultAddrs, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				cluster.VaultAddrs = append(cluster.VaultAddrs, addr)
+			}
+		}
+	case conf.VaultAddr != "":
+		cluster.VaultAddrs = []string{conf.VaultAddr}
+	default:
+		benchmarkLogger.Error("must specify one of cluster_json, vault_addr, or $VAULT_ADDR")
+	}
+
+	if conf.VaultToken != "" {
+		cluster.Token = conf.VaultToken
+	}
+
+	// Requests to a unix socket are made to http://localhost, so they can't
+	// be told apart from requests to any other address
+	var unixSocket bool
+	for _, addr := range cluster.VaultAddrs {
+		if isUnixAddr(addr) {
+			unixSocket = true
+		}
+	}
+	switch {
+	case unixSocket && len(cluster.VaultAddrs) > 1:
+		benchmarkLogger.Error("a unix socket address cannot be combined with other addresses")
+		return 1
+	case unixSocket && conf.ForceHTTP2:
+		benchmarkLogger.Error("force_http2 cannot be used with a unix socket address")
+		return 1
+	}
+
+	// Benchmark requests are sent to the agent instead of Vault when one is
+	// configured
+	attackAddrs := cluster.VaultAddrs
+	if conf.AgentAddr != "" {
+		attackAddrs = []string{conf.AgentAddr}
+	}
+
+	if conf.CompareTLS {
+		for _, addr := range attackAddrs {
+			if !strings.HasPrefix(addr, "https://") {
+				benchmarkLogger.Warn("address doesn't use TLS, only the cost of new connections will be compared", "address", addr)
+			}
+		}
+	}
+	if conf.VaultToken == "" && cluster.Token == "" {
+		benchmarkLogger.Error("must specify one of the following: cluster_json, vault_token, or $VAULT_TOKEN")
+		return 1
+	}
+
+	// Setup annotations and testRunning metric
+	var annoLabels []string
+	var annoValues []string
+	if conf.Annotate != "" {
+		for _, kv := range strings.Split(conf.Annotate, ",") {
+			kvPair := strings.SplitN(kv, "=", 2)
+			if len(kvPair) != 2 || kvPair[0] == "" {
+				benchmarkLogger.Error("annotate should contain comma-separated list of name=value pairs", "got", conf.Annotate)
+				return 1
+			}
+			annoLabels = append(annoLabels, kvPair[0])
+			annoValues = append(annoValues, kvPair[1])
+		}
+	}
+
+	testRunning := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "bench_running",
+		Help: "is the benchmark attack executing",
+	}, annoLabels)
+	prometheus.MustRegister(testRunning)
+	testRunning.WithLabelValues(annoValues...).Set(0)
+
+	// Setup our prometheus listener
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", promhttp.Handler())
+	go func() {
+		_ = http.ListenAndServe(":2112", metricsMux)
+	}()
+
+	if conf.PPROFAddr != "" {
+		pprofServer, err := startPprofServer(conf.PPROFAddr)
+		if err != nil {
+			benchmarkLogger.Error("error starting pprof server", "error", hclog.Fmt("%v", err))
+			return 1
+		}
+		defer pprofServer.Close()
+		benchmarkLogger.Info("serving benchmark tool profiles", "address", conf.PPROFAddr)
+	}
 
 	// Create vault clients
 	var clients []*vaultapi.Client
@@ -363,6 +1086,16 @@ func (r *RunCommand) Run(args []string) int {
 		if conf.CAPEMFile != "" {
 			tlsCfg.CACert = conf.CAPEMFile
 		}
+		if conf.TLS != nil {
+			if conf.TLS.CACert != "" {
+				tlsCfg.CACert = conf.TLS.CACert
+			}
+			tlsCfg.CAPath = conf.TLS.CAPath
+			tlsCfg.ClientCert = conf.TLS.ClientCert
+			tlsCfg.ClientKey = conf.TLS.ClientKey
+			tlsCfg.TLSServerName = conf.TLS.TLSServerName
+			tlsCfg.Insecure = conf.TLS.TLSSkipVerify
+		}
 
 		err := cfg.ConfigureTLS(tlsCfg)
 		if err != nil {
@@ -389,6 +1122,40 @@ func (r *RunCommand) Run(args []string) int {
 			cfg.HttpClient.Transport.(*http.Transport).DisableKeepAlives = true
 		}
 
+		// Go's HTTP client asks for gzipped responses and transparently
+		// decompresses them unless compression is disabled
+		if conf.DisableCompress {
+			benchmarkLogger.Warn("response compression disabled")
+			cfg.HttpClient.Transport.(*http.Transport).DisableCompression = true
+		}
+
+		// Keep enough idle connections around for every worker so that
+		// connections are reused rather than re-established during the run.
+		// When load balancing, each worker may hold a connection to every
+		// address.
+		transport := cfg.HttpClient.Transport.(*http.Transport)
+		maxIdleConns := conf.MaxIdleConns
+		if maxIdleConns == 0 {
+			maxIdleConns = max(maxWorkers, transport.MaxIdleConnsPerHost)
+		}
+		transport.MaxIdleConnsPerHost = maxIdleConns
+		totalIdleConns := maxIdleConns
+		if conf.LoadBalance != "" {
+			totalIdleConns *= len(cluster.VaultAddrs)
+		}
+		if transport.MaxIdleConns != 0 && transport.MaxIdleConns < totalIdleConns {
+			transport.MaxIdleConns = totalIdleConns
+		}
+
+		// Check if we're forcing HTTP/2, which also allows benchmarking HTTP/2
+		// against listeners without TLS.
+		if conf.ForceHTTP2 {
+			benchmarkLogger.Warn("forcing http/2")
+			h2Transport := newHTTP2Transport(addr, transport.TLSClientConfig)
+			h2Transport.DisableCompression = conf.DisableCompress
+			cfg.HttpClient.Transport = h2Transport
+		}
+
 		cfg.Address = addr
 		client, err := vaultapi.NewClient(cfg)
 		if err != nil {
@@ -400,7 +1167,71 @@ func (r *RunCommand) Run(args []string) int {
 		clients = append(clients, client)
 	}
 
-	var wg sync.WaitGroup
+	// Benchmark requests are sent through the agent, while tests are set up
+	// and cleaned up directly against Vault
+	attackClients := clients
+	if conf.AgentAddr != "" {
+		agentClient, err := newAgentClient(clients[0], conf.AgentAddr)
+		if err != nil {
+			benchmarkLogger.Error("error creating agent client", "error", hclog.Fmt("%v", err))
+			return 1
+		}
+		benchmarkLogger.Info("sending benchmark requests through agent", "address", conf.AgentAddr)
+		attackClients = []*vaultapi.Client{agentClient}
+	}
+
+	// Warn about local limits on connections, as requests which fail because
+	// of them look like errors from Vault
+	limits := readResourceLimits()
+	benchmarkLogger.Debug("resource limits", "open_files", limits.openFiles, "ephemeral_ports", limits.ephemeralPorts, "gomaxprocs", runtime.GOMAXPROCS(0))
+	for _, warning := range diagnoseResources(limits, connectionPlan{
+		addrs:      len(attackAddrs),
+		workers:    maxWorkers,
+		maxIdle:    conf.MaxIdleConns,
+		keepAlive:  !conf.DisableKeepAlive,
+		rate:       max(conf.RPS, conf.RampStart, conf.RampEnd, conf.MeanRate+conf.Amplitude),
+		closedLoop: conf.ThinkTime != "",
+		unixSocket: len(attackAddrs) == 1 && isUnixAddr(attackAddrs[0]),
+	}) {
+		benchmarkLogger.Warn(warning)
+	}
+
+	if parsedWaitForReady > 0 {
+		benchmarkLogger.Info("waiting for vault to become ready", "timeout", parsedWaitForReady.String())
+		if err := waitForReady(runCtx, clients, parsedWaitForReady, benchmarkLogger); err != nil {
+			benchmarkLogger.Error("vault is not ready", "error", hclog.Fmt("%v", err))
+			return 1
+		}
+	}
+
+	// Record the build of every server so that reports and metrics can be
+	// compared across upgrades. Reports of requests sent through an agent or
+	// load balanced across servers use the first server's build.
+	serverInfo := make(map[string]*benchmarktests.ServerInfo, len(clients))
+	serverInfoGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "bench_server_info",
+		Help: "the version and build date of the servers being benchmarked",
+	}, []string{"address", "version", "build_date"})
+	prometheus.MustRegister(serverInfoGauge)
+	for _, client := range clients {
+		info, err := benchmarktests.ReadServerInfo(client)
+		if err != nil {
+			benchmarkLogger.Warn("error reading server version", "address", client.Address(), "error", err.Error())
+			continue
+		}
+		benchmarkLogger.Info("benchmarking server", "address", client.Address(), "version", info.Version, "build_date", info.BuildDate)
+		serverInfo[client.Address()] = info
+		serverInfoGauge.WithLabelValues(client.Address(), info.Version, info.BuildDate).Set(1)
+	}
+	reportServer := func(addr string) *benchmarktests.ServerInfo {
+		if info, ok := serverInfo[addr]; ok {
+			return info
+		}
+		return serverInfo[clients[0].Address()]
+	}
+
+	var pprofWG sync.WaitGroup
+	var l sync.Mutex
 
 	if parsedPPROFinterval.Seconds() != 0 {
 		_ = os.Setenv("VAULT_ADDR", cluster.VaultAddrs[0])
@@ -408,11 +1239,33 @@ func (r *RunCommand) Run(args []string) int {
 		if conf.CAPEMFile != "" {
 			_ = os.Setenv("VAULT_CACERT", conf.CAPEMFile)
 		}
-		cmd := exec.Command("vault", "debug", "-duration", (2 * parsedDuration).String(),
+		if conf.TLS != nil {
+			tlsEnv := map[string]string{
+				"VAULT_CACERT":          conf.TLS.CACert,
+				"VAULT_CAPATH":          conf.TLS.CAPath,
+				"VAULT_CLIENT_CERT":     conf.TLS.ClientCert,
+				"VAULT_CLIENT_KEY":      conf.TLS.ClientKey,
+				"VAULT_TLS_SERVER_NAME": conf.TLS.TLSServerName,
+			}
+			for k, v := range tlsEnv {
+				if v != "" {
+					_ = os.Setenv(k, v)
+				}
+			}
+			if conf.TLS.TLSSkipVerify {
+				_ = os.Setenv("VAULT_SKIP_VERIFY", "true")
+			}
+		}
+		// Profile every attack, including the repeat of compare_tls_handshake
+		attacks := conf.Runs
+		if conf.CompareTLS {
+			attacks++
+		}
+		cmd := exec.Command("vault", "debug", "-duration", (2 * time.Duration(attacks) * parsedDuration).String(),
 			"-interval", parsedPPROFinterval.String(), "-compress=false")
-		wg.Add(1)
+		pprofWG.Add(1)
 		go func() {
-			defer wg.Done()
+			defer pprofWG.Done()
 			out, err := cmd.CombinedOutput()
 			if err != nil {
 				benchmarkLogger.Error("error running pprof", "error", hclog.Fmt("%v", err))
@@ -425,100 +1278,410 @@ func (r *RunCommand) Run(args []string) int {
 			// want the debug process to wrap things up and write indexes/etc.
 			benchmarkLogger.Info("stopping pprof")
 			cmd.Process.Signal(os.Interrupt)
+			pprofWG.Wait()
 		}()
 	}
 
-	// Enable file audit device at specified path if flag set
-	if conf.AuditPath != "" {
-		err := clients[0].Sys().EnableAuditWithOptions("bench-audit", &vaultapi.EnableAuditOptions{
-			Type: "file",
-			Options: map[string]string{
-				"file_path": conf.AuditPath,
-			},
-		})
-		if err != nil {
-			benchmarkLogger.Error("error enabling audit device", "error", hclog.Fmt("%v", err))
-			return 1
+	// Stop setup or the attack on interrupt so that cleanup still runs and
+	// the results gathered so far are still reported. Cleanup uses runCtx,
+	// which isn't cancelled. A second interrupt exits immediately.
+	ctx, cancel := context.WithCancel(runCtx)
+	defer cancel()
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	var interrupted, attackFailed bool
+	go func() {
+		select {
+		case <-sigCh:
+			benchmarkLogger.Warn("interrupt received, stopping benchmark and reporting partial results")
+			l.Lock()
+			interrupted = true
+			l.Unlock()
+			signal.Stop(sigCh)
+			cancel()
+		case <-ctx.Done():
 		}
-	}
+	}()
 
 	testRunning.WithLabelValues(annoValues...).Set(1)
 	benchmarkLogger.Info("setting up targets")
 
+	// Log the seed so that a run can be reproduced
+	seed := int64(conf.Seed)
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	benchmarkLogger.Info("generating requests", "seed", seed)
+
 	topLevelConfig := benchmarktests.TopLevelTargetConfig{
 		Duration:     parsedDuration,
 		RandomMounts: conf.RandomMounts,
+		SetupRetries: conf.SetupRetries,
+		SkipSetup:    conf.SkipSetup,
+		Namespaces:   namespaces,
+		Audit:        audit,
+		Addrs:        cluster.VaultAddrs,
+		Workers:      maxWorkers,
+		Rand:         benchmarktests.NewRand(seed),
+	}
+
+	// Tests are set up and cleaned up with their own connections when
+	// setup_max_conns is set, and share the attack's otherwise
+	setupClient := clients[0]
+	if conf.SetupMaxConns > 0 {
+		setupClient, err = newSetupClient(clients[0], conf.SetupMaxConns)
+		if err != nil {
+			benchmarkLogger.Error("error creating setup client", "error", hclog.Fmt("%v", err))
+			return 1
+		}
 	}
 
-	tm, err := benchmarktests.BuildTargets(clients[0], conf.Tests, &benchmarkLogger, &topLevelConfig)
+	var tm *benchmarktests.TargetMulti
+	if replay != nil {
+		tm = benchmarktests.NewSourceTargets(replay)
+	} else {
+		tm, err = benchmarktests.BuildTargets(ctx, setupClient, conf.Tests, &benchmarkLogger, &topLevelConfig)
+	}
+
+	// The audit device is disabled once the attack is over whether or not
+	// cleanup is enabled, even if the setup of a later target or the attack
+	// itself fails
+	var disableAuditOnce sync.Once
+	disableAudit := func() {
+		disableAuditOnce.Do(func() {
+			if tm == nil {
+				return
+			}
+			if err := tm.DisableAudit(setupClient); err != nil {
+				benchmarkLogger.Error("error disabling audit device", "error", hclog.Fmt("%v", err))
+			}
+		})
+	}
+	defer disableAudit()
+
+	// Make sure every target that was set up gets cleaned up, even if the
+	// setup of a later target or the attack itself fails
+	var cleanupOnce sync.Once
+	cleanup := func() {
+		cleanupOnce.Do(func() {
+			disableAudit()
+			if !conf.Cleanup || tm == nil {
+				return
+			}
+			if conf.SkipCleanup {
+				benchmarkLogger.Info("skipping cleanup, resources are kept for inspection")
+				return
+			}
+			benchmarkLogger.Info("cleaning up targets")
+			if err := tm.Cleanup(runCtx, setupClient); err != nil {
+				benchmarkLogger.Error("cleanup error", "err", hclog.Fmt("%v", err))
+			}
+		})
+	}
+	defer cleanup()
+
 	if err != nil {
 		benchmarkLogger.Error(fmt.Sprintf("target setup failed: %v", err))
 		return 1
 	}
 
-	var l sync.Mutex
+	var consumers []benchmarktests.ResultConsumer
+	if conf.StatsdAddr != "" {
+		statsdConsumer, err := newStatsdConsumer(conf.StatsdAddr, conf.StatsdPrefix, conf.StatsdTags)
+		if err != nil {
+			benchmarkLogger.Error("error configuring statsd", "error", hclog.Fmt("%v", err))
+			return 1
+		}
+		defer func() {
+			if err := statsdConsumer.Close(); err != nil {
+				benchmarkLogger.Error("error flushing statsd metrics", "error", hclog.Fmt("%v", err))
+			}
+		}()
+		consumers = append(consumers, statsdConsumer)
+	}
+	// Distributed runs are reported from the histograms of every runner
+	var histograms *histogramConsumer
+	if conf.HistogramFile != "" || runLeader != nil || runFollower != nil {
+		histograms = newHistogramConsumer()
+		consumers = append(consumers, histograms)
+	}
+	if conf.HistogramFile != "" {
+		defer func() {
+			if err := histograms.WriteFile(conf.HistogramFile); err != nil {
+				benchmarkLogger.Error("error writing latency histograms", "error", hclog.Fmt("%v", err))
+			}
+		}()
+	}
+	if conf.ResultsFile != "" {
+		resultsConsumer, err := newResultsFileConsumer(conf.ResultsFile)
+		if err != nil {
+			benchmarkLogger.Error("error configuring results_file", "error", hclog.Fmt("%v", err))
+			return 1
+		}
+		defer func() {
+			if err := resultsConsumer.Close(); err != nil {
+				benchmarkLogger.Error("error writing results file", "error", hclog.Fmt("%v", err))
+			}
+		}()
+		consumers = append(consumers, resultsConsumer)
+	}
+	if parsedProgressInterval > 0 {
+		progressConsumer := newProgressConsumer(benchmarkLogger.Named("progress"), parsedProgressInterval)
+		defer progressConsumer.Close()
+		consumers = append(consumers, progressConsumer)
+	}
+
+	// When load balancing, a single attack spreads its requests across every
+	// address rather than each address being attacked separately
+	if conf.LoadBalance != "" {
+		if err := tm.LoadBalance(cluster.VaultAddrs, conf.LoadBalance); err != nil {
+			benchmarkLogger.Error("error configuring load balancing", "error", hclog.Fmt("%v", err))
+			return 1
+		}
+		attackClients = clients[:1]
+	}
+
+	// Every runner of a distributed run starts attacking at the same time,
+	// once all of them have set up their tests
+	var startAt time.Time
+	switch {
+	case runLeader != nil:
+		benchmarkLogger.Info("waiting for followers to be ready")
+		startAt, err = runLeader.Ready(ctx)
+	case runFollower != nil:
+		benchmarkLogger.Info("waiting for leader to start")
+		startAt, err = runFollower.Ready(ctx)
+	}
+	if err != nil {
+		benchmarkLogger.Error("error starting distributed run", "error", hclog.Fmt("%v", err))
+		return 1
+	}
+	if !startAt.IsZero() {
+		benchmarkLogger.Info("starting distributed run", "start_at", startAt.Format(time.RFC3339Nano))
+		_ = waitUntil(ctx, startAt)
+	}
+
+	// Memory is sampled directly from the Vault servers, and from the start
+	// of the first run to the end of the last
+	var memory *memorySampler
+	if parsedMemoryInterval > 0 {
+		memory = newMemorySampler(clients, benchmarkLogger.Named("memory"), parsedMemoryInterval)
+	}
+
+	// The tests are set up once and attacked runs times, and each address
+	// keeps the report of every run. results holds the reports of the last,
+	// and completeRuns those of the runs which weren't cut short, which are
+	// the only ones summarized.
+	runResults := make(map[string][]*benchmarktests.Reporter)
+	completeRuns := make(map[string][]*benchmarktests.Reporter)
 	results := make(map[string]*benchmarktests.Reporter)
-	benchmarkLogger.Info("starting benchmarks", "duration", hclog.Fmt("%v", parsedDuration.String()))
-	for _, client := range clients {
-		wg.Add(1)
-		go func(client *vaultapi.Client) {
-			defer wg.Done()
+	if conf.Requests != 0 {
+		benchmarkLogger.Info("starting benchmarks", "requests", conf.Requests)
+	} else {
+		benchmarkLogger.Info("starting benchmarks", "duration", hclog.Fmt("%v", parsedDuration.String()))
+	}
+	for run := 1; run <= conf.Runs && ctx.Err() == nil; run++ {
+		if conf.Runs > 1 {
+			benchmarkLogger.Info("starting run", "run", run, "runs", conf.Runs)
+		}
+		var wg sync.WaitGroup
+		for _, client := range attackClients {
+			wg.Add(1)
+			go func(client *vaultapi.Client) {
+				defer wg.Done()
+				defer func() {
+					if rec := recover(); rec != nil {
+						benchmarkLogger.Error("attack panicked", "client", client.Address(), "panic", hclog.Fmt("%v", rec))
+						l.Lock()
+						attackFailed = true
+						l.Unlock()
+					}
+				}()
 
-			if r.flagDebug {
-				if !benchmarkLogger.IsTrace() {
-					benchmarkLogger.SetLevel(hclog.Debug)
+				if r.flagDebug && run == 1 {
+					if !benchmarkLogger.IsTrace() {
+						benchmarkLogger.SetLevel(hclog.Debug)
+					}
+					l.Lock()
+					benchmarkLogger.Debug("=== Debug Info ===")
+					benchmarkLogger.Debug(fmt.Sprintf("Client: %s", client.Address()))
+					err := tm.DebugInfo(client)
+					if err != nil {
+						benchmarkLogger.Error("debug request failed", "client", client.Address(), "error", hclog.Fmt("%v", err))
+						attackFailed = true
+						l.Unlock()
+						return
+					}
+					l.Unlock()
 				}
+
+				rpt, err := benchmarktests.Attack(ctx, tm, client, parsedDuration, pacer, conf.Workers, maxWorkers, parsedRequestTimeout, conf.ErrorBodies, consumers...)
+				if err != nil {
+					benchmarkLogger.Error("attack error", "err", hclog.Fmt("%v", err))
+					l.Lock()
+					attackFailed = true
+					l.Unlock()
+					return
+				}
+				rpt.SetServerInfo(reportServer(client.Address()))
+
 				l.Lock()
-				benchmarkLogger.Debug("=== Debug Info ===")
-				benchmarkLogger.Debug(fmt.Sprintf("Client: %s", client.Address()))
-				tm.DebugInfo(client)
+				// TODO rethink how we present results when multiple nodes are attacked
+				runResults[client.Address()] = append(runResults[client.Address()], rpt)
+				if ctx.Err() == nil {
+					completeRuns[client.Address()] = append(completeRuns[client.Address()], rpt)
+				}
+				results[client.Address()] = rpt
 				l.Unlock()
-			}
+			}(client)
+		}
 
-			rpt, err := benchmarktests.Attack(tm, client, parsedDuration, conf.RPS, conf.Workers)
-			if err != nil {
-				benchmarkLogger.Error("attack error", "err", hclog.Fmt("%v", err))
-				os.Exit(1)
-			}
+		wg.Wait()
+	}
+	if memory != nil {
+		memory.Close()
+	}
 
-			l.Lock()
-			// TODO rethink how we present results when multiple nodes are attacked
-			results[client.Address()] = rpt
-			l.Unlock()
+	// Followers send their results before cleaning up so as not to hold up
+	// the leader's report
+	if runFollower != nil {
+		if err := runFollower.SendResults(context.Background(), histograms.Histograms()); err != nil {
+			benchmarkLogger.Error("error reporting to leader", "error", hclog.Fmt("%v", err))
+			attackFailed = true
+		}
+	}
 
-			if conf.Cleanup {
-				benchmarkLogger.Info("cleaning up targets")
-				err := tm.Cleanup(client)
+	// Repeat the attack opening a new connection for every request, so that
+	// the cost of the TLS handshake can be compared with reusing connections
+	handshakeResults := make(map[string]*benchmarktests.Reporter)
+	if conf.CompareTLS && ctx.Err() == nil {
+		benchmarkLogger.Info("repeating benchmarks with a new connection per request")
+		var wg sync.WaitGroup
+		for _, client := range attackClients {
+			if _, ok := results[client.Address()]; !ok {
+				continue
+			}
+			wg.Add(1)
+			go func(client *vaultapi.Client) {
+				defer wg.Done()
+				handshakeClient, err := newHandshakeClient(client)
 				if err != nil {
-					benchmarkLogger.Error("cleanup error", "err", hclog.Fmt("%v", err))
+					benchmarkLogger.Error("error creating handshake client", "error", hclog.Fmt("%v", err))
+					l.Lock()
+					attackFailed = true
+					l.Unlock()
+					return
 				}
-				if conf.AuditPath != "" {
-					_, err := client.Logical().Delete("/sys/audit/bench-audit")
-					if err != nil {
-						benchmarkLogger.Error("error disabling bench-audit audit device", "error", hclog.Fmt("%v", err))
-					}
+
+				rpt, err := benchmarktests.Attack(ctx, tm, handshakeClient, parsedDuration, pacer, conf.Workers, maxWorkers, parsedRequestTimeout, 0)
+				if err != nil {
+					benchmarkLogger.Error("attack error", "err", hclog.Fmt("%v", err))
+					l.Lock()
+					attackFailed = true
+					l.Unlock()
+					return
 				}
-			}
-		}(client)
-	}
+				rpt.SetServerInfo(reportServer(client.Address()))
 
-	wg.Wait()
+				l.Lock()
+				handshakeResults[client.Address()] = rpt
+				l.Unlock()
+			}(client)
+		}
+		wg.Wait()
+	}
+	cleanup()
 
 	testRunning.WithLabelValues(annoValues...).Set(0)
 	benchmarkLogger.Info("benchmark complete")
-	for _, client := range clients {
+	for _, client := range attackClients {
 		addr := client.Address()
-		rpt := results[addr]
-		switch conf.ReportMode {
-		case "json":
-			rpt.ReportJSON(os.Stdout)
-		case "verbose":
-			rpt.ReportVerbose(os.Stdout)
-		default:
-			rpt.ReportTerse(os.Stdout)
+		rpt, ok := results[addr]
+		if !ok {
+			continue
+		}
+		for _, runRpt := range runResults[addr] {
+			switch conf.ReportMode {
+			case "json":
+				runRpt.ReportJSON(os.Stdout)
+			case "verbose":
+				runRpt.ReportVerbose(os.Stdout)
+			default:
+				runRpt.ReportTerse(os.Stdout)
+			}
+			fmt.Println()
+		}
+
+		if conf.Runs > 1 && len(completeRuns[addr]) > 0 {
+			if err := reportRuns(os.Stdout, conf.ReportMode, addr, completeRuns[addr]); err != nil {
+				benchmarkLogger.Error("error reporting runs", "error", hclog.Fmt("%v", err))
+			}
+			fmt.Println()
+		}
+
+		if handshakeRpt, ok := handshakeResults[addr]; ok {
+			if err := reportHandshakes(os.Stdout, conf.ReportMode, addr, rpt, handshakeRpt); err != nil {
+				benchmarkLogger.Error("error reporting tls handshake cost", "error", hclog.Fmt("%v", err))
+			}
+			fmt.Println()
+		}
+	}
+
+	// Memory is reported for every server, including those requests were
+	// load balanced across or sent to through an agent
+	if memory != nil {
+		for _, client := range clients {
+			if err := reportMemory(os.Stdout, conf.ReportMode, client.Address(), memory.Samples(client.Address())); err != nil {
+				benchmarkLogger.Error("error reporting memory", "error", hclog.Fmt("%v", err))
+			}
+			fmt.Println()
+		}
+	}
+
+	if runLeader != nil {
+		benchmarkLogger.Info("waiting for follower results")
+		resultsCtx, resultsCancel := context.WithTimeout(context.Background(), coordinateResultsTimeout)
+		followerResults, err := runLeader.Results(resultsCtx)
+		resultsCancel()
+		if err != nil {
+			benchmarkLogger.Error("not every follower sent its results, reporting those received", "received", len(followerResults), "followers", conf.Followers)
+			attackFailed = true
+		}
+
+		runners := []map[string]*latencyHistogram{histograms.Histograms()}
+		for _, runnerResults := range followerResults {
+			runners = append(runners, runnerResults)
+		}
+		if err := reportDistributed(os.Stdout, conf.ReportMode, len(runners), mergeHistograms(runners...)); err != nil {
+			benchmarkLogger.Error("error reporting distributed results", "error", hclog.Fmt("%v", err))
 		}
 		fmt.Println()
 	}
+
+	// SLOs must hold in every run
+	sloFailed := false
+	for _, client := range attackClients {
+		for i, rpt := range runResults[client.Address()] {
+			for _, slo := range slos {
+				actual, ok := slo.check(rpt)
+				if ok {
+					continue
+				}
+				sloFailed = true
+				benchmarkLogger.Error("slo assertion failed", "address", client.Address(), "run", i+1, "test", slo.target, "slo", slo.String(), "actual", slo.format(actual), "missed_by", slo.miss(actual))
+			}
+		}
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	if interrupted {
+		benchmarkLogger.Warn("benchmark was interrupted, results are partial")
+		return 1
+	}
+	if attackFailed || sloFailed {
+		return 1
+	}
 	return 0
 }
 
@@ -530,6 +1693,20 @@ func (r *RunCommand) applyConfigOverrides(f *FlagSets, config *vbConfig.VaultBen
 	})
 	config.PPROFInterval = r.flagPPROFInterval.String()
 
+	r.setStringFlag(f, config.PPROFAddr, &StringVar{
+		Name:    "pprof_addr",
+		Target:  &r.flagPPROFAddr,
+		Default: "",
+	})
+	config.PPROFAddr = r.flagPPROFAddr
+
+	r.setDurationFlag(f, config.WaitForReady, &DurationVar{
+		Name:    "wait_for_ready",
+		Target:  &r.flagWaitForReady,
+		Default: 0,
+	})
+	config.WaitForReady = r.flagWaitForReady.String()
+
 	r.setDurationFlag(f, config.Duration, &DurationVar{
 		Name:    "duration",
 		Target:  &r.flagDuration,
@@ -551,6 +1728,124 @@ func (r *RunCommand) applyConfigOverrides(f *FlagSets, config *vbConfig.VaultBen
 	})
 	config.Workers = r.flagWorkers
 
+	r.setIntFlag(f, config.MaxWorkers, &IntVar{
+		Name:    "max_workers",
+		Target:  &r.flagMaxWorkers,
+		Default: 0,
+	})
+	config.MaxWorkers = r.flagMaxWorkers
+
+	r.setIntFlag(f, config.GOMAXPROCS, &IntVar{
+		Name:    "gomaxprocs",
+		Target:  &r.flagGOMAXPROCS,
+		Default: 0,
+	})
+	config.GOMAXPROCS = r.flagGOMAXPROCS
+
+	r.setIntFlag(f, config.Requests, &IntVar{
+		Name:    "requests",
+		Target:  &r.flagRequests,
+		Default: 0,
+	})
+	config.Requests = r.flagRequests
+
+	r.setIntFlag(f, config.Runs, &IntVar{
+		Name:    "runs",
+		Target:  &r.flagRuns,
+		Default: 1,
+	})
+	config.Runs = r.flagRuns
+
+	r.setIntFlag(f, config.RampStart, &IntVar{
+		Name:    "ramp_start",
+		Target:  &r.flagRampStart,
+		Default: 0,
+	})
+	config.RampStart = r.flagRampStart
+
+	r.setIntFlag(f, config.RampEnd, &IntVar{
+		Name:    "ramp_end",
+		Target:  &r.flagRampEnd,
+		Default: 0,
+	})
+	config.RampEnd = r.flagRampEnd
+
+	r.setDurationFlag(f, config.RampDuration, &DurationVar{
+		Name:    "ramp_duration",
+		Target:  &r.flagRampDuration,
+		Default: 0,
+	})
+	if r.flagRampDuration != 0 {
+		config.RampDuration = r.flagRampDuration.String()
+	}
+
+	r.setIntFlag(f, config.MeanRate, &IntVar{
+		Name:    "mean_rate",
+		Target:  &r.flagMeanRate,
+		Default: 0,
+	})
+	config.MeanRate = r.flagMeanRate
+
+	r.setIntFlag(f, config.Amplitude, &IntVar{
+		Name:    "amplitude",
+		Target:  &r.flagAmplitude,
+		Default: 0,
+	})
+	config.Amplitude = r.flagAmplitude
+
+	r.setDurationFlag(f, config.Period, &DurationVar{
+		Name:    "period",
+		Target:  &r.flagPeriod,
+		Default: 0,
+	})
+	if r.flagPeriod != 0 {
+		config.Period = r.flagPeriod.String()
+	}
+
+	r.setDurationFlag(f, config.RequestTimeout, &DurationVar{
+		Name:    "request_timeout",
+		Target:  &r.flagRequestTimeout,
+		Default: 0,
+	})
+	if r.flagRequestTimeout != 0 {
+		config.RequestTimeout = r.flagRequestTimeout.String()
+	}
+
+	r.setDurationFlag(f, config.ProgressInterval, &DurationVar{
+		Name:    "progress_interval",
+		Target:  &r.flagProgressInterval,
+		Default: 0,
+	})
+	if r.flagProgressInterval != 0 {
+		config.ProgressInterval = r.flagProgressInterval.String()
+	}
+
+	r.setDurationFlag(f, config.MemoryInterval, &DurationVar{
+		Name:    "memory_sample_interval",
+		Target:  &r.flagMemoryInterval,
+		Default: 0,
+	})
+	if r.flagMemoryInterval != 0 {
+		config.MemoryInterval = r.flagMemoryInterval.String()
+	}
+
+	if r.isFlagSet(f, "slo") {
+		config.SLO = r.flagSLO
+	}
+
+	if r.isFlagSet(f, "filter_tags") {
+		config.FilterTags = r.flagFilterTags
+	}
+
+	r.setDurationFlag(f, config.ThinkTime, &DurationVar{
+		Name:    "think_time",
+		Target:  &r.flagThinkTime,
+		Default: 0,
+	})
+	if r.flagThinkTime != 0 {
+		config.ThinkTime = r.flagThinkTime.String()
+	}
+
 	r.setStringFlag(f, config.VaultToken, &StringVar{
 		Name:    "vault_token",
 		EnvVar:  "VAULT_TOKEN",
@@ -567,6 +1862,27 @@ func (r *RunCommand) applyConfigOverrides(f *FlagSets, config *vbConfig.VaultBen
 	})
 	config.VaultAddr = r.flagVaultAddr
 
+	r.setStringFlag(f, config.VaultAddrs, &StringVar{
+		Name:    "vault_addrs",
+		Target:  &r.flagVaultAddrs,
+		Default: "",
+	})
+	config.VaultAddrs = r.flagVaultAddrs
+
+	r.setStringFlag(f, config.AgentAddr, &StringVar{
+		Name:    "agent_addr",
+		Target:  &r.flagAgentAddr,
+		Default: "",
+	})
+	config.AgentAddr = r.flagAgentAddr
+
+	r.setStringFlag(f, config.LoadBalance, &StringVar{
+		Name:    "load_balance",
+		Target:  &r.flagLoadBalance,
+		Default: "",
+	})
+	config.LoadBalance = r.flagLoadBalance
+
 	r.setStringFlag(f, config.VaultNamespace, &StringVar{
 		Name:    "vault_namespace",
 		EnvVar:  "VAULT_NAMESPACE",
@@ -582,6 +1898,20 @@ func (r *RunCommand) applyConfigOverrides(f *FlagSets, config *vbConfig.VaultBen
 	})
 	config.ReportMode = r.flagReportMode
 
+	r.setIntFlag(f, config.ErrorBodies, &IntVar{
+		Name:    "error_bodies",
+		Target:  &r.flagErrorBodies,
+		Default: 0,
+	})
+	config.ErrorBodies = r.flagErrorBodies
+
+	r.setIntFlag(f, config.Seed, &IntVar{
+		Name:    "seed",
+		Target:  &r.flagSeed,
+		Default: 0,
+	})
+	config.Seed = r.flagSeed
+
 	r.setStringFlag(f, config.Annotate, &StringVar{
 		Name:    "annotate",
 		Target:  &r.flagAnnotate,
@@ -596,6 +1926,83 @@ func (r *RunCommand) applyConfigOverrides(f *FlagSets, config *vbConfig.VaultBen
 	})
 	config.AuditPath = r.flagAuditPath
 
+	r.setStringFlag(f, config.OTLPEndpoint, &StringVar{
+		Name:    "otlp_endpoint",
+		Target:  &r.flagOTLPEndpoint,
+		Default: "",
+	})
+	config.OTLPEndpoint = r.flagOTLPEndpoint
+
+	r.setStringFlag(f, config.HistogramFile, &StringVar{
+		Name:    "histogram_file",
+		Target:  &r.flagHistogramFile,
+		Default: "",
+	})
+	config.HistogramFile = r.flagHistogramFile
+
+	r.setStringFlag(f, config.ReplayFile, &StringVar{
+		Name:    "replay_file",
+		Target:  &r.flagReplayFile,
+		Default: "",
+	})
+	config.ReplayFile = r.flagReplayFile
+
+	r.setStringFlag(f, config.ReplayFormat, &StringVar{
+		Name:    "replay_format",
+		Target:  &r.flagReplayFormat,
+		Default: "",
+	})
+	config.ReplayFormat = r.flagReplayFormat
+
+	r.setStringFlag(f, config.ResultsFile, &StringVar{
+		Name:    "results_file",
+		Target:  &r.flagResultsFile,
+		Default: "",
+	})
+	config.ResultsFile = r.flagResultsFile
+
+	r.setStringFlag(f, config.LeaderListen, &StringVar{
+		Name:    "leader_listen",
+		Target:  &r.flagLeaderListen,
+		Default: "",
+	})
+	config.LeaderListen = r.flagLeaderListen
+
+	r.setIntFlag(f, config.Followers, &IntVar{
+		Name:    "followers",
+		Target:  &r.flagFollowers,
+		Default: 0,
+	})
+	config.Followers = r.flagFollowers
+
+	r.setStringFlag(f, config.LeaderAddr, &StringVar{
+		Name:    "leader_addr",
+		Target:  &r.flagLeaderAddr,
+		Default: "",
+	})
+	config.LeaderAddr = r.flagLeaderAddr
+
+	r.setStringFlag(f, config.StatsdAddr, &StringVar{
+		Name:    "statsd_addr",
+		Target:  &r.flagStatsdAddr,
+		Default: "",
+	})
+	config.StatsdAddr = r.flagStatsdAddr
+
+	r.setStringFlag(f, config.StatsdPrefix, &StringVar{
+		Name:    "statsd_prefix",
+		Target:  &r.flagStatsdPrefix,
+		Default: "vault_benchmark",
+	})
+	config.StatsdPrefix = r.flagStatsdPrefix
+
+	r.setBoolFlag(f, config.StatsdTags, &BoolVar{
+		Name:    "statsd_tags",
+		Target:  &r.flagStatsdTags,
+		Default: false,
+	})
+	config.StatsdTags = r.flagStatsdTags
+
 	r.setStringFlag(f, config.CAPEMFile, &StringVar{
 		Name:    "ca_pem_file",
 		EnvVar:  "VAULT_CACERT",
@@ -618,6 +2025,20 @@ func (r *RunCommand) applyConfigOverrides(f *FlagSets, config *vbConfig.VaultBen
 	})
 	config.Cleanup = r.flagCleanup
 
+	r.setBoolFlag(f, config.SkipCleanup, &BoolVar{
+		Name:    "skip_cleanup",
+		Target:  &r.flagSkipCleanup,
+		Default: false,
+	})
+	config.SkipCleanup = r.flagSkipCleanup
+
+	r.setBoolFlag(f, config.SkipSetup, &BoolVar{
+		Name:    "skip_setup",
+		Target:  &r.flagSkipSetup,
+		Default: false,
+	})
+	config.SkipSetup = r.flagSkipSetup
+
 	r.setBoolFlag(f, config.RandomMounts, &BoolVar{
 		Name:    "random_mounts",
 		Target:  &r.flagRandomMounts,
@@ -625,6 +2046,22 @@ func (r *RunCommand) applyConfigOverrides(f *FlagSets, config *vbConfig.VaultBen
 	})
 	config.RandomMounts = r.flagRandomMounts
 
+	// The config already defaults to 3 retries, so a setup_retries of 0 in
+	// the config turns retries off rather than falling back to the default
+	r.setIntFlag(f, config.SetupRetries, &IntVar{
+		Name:    "setup_retries",
+		Target:  &r.flagSetupRetries,
+		Default: config.SetupRetries,
+	})
+	config.SetupRetries = r.flagSetupRetries
+
+	r.setIntFlag(f, config.SetupMaxConns, &IntVar{
+		Name:    "setup_max_conns",
+		Target:  &r.flagSetupMaxConns,
+		Default: 0,
+	})
+	config.SetupMaxConns = r.flagSetupMaxConns
+
 	r.setStringFlag(f, config.LogLevel, &StringVar{
 		Name:    "log_level",
 		Target:  &r.flagLogLevel,
@@ -646,6 +2083,45 @@ func (r *RunCommand) applyConfigOverrides(f *FlagSets, config *vbConfig.VaultBen
 		Default: false,
 	})
 	config.DisableKeepAlive = r.flagDisableKeepAlive
+
+	r.setBoolFlag(f, config.DisableCompress, &BoolVar{
+		Name:    "disable_compression",
+		Target:  &r.flagDisableCompress,
+		Default: false,
+	})
+	config.DisableCompress = r.flagDisableCompress
+
+	r.setBoolFlag(f, config.ForceHTTP2, &BoolVar{
+		Name:    "force_http2",
+		Target:  &r.flagForceHTTP2,
+		Default: false,
+	})
+	config.ForceHTTP2 = r.flagForceHTTP2
+
+	r.setBoolFlag(f, config.CompareTLS, &BoolVar{
+		Name:    "compare_tls_handshake",
+		Target:  &r.flagCompareTLS,
+		Default: false,
+	})
+	config.CompareTLS = r.flagCompareTLS
+
+	r.setIntFlag(f, config.MaxIdleConns, &IntVar{
+		Name:    "max_idle_conns_per_host",
+		Target:  &r.flagMaxIdleConns,
+		Default: 0,
+	})
+	config.MaxIdleConns = r.flagMaxIdleConns
+}
+
+// isFlagSet reports whether the named flag was passed on the command line
+func (r *RunCommand) isFlagSet(f *FlagSets, name string) bool {
+	var isFlagSet bool
+	f.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			isFlagSet = true
+		}
+	})
+	return isFlagSet
 }
 
 func (r *RunCommand) setBoolFlag(f *FlagSets, configVal bool, fVar *BoolVar) {
@@ -0,0 +1,144 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/openbao/benchmark-openbao/benchmarktests"
+	vbConfig "github.com/openbao/benchmark-openbao/config"
+)
+
+// checkRepeatedAttacks checks that no test uses up the resources it set up
+// when the tests are attacked more than once, by repeated runs or by the
+// repeat of compare_tls_handshake, as the attacks after the first would
+// skew the results
+func checkRepeatedAttacks(conf *vbConfig.VaultBenchmarkCoreConfig) error {
+	var option string
+	switch {
+	case conf.Runs > 1:
+		option = "runs"
+	case conf.CompareTLS:
+		option = "compare_tls_handshake"
+	default:
+		return nil
+	}
+
+	for _, test := range conf.Tests {
+		if consumer, ok := test.Builder.(benchmarktests.SetupConsumer); ok && consumer.ConsumesSetup() {
+			return fmt.Errorf("%v cannot be combined with tests which use up the resources they set up, such as %v (%v)", option, test.Name, test.Type)
+		}
+	}
+	return nil
+}
+
+// runMetrics are the metrics summarized across repeated runs, in the order
+// they are reported
+var runMetrics = []string{"throughput", "success_ratio", "mean", "p50", "p95", "p99"}
+
+// runStat is the mean and sample standard deviation of a metric across runs
+type runStat struct {
+	Mean   float64 `json:"mean"`
+	Stddev float64 `json:"stddev"`
+}
+
+// summarizeRuns returns the spread of each of runMetrics for every target,
+// across the runs which sent requests to it
+func summarizeRuns(runs []*benchmarktests.Reporter) map[string]map[string]*runStat {
+	values := make(map[string]map[string][]float64)
+	for _, rpt := range runs {
+		for _, name := range rpt.MetricNames() {
+			m, _ := rpt.Metrics(name)
+			if m.Requests == 0 {
+				continue
+			}
+			if values[name] == nil {
+				values[name] = make(map[string][]float64)
+			}
+			for _, metric := range runMetrics {
+				values[name][metric] = append(values[name][metric], metricValue(m, metric))
+			}
+		}
+	}
+
+	summary := make(map[string]map[string]*runStat, len(values))
+	for name, metrics := range values {
+		summary[name] = make(map[string]*runStat, len(metrics))
+		for metric, vs := range metrics {
+			summary[name][metric] = newRunStat(vs)
+		}
+	}
+	return summary
+}
+
+func newRunStat(vs []float64) *runStat {
+	var sum float64
+	for _, v := range vs {
+		sum += v
+	}
+	stat := &runStat{Mean: sum / float64(len(vs))}
+	if len(vs) < 2 {
+		return stat
+	}
+
+	var squares float64
+	for _, v := range vs {
+		squares += (v - stat.Mean) * (v - stat.Mean)
+	}
+	stat.Stddev = math.Sqrt(squares / float64(len(vs)-1))
+	return stat
+}
+
+// formatRunStat formats the mean and standard deviation of a metric
+func formatRunStat(metric string, stat *runStat) string {
+	if sloMetrics[metric] {
+		return fmt.Sprintf("%v ± %v", time.Duration(stat.Mean).Round(time.Microsecond), time.Duration(stat.Stddev).Round(time.Microsecond))
+	}
+	return strconv.FormatFloat(stat.Mean, 'g', 6, 64) + " ± " + strconv.FormatFloat(stat.Stddev, 'g', 6, 64)
+}
+
+// reportRuns reports the mean and standard deviation of the key metrics of
+// each target of the address across every run, in the given report mode.
+// Latencies in JSON reports are in nanoseconds.
+func reportRuns(w io.Writer, mode, addr string, runs []*benchmarktests.Reporter) error {
+	summary := summarizeRuns(runs)
+	if mode == "json" {
+		return json.NewEncoder(w).Encode(&struct {
+			Type       string                         `json:"type"`
+			TargetAddr string                         `json:"target_addr"`
+			Runs       int                            `json:"runs"`
+			Summary    map[string]map[string]*runStat `json:"summary"`
+		}{
+			Type:       "runs",
+			TargetAddr: addr,
+			Runs:       len(runs),
+			Summary:    summary,
+		})
+	}
+
+	names := make([]string, 0, len(summary))
+	for name := range summary {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.StripEscape)
+	fmt.Fprintf(tw, "Summary of %d runs: %v\n", len(runs), addr)
+	fmt.Fprintf(tw, "op\tthroughput\tsuccess ratio\tmean\t50th%%\t95th%%\t99th%%\n")
+	for _, name := range names {
+		fmt.Fprintf(tw, "%s", name)
+		for _, metric := range runMetrics {
+			fmt.Fprintf(tw, "\t%s", formatRunStat(metric, summary[name][metric]))
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
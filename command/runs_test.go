@@ -0,0 +1,86 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openbao/benchmark-openbao/benchmarktests"
+	vbConfig "github.com/openbao/benchmark-openbao/config"
+)
+
+func TestSummarizeRuns(t *testing.T) {
+	reports, err := benchmarktests.FromReader(strings.NewReader(`
+{"target_addr": "https://127.0.0.1:8200", "metrics": {"read": {"requests": 10, "throughput": 90, "success": 1, "latencies": {"mean": 2000000, "99th": 4000000}}}}
+{"target_addr": "https://127.0.0.1:8200", "metrics": {"read": {"requests": 10, "throughput": 110, "success": 1, "latencies": {"mean": 4000000, "99th": 8000000}}}}
+{"target_addr": "https://127.0.0.1:8200", "metrics": {"read": {"requests": 0}}}
+`))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Runs which sent no requests to a target are left out
+	read := summarizeRuns(reports)["read"]
+	if read["throughput"].Mean != 100 || math.Abs(read["throughput"].Stddev-math.Sqrt(200)) > 1e-9 {
+		t.Fatalf("unexpected throughput: %+v", read["throughput"])
+	}
+	if time.Duration(read["p99"].Mean) != 6*time.Millisecond || read["success_ratio"].Stddev != 0 {
+		t.Fatalf("unexpected latencies: p99 %+v, success ratio %+v", read["p99"], read["success_ratio"])
+	}
+}
+
+func TestReportRuns_JSONSkipped(t *testing.T) {
+	report := `{"target_addr": "https://127.0.0.1:8200", "metrics": {"read": {"requests": 10, "throughput": 90, "success": 1}}}` + "\n"
+	reports, err := benchmarktests.FromReader(strings.NewReader(report))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// The summary is written to the same stream as the reports, but isn't
+	// read back as one
+	var buf strings.Builder
+	buf.WriteString(report)
+	if err := reportRuns(&buf, "json", "https://127.0.0.1:8200", reports); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"type":"runs"`) {
+		t.Fatalf("expected summary type, got: %v", buf.String())
+	}
+	reports, err = benchmarktests.FromReader(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("expected 1 report, got: %d", len(reports))
+	}
+}
+
+func TestCheckRepeatedAttacks(t *testing.T) {
+	conf := vbConfig.NewVaultBenchmarkCoreConfig()
+	conf.Tests = []*benchmarktests.BenchmarkTarget{
+		{Name: "namespace_delete_test", Type: benchmarktests.NamespaceDeleteType, Builder: benchmarktests.TestList[benchmarktests.NamespaceDeleteType]()},
+	}
+
+	// Tests which use up what they set up can only be attacked once
+	if err := checkRepeatedAttacks(conf); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	conf.Runs = 2
+	if err := checkRepeatedAttacks(conf); err == nil {
+		t.Fatal("expected error combining runs with namespace_delete")
+	}
+	conf.Runs = 1
+	conf.CompareTLS = true
+	if err := checkRepeatedAttacks(conf); err == nil {
+		t.Fatal("expected error combining compare_tls_handshake with namespace_delete")
+	}
+
+	conf.Tests[0].Builder = benchmarktests.TestList[benchmarktests.NamespaceType]()
+	if err := checkRepeatedAttacks(conf); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openbao/benchmark-openbao/benchmarktests"
+	vbConfig "github.com/openbao/benchmark-openbao/config"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+// sloTotal is the name of the report section covering every target
+const sloTotal = "total"
+
+var sloPattern = regexp.MustCompile(`^([a-z0-9_]+)\s*(<=|>=|<|>)\s*(\S+)$`)
+
+// sloMetrics are the metrics assertions can be made about, and whether each
+// is a latency
+var sloMetrics = map[string]bool{
+	"mean":          true,
+	"p50":           true,
+	"p90":           true,
+	"p95":           true,
+	"p99":           true,
+	"max":           true,
+	"success_ratio": false,
+	"rate":          false,
+	"throughput":    false,
+}
+
+// sloAssertion is a bound on a metric of a target's report, such as
+// "p99 < 50ms"
+type sloAssertion struct {
+	target    string
+	metric    string
+	op        string
+	threshold float64
+	latency   bool
+}
+
+// parseSLO parses an assertion about the named target
+func parseSLO(target, expr string) (*sloAssertion, error) {
+	m := sloPattern.FindStringSubmatch(strings.TrimSpace(expr))
+	if m == nil {
+		return nil, fmt.Errorf("invalid slo %q, expected an assertion such as \"p99 < 50ms\"", expr)
+	}
+
+	a := &sloAssertion{target: target, metric: m[1], op: m[2]}
+	latency, ok := sloMetrics[a.metric]
+	if !ok {
+		return nil, fmt.Errorf("invalid slo %q, unknown metric %q", expr, a.metric)
+	}
+	a.latency = latency
+
+	if latency {
+		d, err := time.ParseDuration(m[3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid slo %q: %v", expr, err)
+		}
+		a.threshold = float64(d)
+	} else {
+		f, err := strconv.ParseFloat(m[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slo %q: %v", expr, err)
+		}
+		a.threshold = f
+	}
+	return a, nil
+}
+
+// parseSLOs collects the assertions of every test along with the global
+// ones, which are of the form "<target>: <assertion>"
+func parseSLOs(conf *vbConfig.VaultBenchmarkCoreConfig) ([]*sloAssertion, error) {
+	targets := map[string]bool{sloTotal: true}
+	var slos []*sloAssertion
+	for _, test := range conf.Tests {
+		targets[test.Name] = true
+		for _, expr := range test.SLO {
+			a, err := parseSLO(test.Name, expr)
+			if err != nil {
+				return nil, err
+			}
+			slos = append(slos, a)
+		}
+	}
+
+	for _, s := range conf.SLO {
+		target, expr, ok := strings.Cut(s, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid slo %q, expected \"<test name>: <assertion>\"", s)
+		}
+		target = strings.TrimSpace(target)
+		// Replayed requests are grouped into targets once the file is read,
+		// so SLOs can't name them
+		if conf.ReplayFile != "" && target != sloTotal {
+			return nil, fmt.Errorf("invalid slo %q, replay_file only supports assertions on %q", s, sloTotal)
+		}
+		if !targets[target] {
+			return nil, fmt.Errorf("invalid slo %q, no test named %q", s, target)
+		}
+		a, err := parseSLO(target, expr)
+		if err != nil {
+			return nil, err
+		}
+		slos = append(slos, a)
+	}
+	return slos, nil
+}
+
+// value returns the asserted metric of m
+func (a *sloAssertion) value(m *vegeta.Metrics) float64 {
+	return metricValue(m, a.metric)
+}
+
+// metricValue returns the named metric of m, which must be one of
+// sloMetrics
+func metricValue(m *vegeta.Metrics, metric string) float64 {
+	switch metric {
+	case "mean":
+		return float64(m.Latencies.Mean)
+	case "p50":
+		return float64(m.Latencies.P50)
+	case "p90":
+		return float64(m.Latencies.P90)
+	case "p95":
+		return float64(m.Latencies.P95)
+	case "p99":
+		return float64(m.Latencies.P99)
+	case "max":
+		return float64(m.Latencies.Max)
+	case "success_ratio":
+		return m.Success
+	case "rate":
+		return m.Rate
+	default:
+		return m.Throughput
+	}
+}
+
+// check evaluates the assertion against the report, returning the actual
+// value of the metric and whether the assertion holds
+func (a *sloAssertion) check(rpt *benchmarktests.Reporter) (float64, bool) {
+	m, ok := rpt.Metrics(a.target)
+	if !ok {
+		return 0, false
+	}
+
+	actual := a.value(m)
+	switch a.op {
+	case "<":
+		return actual, actual < a.threshold
+	case "<=":
+		return actual, actual <= a.threshold
+	case ">":
+		return actual, actual > a.threshold
+	default:
+		return actual, actual >= a.threshold
+	}
+}
+
+// format formats a value of the asserted metric
+func (a *sloAssertion) format(v float64) string {
+	if a.latency {
+		return time.Duration(v).String()
+	}
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
+
+// miss returns how far the actual value is from meeting the assertion
+func (a *sloAssertion) miss(actual float64) string {
+	return a.format(math.Abs(actual - a.threshold))
+}
+
+func (a *sloAssertion) String() string {
+	return fmt.Sprintf("%s %s %s", a.metric, a.op, a.format(a.threshold))
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openbao/benchmark-openbao/benchmarktests"
+	vbConfig "github.com/openbao/benchmark-openbao/config"
+)
+
+func TestSLOCheck(t *testing.T) {
+	const report = `{"metrics":{"kvv2_read_test":{"latencies":{"99th":72000000},"success":0.998},"total":{"latencies":{"99th":72000000},"success":0.998}},"target_addr":"http://localhost:8200"}`
+	reports, err := benchmarktests.FromReader(strings.NewReader(report))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	conf := vbConfig.NewVaultBenchmarkCoreConfig()
+	conf.Tests = []*benchmarktests.BenchmarkTarget{{Name: "kvv2_read_test", SLO: []string{"p99 < 50ms", "p99 <= 100ms"}}}
+	conf.SLO = []string{"total: success_ratio > 0.999"}
+	slos, err := parseSLOs(conf)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	expected := []struct {
+		ok   bool
+		miss string
+	}{
+		{false, "22ms"},
+		{true, "28ms"},
+		{false, "0.001"},
+	}
+	for i, slo := range slos {
+		actual, ok := slo.check(reports[0])
+		if ok != expected[i].ok || slo.miss(actual) != expected[i].miss {
+			t.Fatalf("%v: expected ok=%v, miss=%v, got ok=%v, miss=%v", slo, expected[i].ok, expected[i].miss, ok, slo.miss(actual))
+		}
+	}
+}
+
+func TestParseSLOs_Invalid(t *testing.T) {
+	for _, slo := range []string{"p99 < 50ms", "missing_test: p99 < 50ms", "total: p42 < 50ms", "total: p99 < fast", "total: success_ratio = 1"} {
+		conf := vbConfig.NewVaultBenchmarkCoreConfig()
+		conf.SLO = []string{slo}
+		if _, err := parseSLOs(conf); err == nil {
+			t.Fatalf("expected error parsing %q", slo)
+		}
+	}
+
+	// Replayed requests are only grouped into targets once the file is read
+	conf := vbConfig.NewVaultBenchmarkCoreConfig()
+	conf.ReplayFile = "traffic.har"
+	conf.SLO = []string{"GET /v1/secret/data: p99 < 50ms"}
+	if _, err := parseSLOs(conf); err == nil {
+		t.Fatal("expected error parsing a per-target slo with replay_file")
+	}
+}
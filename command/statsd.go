@@ -0,0 +1,76 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/openbao/benchmark-openbao/benchmarktests"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+var _ benchmarktests.ResultConsumer = (*statsdConsumer)(nil)
+
+// statsdConsumer emits the latency and outcome of every request to a statsd
+// server. With tags enabled the test name and status code are sent as
+// DogStatsD tags, otherwise they are part of the metric names so that plain
+// statsd servers can tell tests apart.
+type statsdConsumer struct {
+	client *statsd.Client
+	tags   bool
+}
+
+// newStatsdConsumer returns a consumer which sends metrics to the statsd
+// server at addr, prefixing every metric name with prefix
+func newStatsdConsumer(addr, prefix string, tags bool) (*statsdConsumer, error) {
+	client, err := statsd.New(addr,
+		statsd.WithNamespace(prefix),
+		statsd.WithoutTelemetry(),
+		statsd.WithoutOriginDetection(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating statsd client: %v", err)
+	}
+	return &statsdConsumer{client: client, tags: tags}, nil
+}
+
+func (s *statsdConsumer) Consume(target string, result *vegeta.Result, failed bool) {
+	if target == "" {
+		target = "unknown"
+	}
+	code := strconv.Itoa(int(result.Code))
+
+	// Send errors are dropped; metrics are best effort and must not slow
+	// down the attack
+	if s.tags {
+		tags := []string{"test:" + target, "status:" + code}
+		_ = s.client.Timing("request.latency", result.Latency, tags, 1)
+		_ = s.client.Incr("request.count", tags, 1)
+		if failed {
+			_ = s.client.Incr("request.errors", tags, 1)
+		}
+		return
+	}
+
+	name := statsdSanitize(target)
+	_ = s.client.Timing(name+".latency", result.Latency, nil, 1)
+	_ = s.client.Incr(name+".status."+code, nil, 1)
+	if failed {
+		_ = s.client.Incr(name+".errors", nil, 1)
+	}
+}
+
+// Close flushes any buffered metrics
+func (s *statsdConsumer) Close() error {
+	return s.client.Close()
+}
+
+// statsdSanitize replaces the characters which are reserved by the statsd
+// protocol in a metric name
+func statsdSanitize(name string) string {
+	return strings.NewReplacer(":", "_", "|", "_", "@", "_", "#", "_", ",", "_", " ", "_").Replace(name)
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+func TestStatsdConsumer(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer conn.Close()
+
+	consumer, err := newStatsdConsumer(conn.LocalAddr().String(), "bench", false)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	consumer.Consume("kvv2 read", &vegeta.Result{Code: 500, Latency: 5 * time.Millisecond}, true)
+	if err := consumer.Close(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	var received strings.Builder
+	buf := make([]byte, 65536)
+	for {
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			break
+		}
+		received.Write(buf[:n])
+		received.WriteString("\n")
+	}
+
+	for _, expected := range []string{"bench.kvv2_read.latency:5", "bench.kvv2_read.status.500:1|c", "bench.kvv2_read.errors:1|c"} {
+		if !strings.Contains(received.String(), expected) {
+			t.Fatalf("expected %q to be sent, got: %q", expected, received.String())
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openbao/benchmark-openbao/benchmarktests"
+)
+
+// filterTests returns the tests with at least one of the passed in tags,
+// which may each be a comma-separated list. The weights of the tests sharing
+// the global attacker are scaled back up to 100, so that a subset of a suite
+// can be run without editing its weights.
+func filterTests(tests []*benchmarktests.BenchmarkTarget, filterTags []string) ([]*benchmarktests.BenchmarkTarget, error) {
+	wanted := make(map[string]bool)
+	for _, tags := range filterTags {
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				wanted[tag] = true
+			}
+		}
+	}
+	if len(wanted) == 0 {
+		return tests, nil
+	}
+
+	var filtered []*benchmarktests.BenchmarkTarget
+	for _, test := range tests {
+		for _, tag := range test.Tags {
+			if wanted[tag] {
+				filtered = append(filtered, test)
+				break
+			}
+		}
+	}
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("no tests have any of the tags %v", strings.Join(filterTags, ","))
+	}
+
+	benchmarktests.RebalanceWeights(filtered)
+	return filtered, nil
+}
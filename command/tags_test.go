@@ -0,0 +1,43 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"testing"
+
+	"github.com/openbao/benchmark-openbao/benchmarktests"
+)
+
+func TestFilterTests(t *testing.T) {
+	tests := func() []*benchmarktests.BenchmarkTarget {
+		return []*benchmarktests.BenchmarkTarget{
+			{Name: "kvv2_write", Weight: 30, Tags: []string{"kv", "write"}},
+			{Name: "kvv2_read", Weight: 50, Tags: []string{"kv", "read"}},
+			{Name: "transit_encrypt", Weight: 20, Tags: []string{"write"}},
+		}
+	}
+
+	filtered, err := filterTests(tests(), []string{"write"})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(filtered) != 2 || filtered[0].Name != "kvv2_write" || filtered[1].Name != "transit_encrypt" {
+		t.Fatalf("expected the write tests, got: %v", filtered)
+	}
+	if filtered[0].Weight != 60 || filtered[1].Weight != 40 {
+		t.Fatalf("expected weights to be rebalanced to 60 and 40, got: %d and %d", filtered[0].Weight, filtered[1].Weight)
+	}
+
+	filtered, err = filterTests(tests(), []string{"read,missing"})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].Weight != 100 {
+		t.Fatalf("expected only kvv2_read with weight 100, got: %v", filtered)
+	}
+
+	if _, err := filterTests(tests(), []string{"missing"}); err == nil {
+		t.Fatal("expected error when no tests match")
+	}
+}
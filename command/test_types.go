@@ -0,0 +1,78 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openbao/benchmark-openbao/benchmarktests"
+	vbConfig "github.com/openbao/benchmark-openbao/config"
+)
+
+// describeConfigError adds the valid test types, and the closest match if
+// there is one, to errors for unknown test types
+func describeConfigError(err error) error {
+	var typeErr *vbConfig.InvalidTestTypeError
+	if !errors.As(err, &typeErr) {
+		return err
+	}
+	return invalidTestTypeError(typeErr.Type)
+}
+
+// invalidTestTypeError builds an error for an unknown test type which lists
+// the valid test types and suggests the closest match, if there is one
+func invalidTestTypeError(testType string) error {
+	validTypes := make([]string, 0, len(benchmarktests.TestList))
+	for t := range benchmarktests.TestList {
+		validTypes = append(validTypes, t)
+	}
+	sort.Strings(validTypes)
+
+	suggestion := ""
+	bestDistance := maxSuggestionDistance(testType) + 1
+	for _, t := range validTypes {
+		if d := levenshtein(testType, t); d < bestDistance {
+			suggestion = t
+			bestDistance = d
+		}
+	}
+
+	msg := fmt.Sprintf("invalid test type found: %v", testType)
+	if suggestion != "" {
+		msg += fmt.Sprintf(" (did you mean %q?)", suggestion)
+	}
+	return fmt.Errorf("%s; valid test types are: %s", msg, strings.Join(validTypes, ", "))
+}
+
+// maxSuggestionDistance is the most edits a test type may be from an unknown
+// one to be suggested for it. A third of the name's length allows for a typo
+// or two in most names, and the extra edit lets short names have one too,
+// while types which merely share a prefix with the name aren't suggested.
+func maxSuggestionDistance(testType string) int {
+	return len(testType)/3 + 1
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
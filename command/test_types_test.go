@@ -0,0 +1,50 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	vbConfig "github.com/openbao/benchmark-openbao/config"
+)
+
+func TestDescribeConfigError(t *testing.T) {
+	conf := vbConfig.NewVaultBenchmarkCoreConfig()
+	err := vbConfig.ParseConfig([]byte(`test "kvv2_raed" "kv" {}`), "test", conf)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	err = describeConfigError(err)
+	if !strings.Contains(err.Error(), `did you mean "kvv2_read"?`) {
+		t.Errorf("expected suggestion in error: %s", err.Error())
+	}
+	if !strings.Contains(err.Error(), "approle_auth") {
+		t.Errorf("expected valid test types in error: %s", err.Error())
+	}
+
+	// Types which aren't close to any test type get no suggestion
+	if err := invalidTestTypeError("nope"); strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("unexpected suggestion in error: %s", err.Error())
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"kvv2_read", "kvv2_read", 0},
+		{"kvv2_raed", "kvv2_read", 2},
+		{"kvv1_read", "kvv2_read", 1},
+		{"", "abc", 3},
+	}
+	for _, c := range cases {
+		if got := levenshtein(c.a, c.b); got != c.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"text/tabwriter"
+	"time"
+
+	"github.com/openbao/benchmark-openbao/benchmarktests"
+	vaultapi "github.com/openbao/openbao/api/v2"
+)
+
+// newHandshakeClient returns a copy of client which opens a new connection,
+// and so makes a new TLS handshake, for every request
+func newHandshakeClient(client *vaultapi.Client) (*vaultapi.Client, error) {
+	cfg := client.CloneConfig()
+	transport, ok := cfg.HttpClient.Transport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unsupported transport %T", cfg.HttpClient.Transport)
+	}
+	transport = transport.Clone()
+	transport.DisableKeepAlives = true
+
+	httpClient := *cfg.HttpClient
+	httpClient.Transport = transport
+	cfg.HttpClient = &httpClient
+
+	handshakeClient, err := vaultapi.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	handshakeClient.SetToken(client.Token())
+	handshakeClient.SetHeaders(client.Headers())
+	return handshakeClient, nil
+}
+
+// handshakeCost compares the latencies of a target's requests over reused
+// connections with those over new connections
+type handshakeCost struct {
+	ReusedMean time.Duration `json:"reused_mean"`
+	NewMean    time.Duration `json:"new_mean"`
+	MeanDelta  time.Duration `json:"mean_delta"`
+	ReusedP99  time.Duration `json:"reused_99th"`
+	NewP99     time.Duration `json:"new_99th"`
+	P99Delta   time.Duration `json:"99th_delta"`
+}
+
+// compareHandshakes returns the cost of a new connection for every target
+// attacked both with reused and with new connections
+func compareHandshakes(reused, fresh *benchmarktests.Reporter) map[string]*handshakeCost {
+	costs := make(map[string]*handshakeCost)
+	for _, name := range reused.MetricNames() {
+		r, _ := reused.Metrics(name)
+		n, ok := fresh.Metrics(name)
+		if !ok || r.Requests == 0 || n.Requests == 0 {
+			continue
+		}
+		costs[name] = &handshakeCost{
+			ReusedMean: r.Latencies.Mean,
+			NewMean:    n.Latencies.Mean,
+			MeanDelta:  n.Latencies.Mean - r.Latencies.Mean,
+			ReusedP99:  r.Latencies.P99,
+			NewP99:     n.Latencies.P99,
+			P99Delta:   n.Latencies.P99 - r.Latencies.P99,
+		}
+	}
+	return costs
+}
+
+// reportHandshakes reports the cost of a new connection for each target of
+// the address, in the given report mode
+func reportHandshakes(w io.Writer, mode, addr string, reused, fresh *benchmarktests.Reporter) error {
+	costs := compareHandshakes(reused, fresh)
+	if mode == "json" {
+		return json.NewEncoder(w).Encode(&struct {
+			Type         string                    `json:"type"`
+			TargetAddr   string                    `json:"target_addr"`
+			TLSHandshake map[string]*handshakeCost `json:"tls_handshake"`
+		}{
+			Type:         "tls_handshake",
+			TargetAddr:   addr,
+			TLSHandshake: costs,
+		})
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.StripEscape)
+	fmt.Fprintf(tw, "TLS handshake cost: %v\n", addr)
+	fmt.Fprintf(tw, "op\treused mean\tnew mean\tdelta\treused 99th%%\tnew 99th%%\tdelta\n")
+	for _, name := range reused.MetricNames() {
+		c, ok := costs[name]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", name, c.ReusedMean, c.NewMean, c.MeanDelta, c.ReusedP99, c.NewP99, c.P99Delta)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openbao/benchmark-openbao/benchmarktests"
+	vaultapi "github.com/openbao/openbao/api/v2"
+)
+
+func TestCompareHandshakes(t *testing.T) {
+	reports, err := benchmarktests.FromReader(strings.NewReader(`
+{"target_addr": "https://127.0.0.1:8200", "metrics": {"read": {"requests": 10, "latencies": {"mean": 2000000, "99th": 5000000}}}}
+{"target_addr": "https://127.0.0.1:8200", "metrics": {"read": {"requests": 10, "latencies": {"mean": 7000000, "99th": 12000000}}}}
+`))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	costs := compareHandshakes(reports[0], reports[1])
+	c, ok := costs["read"]
+	if !ok {
+		t.Fatalf("expected the cost of read, got: %v", costs)
+	}
+	if c.MeanDelta != 5*time.Millisecond || c.P99Delta != 7*time.Millisecond {
+		t.Fatalf("unexpected cost: %+v", c)
+	}
+}
+
+func TestNewHandshakeClient(t *testing.T) {
+	client, err := vaultapi.NewClient(vaultapi.DefaultConfig())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	client.SetToken("token")
+
+	handshakeClient, err := newHandshakeClient(client)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !handshakeClient.CloneConfig().HttpClient.Transport.(*http.Transport).DisableKeepAlives {
+		t.Fatal("expected keep-alive to be disabled")
+	}
+	if client.CloneConfig().HttpClient.Transport.(*http.Transport).DisableKeepAlives {
+		t.Fatal("expected the original client to keep reusing connections")
+	}
+	if handshakeClient.Token() != "token" {
+		t.Fatalf("expected the token to be kept, got %q", handshakeClient.Token())
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-cleanhttp"
+	vaultapi "github.com/openbao/openbao/api/v2"
+	"golang.org/x/net/http2"
+)
+
+// isUnixAddr returns true if addr is the address of a unix domain socket, such
+// as unix:///run/openbao/proxy.sock. The Vault client dials the socket for
+// requests to such addresses, which are then made to http://localhost.
+func isUnixAddr(addr string) bool {
+	return strings.HasPrefix(addr, "unix://")
+}
+
+// newHTTP2Transport returns a transport which only speaks HTTP/2. For
+// plain text addresses HTTP/2 is used without TLS (h2c).
+func newHTTP2Transport(addr string, tlsConfig *tls.Config) *http2.Transport {
+	transport := &http2.Transport{
+		TLSClientConfig: tlsConfig,
+	}
+	if strings.HasPrefix(addr, "http://") {
+		transport.AllowHTTP = true
+		transport.DialTLSContext = func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		}
+	}
+	return transport
+}
+
+// newSetupClient returns a copy of client with its own connection pool of at
+// most maxConns connections to each address, so that setting up and cleaning
+// up tests doesn't compete with the attack for connections
+func newSetupClient(client *vaultapi.Client, maxConns int) (*vaultapi.Client, error) {
+	cfg := client.CloneConfig()
+	transport, ok := cfg.HttpClient.Transport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unsupported transport %T", cfg.HttpClient.Transport)
+	}
+	transport = transport.Clone()
+	transport.MaxConnsPerHost = maxConns
+	transport.MaxIdleConnsPerHost = maxConns
+	if transport.MaxIdleConns != 0 && transport.MaxIdleConns < maxConns {
+		transport.MaxIdleConns = maxConns
+	}
+
+	httpClient := *cfg.HttpClient
+	httpClient.Transport = transport
+	cfg.HttpClient = &httpClient
+
+	setupClient, err := vaultapi.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	setupClient.SetToken(client.Token())
+	setupClient.SetHeaders(client.Headers())
+	return setupClient, nil
+}
+
+// newAgentClient returns a copy of client which sends its requests to the
+// OpenBao Agent or Proxy at addr, with the same TLS configuration, token and
+// headers
+func newAgentClient(client *vaultapi.Client, addr string) (*vaultapi.Client, error) {
+	cfg := client.CloneConfig()
+	transport, ok := cfg.HttpClient.Transport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unsupported transport %T", cfg.HttpClient.Transport)
+	}
+	transport = transport.Clone()
+
+	// The client may dial a unix socket, which the agent may not listen on
+	if !isUnixAddr(addr) {
+		transport.DialContext = cleanhttp.DefaultPooledTransport().DialContext
+	}
+
+	httpClient := *cfg.HttpClient
+	httpClient.Transport = transport
+	cfg.HttpClient = &httpClient
+	cfg.Address = addr
+
+	agentClient, err := vaultapi.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	agentClient.SetToken(client.Token())
+	agentClient.SetHeaders(client.Headers())
+	return agentClient, nil
+}
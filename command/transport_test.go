@@ -0,0 +1,78 @@
+// Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	vaultapi "github.com/openbao/openbao/api/v2"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+func TestUnixSocketClient(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "bao.sock")
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets not supported: %v", err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})}
+	go server.Serve(ln)
+	defer server.Close()
+
+	cfg := vaultapi.DefaultConfig()
+	cfg.Address = "unix://" + socket
+	client, err := vaultapi.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Attacks use a copy of the client's HTTP client, which has to keep
+	// dialing the socket
+	attacker := vegeta.NewAttacker(vegeta.Client(client.CloneConfig().HttpClient))
+	targeter := vegeta.NewStaticTargeter(vegeta.Target{Method: "GET", URL: client.Address() + "/v1/sys/health"})
+	for result := range attacker.Attack(targeter, vegeta.Rate{Freq: 10, Per: time.Second}, 200*time.Millisecond, "unix") {
+		if result.Code != http.StatusNoContent {
+			t.Fatalf("expected request over the socket to succeed, got %d: %v", result.Code, result.Error)
+		}
+	}
+}
+
+func TestNewAgentClient(t *testing.T) {
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer agent.Close()
+
+	// Vault is only reachable over a unix socket, which the agent client
+	// mustn't keep dialing
+	cfg := vaultapi.DefaultConfig()
+	cfg.Address = "unix://" + filepath.Join(t.TempDir(), "bao.sock")
+	client, err := vaultapi.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	client.SetToken("token")
+
+	agentClient, err := newAgentClient(client, agent.URL)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if agentClient.Address() != agent.URL {
+		t.Fatalf("expected address %v, got %v", agent.URL, agentClient.Address())
+	}
+	if _, err := agentClient.Logical().Read("sys/health"); err != nil {
+		t.Fatalf("expected request through the agent to succeed, got: %v", err)
+	}
+}
@@ -5,6 +5,7 @@ package config
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/hashicorp/hcl/v2"
@@ -21,11 +22,24 @@ const (
 	DefaultRandomMounts = true
 	DefaultCleanup      = false
 	DefaultLogLevel     = "INFO"
+	DefaultSetupRetries = 3
+	DefaultStatsdPrefix = "vault_benchmark"
+
+	DefaultNamespacePrefix = "benchmark-ns"
+
+	// StdinPath is the config path which reads the config from stdin
+	StdinPath = "-"
+
+	// stdinFilename names stdin in errors about its config
+	stdinFilename = "<stdin>"
 )
 
 type VaultBenchmarkCoreConfig struct {
 	Remain           hcl.Body                          `hcl:",remain"`
 	VaultAddr        string                            `hcl:"vault_addr,optional"`
+	VaultAddrs       string                            `hcl:"vault_addrs,optional"`
+	AgentAddr        string                            `hcl:"agent_addr,optional"`
+	LoadBalance      string                            `hcl:"load_balance,optional"`
 	VaultToken       string                            `hcl:"vault_token,optional"`
 	VaultNamespace   string                            `hcl:"vault_namespace,optional"`
 	Duration         string                            `hcl:"duration,optional"`
@@ -35,48 +49,199 @@ type VaultBenchmarkCoreConfig struct {
 	ClusterJSON      string                            `hcl:"cluster_json,optional"`
 	CAPEMFile        string                            `hcl:"ca_pem_file,optional"`
 	PPROFInterval    string                            `hcl:"pprof_interval,optional"`
+	PPROFAddr        string                            `hcl:"pprof_addr,optional"`
+	WaitForReady     string                            `hcl:"wait_for_ready,optional"`
+	OTLPEndpoint     string                            `hcl:"otlp_endpoint,optional"`
+	StatsdAddr       string                            `hcl:"statsd_addr,optional"`
+	HistogramFile    string                            `hcl:"histogram_file,optional"`
+	ReplayFile       string                            `hcl:"replay_file,optional"`
+	ReplayFormat     string                            `hcl:"replay_format,optional"`
+	ResultsFile      string                            `hcl:"results_file,optional"`
+	LeaderListen     string                            `hcl:"leader_listen,optional"`
+	LeaderAddr       string                            `hcl:"leader_addr,optional"`
+	StatsdPrefix     string                            `hcl:"statsd_prefix,optional"`
 	LogLevel         string                            `hcl:"log_level,optional"`
+	RampDuration     string                            `hcl:"ramp_duration,optional"`
+	RequestTimeout   string                            `hcl:"request_timeout,optional"`
+	ProgressInterval string                            `hcl:"progress_interval,optional"`
+	MemoryInterval   string                            `hcl:"memory_sample_interval,optional"`
+	Period           string                            `hcl:"period,optional"`
+	ThinkTime        string                            `hcl:"think_time,optional"`
+	TLS              *TLSConfig                        `hcl:"tls,block"`
+	Namespaces       *NamespacesConfig                 `hcl:"namespaces,block"`
+	Audit            *AuditConfig                      `hcl:"audit,block"`
 	Tests            []*benchmarktests.BenchmarkTarget `hcl:"test,block"`
+	SLO              []string                          `hcl:"slo,optional"`
+	FilterTags       []string                          `hcl:"filter_tags,optional"`
 	RPS              int                               `hcl:"rps,optional"`
 	Workers          int                               `hcl:"workers,optional"`
+	MaxWorkers       int                               `hcl:"max_workers,optional"`
+	GOMAXPROCS       int                               `hcl:"gomaxprocs,optional"`
+	Requests         int                               `hcl:"requests,optional"`
+	Runs             int                               `hcl:"runs,optional"`
+	MaxIdleConns     int                               `hcl:"max_idle_conns_per_host,optional"`
+	RampStart        int                               `hcl:"ramp_start,optional"`
+	RampEnd          int                               `hcl:"ramp_end,optional"`
+	MeanRate         int                               `hcl:"mean_rate,optional"`
+	Amplitude        int                               `hcl:"amplitude,optional"`
+	SetupRetries     int                               `hcl:"setup_retries,optional"`
+	SetupMaxConns    int                               `hcl:"setup_max_conns,optional"`
+	ErrorBodies      int                               `hcl:"error_bodies,optional"`
+	Seed             int                               `hcl:"seed,optional"`
+	Followers        int                               `hcl:"followers,optional"`
 	RandomMounts     bool                              `hcl:"random_mounts,optional"`
 	InputResults     bool                              `hcl:"input_results,optional"`
 	Cleanup          bool                              `hcl:"cleanup,optional"`
+	SkipSetup        bool                              `hcl:"skip_setup,optional"`
+	SkipCleanup      bool                              `hcl:"skip_cleanup,optional"`
 	Debug            bool                              `hcl:"debug,optional"`
 	DisableHTTP2     bool                              `hcl:"disable_http2,optional"`
 	DisableKeepAlive bool                              `hcl:"disable_keep_alive,optional"`
+	DisableCompress  bool                              `hcl:"disable_compression,optional"`
+	ForceHTTP2       bool                              `hcl:"force_http2,optional"`
+	StatsdTags       bool                              `hcl:"statsd_tags,optional"`
+	CompareTLS       bool                              `hcl:"compare_tls_handshake,optional"`
+}
+
+// TLSConfig configures TLS for connections to Vault, both during setup and
+// for the benchmark requests themselves
+type TLSConfig struct {
+	CACert        string `hcl:"ca_cert,optional"`
+	CAPath        string `hcl:"ca_path,optional"`
+	ClientCert    string `hcl:"client_cert,optional"`
+	ClientKey     string `hcl:"client_key,optional"`
+	TLSServerName string `hcl:"tls_server_name,optional"`
+	TLSSkipVerify bool   `hcl:"tls_skip_verify,optional"`
+}
+
+// NamespacesConfig configures the namespaces that benchmark load is spread
+// across. Either an explicit list of names or a count of namespaces to
+// generate may be given.
+type NamespacesConfig struct {
+	Names  []string `hcl:"names,optional"`
+	Count  int      `hcl:"count,optional"`
+	Prefix string   `hcl:"prefix,optional"`
+}
+
+// List returns the names of the configured namespaces
+func (n *NamespacesConfig) List() ([]string, error) {
+	switch {
+	case len(n.Names) > 0 && n.Count != 0:
+		return nil, fmt.Errorf("only one of namespaces names or count can be set")
+	case len(n.Names) > 0:
+		return n.Names, nil
+	case n.Count <= 0:
+		return nil, fmt.Errorf("namespaces count must be greater than 0")
+	}
+
+	prefix := n.Prefix
+	if prefix == "" {
+		prefix = DefaultNamespacePrefix
+	}
+	names := make([]string, n.Count)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s-%d", prefix, i)
+	}
+	return names, nil
+}
+
+// AuditConfig configures an audit device which is enabled while the
+// benchmark runs, to measure the cost of auditing
+type AuditConfig struct {
+	Type       string `hcl:"type,optional"`
+	FilePath   string `hcl:"file_path,optional"`
+	Address    string `hcl:"address,optional"`
+	SocketType string `hcl:"socket_type,optional"`
+}
+
+// Device returns the configured audit device
+func (a *AuditConfig) Device() (*benchmarktests.AuditDevice, error) {
+	switch a.Type {
+	case "", "file":
+		if a.FilePath == "" {
+			return nil, fmt.Errorf("file_path must be set for a file audit device")
+		}
+		return &benchmarktests.AuditDevice{
+			Type:    "file",
+			Options: map[string]string{"file_path": a.FilePath},
+		}, nil
+	case "socket":
+		if a.Address == "" {
+			return nil, fmt.Errorf("address must be set for a socket audit device")
+		}
+		socketType := a.SocketType
+		if socketType == "" {
+			socketType = "tcp"
+		}
+		return &benchmarktests.AuditDevice{
+			Type:    "socket",
+			Options: map[string]string{"address": a.Address, "socket_type": socketType},
+		}, nil
+	default:
+		return nil, fmt.Errorf("audit type must be one of file or socket")
+	}
 }
 
 func NewVaultBenchmarkCoreConfig() *VaultBenchmarkCoreConfig {
-	// Default Vault Benchmark Config Values
+	// Default Vault Benchmark Config Values. Duration is left unset so an
+	// explicitly configured duration can be told apart from DefaultDuration.
 	return &VaultBenchmarkCoreConfig{
 		Workers:      DefaultWorkers,
 		RPS:          DefaultRPS,
-		Duration:     DefaultDuration,
 		ReportMode:   DefaultReportMode,
 		RandomMounts: DefaultRandomMounts,
 		Cleanup:      DefaultCleanup,
 		LogLevel:     DefaultLogLevel,
+		SetupRetries: DefaultSetupRetries,
+		StatsdPrefix: DefaultStatsdPrefix,
 	}
 }
 
 // LoadConfig populates a VaultBenchmarkCoreConfig struct from the
 // passed in HCL config file
 func (c *VaultBenchmarkCoreConfig) LoadConfig(path string) error {
-	var fileBuf []byte
+	return c.LoadConfigs([]string{path}, os.Stdin)
+}
+
+// LoadConfigs populates a VaultBenchmarkCoreConfig struct from the passed in
+// HCL config files, merging their tests into one list. A path of "-" reads a
+// config from stdin.
+func (c *VaultBenchmarkCoreConfig) LoadConfigs(paths []string, stdin io.Reader) error {
+	parser := hclparse.NewParser()
+	files := make([]*hcl.File, 0, len(paths))
+	readStdin := false
+	for _, path := range paths {
+		var fileBuf []byte
+		var err error
+		switch {
+		case path == StdinPath && readStdin:
+			return fmt.Errorf("config can only be read from stdin once")
+		case path == StdinPath:
+			readStdin = true
+			path = stdinFilename
+			fileBuf, err = io.ReadAll(stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read stdin: %v", err)
+			}
+		default:
+			// File Validity checking
+			if ok, err := benchmarktests.IsFile(path); !ok {
+				return fmt.Errorf("failed to open file: %v", err)
+			}
+			fileBuf, err = os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to open file: %v", err)
+			}
+		}
 
-	// File Validity checking
-	if ok, err := benchmarktests.IsFile(path); ok {
-		fileBuf, err = os.ReadFile(path)
-		if err != nil {
-			return fmt.Errorf("failed to open file: %v", err)
+		confFile, confDiags := parser.ParseHCL(fileBuf, path)
+		if confDiags.HasErrors() {
+			return fmt.Errorf("failed to parse config: error parsing hcl: %v", confDiags)
 		}
-	} else {
-		return fmt.Errorf("failed to open file: %v", err)
+		files = append(files, confFile)
 	}
 
-	err := ParseConfig(fileBuf, path, c)
-	if err != nil {
+	if err := decodeConfig(hcl.MergeFiles(files), c); err != nil {
 		return fmt.Errorf("failed to parse config: %v", err)
 	}
 	return nil
@@ -89,11 +254,36 @@ func ParseConfig(hclBuf []byte, pathName string, configStruct *VaultBenchmarkCor
 	if confDiags.HasErrors() {
 		return fmt.Errorf("error parsing hcl: %v", confDiags)
 	}
+	return decodeConfig(confFile.Body, configStruct)
+}
+
+// InvalidTestTypeError is returned when a config references a test type which
+// isn't in benchmarktests.TestList
+type InvalidTestTypeError struct {
+	Type string
+}
+
+func (e *InvalidTestTypeError) Error() string {
+	return fmt.Sprintf("invalid test type found: %v", e.Type)
+}
 
+// decodeConfig decodes a parsed HCL body, which may be several files merged
+// together, into the core config and parses the config of each test
+func decodeConfig(body hcl.Body, configStruct *VaultBenchmarkCoreConfig) error {
 	// Decode HCL Body into Core Config Struct
-	moreDiags := gohcl.DecodeBody(confFile.Body, nil, configStruct)
+	moreDiags := gohcl.DecodeBody(body, benchmarktests.EvalContext(), configStruct)
 	if moreDiags.HasErrors() {
-		return fmt.Errorf("error decoding hcl: %v", confDiags)
+		return fmt.Errorf("error decoding hcl: %v", moreDiags)
+	}
+
+	// Test names identify tests in reports, so they must be unique across
+	// every config file
+	seen := make(map[string]bool, len(configStruct.Tests))
+	for _, vbTest := range configStruct.Tests {
+		if seen[vbTest.Name] {
+			return fmt.Errorf("duplicate test name %q, test names must be unique across every config file", vbTest.Name)
+		}
+		seen[vbTest.Name] = true
 	}
 
 	// Check to see if we have more than one Cert auth and fail if we do
@@ -112,7 +302,7 @@ func ParseConfig(hclBuf []byte, pathName string, configStruct *VaultBenchmarkCor
 			}
 			vbTest.Builder = currBuilder
 		} else {
-			return fmt.Errorf("invalid test type found: %v", vbTest.Type)
+			return &InvalidTestTypeError{Type: vbTest.Type}
 		}
 	}
 	return nil
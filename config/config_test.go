@@ -4,6 +4,8 @@
 package config
 
 import (
+	"errors"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -40,6 +42,76 @@ func TestLoadConfig_NoExist(t *testing.T) {
 	}
 }
 
+func TestLoadConfigs_Merge(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.hcl")
+	err := os.WriteFile(base, []byte(`
+workers = 5
+test "kvv2_read" "kvv2_read_test" {
+	weight = 50
+}
+`), 0o600)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	stdin := strings.NewReader(`
+test "kvv2_write" "kvv2_write_test" {
+	weight = 50
+}
+`)
+
+	conf := NewVaultBenchmarkCoreConfig()
+	if err := conf.LoadConfigs([]string{base, StdinPath}, stdin); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if conf.Workers != 5 {
+		t.Fatalf("expected 5 workers, got: %d", conf.Workers)
+	}
+	if len(conf.Tests) != 2 || conf.Tests[0].Name != "kvv2_read_test" || conf.Tests[1].Name != "kvv2_write_test" {
+		t.Fatalf("expected tests of both files to be merged, got: %v", conf.Tests)
+	}
+
+	dup := filepath.Join(dir, "dup.hcl")
+	if err := os.WriteFile(dup, []byte(`test "kvv2_list" "kvv2_read_test" {}`), 0o600); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	conf = NewVaultBenchmarkCoreConfig()
+	err = conf.LoadConfigs([]string{base, dup}, nil)
+	if err == nil || !strings.Contains(err.Error(), `duplicate test name "kvv2_read_test"`) {
+		t.Fatalf("expected duplicate test name error, got: %v", err)
+	}
+
+	conf = NewVaultBenchmarkCoreConfig()
+	if err := conf.LoadConfigs([]string{StdinPath, StdinPath}, strings.NewReader("")); err == nil {
+		t.Fatal("expected error reading stdin twice")
+	}
+}
+
+func TestParseConfig_Env(t *testing.T) {
+	t.Setenv("BENCHMARK_TEST_ADDR", "http://vault:8200")
+	t.Setenv("BENCHMARK_TEST_WORKERS", "7")
+
+	conf := NewVaultBenchmarkCoreConfig()
+	err := ParseConfig([]byte(`
+vault_addr  = env("BENCHMARK_TEST_ADDR")
+workers     = env.BENCHMARK_TEST_WORKERS
+report_mode = "${env("BENCHMARK_TEST_WORKERS")}-json"
+`), "test", conf)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if conf.VaultAddr != "http://vault:8200" || conf.Workers != 7 || conf.ReportMode != "7-json" {
+		t.Fatalf("unexpected config: %+v", conf)
+	}
+
+	for _, hcl := range []string{`vault_addr = env("BENCHMARK_TEST_UNSET")`, `vault_addr = env.BENCHMARK_TEST_UNSET`} {
+		conf = NewVaultBenchmarkCoreConfig()
+		if err := ParseConfig([]byte(hcl), "test", conf); err == nil {
+			t.Fatalf("expected error for unset environment variable: %s", hcl)
+		}
+	}
+}
+
 func TestParseConfig_InvalidTest(t *testing.T) {
 	conf := NewVaultBenchmarkCoreConfig()
 	err := ParseConfig([]byte(`test "invalid" "nope" {this="invalid"}`), "test", conf)
@@ -49,6 +121,10 @@ func TestParseConfig_InvalidTest(t *testing.T) {
 	if !strings.Contains(err.Error(), `invalid test type found: invalid`) {
 		t.Errorf("bad error: %s", err.Error())
 	}
+	var typeErr *InvalidTestTypeError
+	if !errors.As(err, &typeErr) || typeErr.Type != "invalid" {
+		t.Errorf("expected an InvalidTestTypeError, got: %#v", err)
+	}
 }
 
 func TestParseConfig_InvalidValueType(t *testing.T) {
@@ -58,3 +134,50 @@ func TestParseConfig_InvalidValueType(t *testing.T) {
 		t.Fatal("expected error")
 	}
 }
+
+func TestParseConfig_TLS(t *testing.T) {
+	conf := NewVaultBenchmarkCoreConfig()
+	err := ParseConfig([]byte(`
+tls {
+	ca_cert         = "/ca.pem"
+	tls_skip_verify = true
+}
+`), "test", conf)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if conf.TLS == nil || conf.TLS.CACert != "/ca.pem" || !conf.TLS.TLSSkipVerify {
+		t.Fatalf("unexpected tls config: %+v", conf.TLS)
+	}
+}
+
+func TestNamespacesConfig_List(t *testing.T) {
+	names, err := (&NamespacesConfig{Count: 2, Prefix: "tenant"}).List()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(names) != 2 || names[0] != "tenant-0" || names[1] != "tenant-1" {
+		t.Fatalf("unexpected namespaces: %v", names)
+	}
+
+	if _, err := (&NamespacesConfig{Count: 2, Names: []string{"a"}}).List(); err == nil {
+		t.Fatal("expected error when both names and count are set")
+	}
+}
+
+func TestAuditConfig_Device(t *testing.T) {
+	device, err := (&AuditConfig{Type: "socket", Address: "127.0.0.1:9090"}).Device()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if device.Type != "socket" || device.Options["socket_type"] != "tcp" {
+		t.Fatalf("unexpected device: %v", device)
+	}
+
+	if _, err := (&AuditConfig{}).Device(); err == nil {
+		t.Fatal("expected error when file_path isn't set")
+	}
+	if _, err := (&AuditConfig{Type: "syslog"}).Device(); err == nil {
+		t.Fatal("expected error for unsupported type")
+	}
+}
@@ -0,0 +1,7 @@
+# Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+# SPDX-License-Identifier: MPL-2.0
+
+config {
+    by     = "name"
+    lookup = "group"
+}
@@ -0,0 +1,6 @@
+# Copyright (c) 2025 OpenBao a Series of LF Projects, LLC
+# SPDX-License-Identifier: MPL-2.0
+
+config {
+    by = "accessor"
+}